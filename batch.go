@@ -0,0 +1,257 @@
+package postmark
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// MaxBatchSize is the largest JSON payload of one batch request
+const MaxBatchSize = 50 << 20
+
+// ErrMessageTooLarge is returned for a message whose JSON encoding alone exceeds the batch size limit
+var ErrMessageTooLarge = errors.New("message exceeds the batch size limit")
+
+// BatchOptions configures how SendEmailBatches and SendTemplatedEmailBatches split and send messages
+type BatchOptions struct {
+	// MaxMessages is the largest number of messages per request, MaxBatchMessages by default
+	MaxMessages int
+	// MaxBytes is the largest JSON payload per request, MaxBatchSize by default
+	MaxBytes int
+	// Concurrency is the number of requests sent at the same time, 1 by default
+	Concurrency int
+}
+
+// withDefaults returns the options with zero values replaced by the defaults
+func (o *BatchOptions) withDefaults() BatchOptions {
+	opts := BatchOptions{}
+	if o != nil {
+		opts = *o
+	}
+	if opts.MaxMessages <= 0 || opts.MaxMessages > MaxBatchMessages {
+		opts.MaxMessages = MaxBatchMessages
+	}
+	if opts.MaxBytes <= 0 || opts.MaxBytes > MaxBatchSize {
+		opts.MaxBytes = MaxBatchSize
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 1
+	}
+	return opts
+}
+
+// BatchError lists the messages of SendEmailBatches or SendTemplatedEmailBatches that were not accepted
+type BatchError struct {
+	// Failed maps the index of every failed message in the input to its error
+	Failed map[int]error
+	// Total is the number of messages in the input
+	Total int
+}
+
+// Error summarizes the failed messages
+func (e BatchError) Error() string {
+	indexes := e.indexes()
+
+	const maxListed = 5
+	problems := make([]string, 0, maxListed)
+	for _, i := range indexes[:min(len(indexes), maxListed)] {
+		problems = append(problems, fmt.Sprintf("[%d] %s", i, e.Failed[i]))
+	}
+	if len(indexes) > maxListed {
+		problems = append(problems, fmt.Sprintf("and %d more", len(indexes)-maxListed))
+	}
+
+	return fmt.Sprintf("%d of %d messages failed: %s", len(e.Failed), e.Total, strings.Join(problems, "; "))
+}
+
+// Unwrap returns the errors of the failed messages, so errors.Is matches any of them
+func (e BatchError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Failed))
+	for _, i := range e.indexes() {
+		errs = append(errs, e.Failed[i])
+	}
+	return errs
+}
+
+// indexes returns the indexes of the failed messages in order
+func (e BatchError) indexes() []int {
+	indexes := make([]int, 0, len(e.Failed))
+	for i := range e.Failed {
+		indexes = append(indexes, i)
+	}
+	sort.Ints(indexes)
+	return indexes
+}
+
+// SendEmailBatches sends any number of emails, split into batches that respect Postmark's message count and payload
+// size limits. The responses are aligned index-for-index with emails. When some messages fail, the error is a
+// BatchError mapping their indexes to errors, and their responses carry the ErrorCode and Message.
+func (client *Client) SendEmailBatches(ctx context.Context, emails []Email, opts *BatchOptions) ([]EmailResponse, error) {
+	sender := batchSender[Email]{
+		client:   client,
+		opts:     opts.withDefaults(),
+		overhead: len(`[]`),
+		path:     "email/batch",
+		validate: Email.Validate,
+		to:       func(email Email) string { return email.To },
+		send:     client.SendEmailBatch,
+	}
+	return sender.run(ctx, emails)
+}
+
+// SendTemplatedEmailBatches sends any number of templated emails, split into batches like SendEmailBatches
+func (client *Client) SendTemplatedEmailBatches(ctx context.Context, emails []TemplatedEmail, opts *BatchOptions) ([]EmailResponse, error) {
+	sender := batchSender[TemplatedEmail]{
+		client:   client,
+		opts:     opts.withDefaults(),
+		overhead: len(`{"Messages":[]}`),
+		path:     "email/batchWithTemplates",
+		validate: TemplatedEmail.Validate,
+		to:       func(email TemplatedEmail) string { return email.To },
+		send:     client.SendTemplatedEmailBatch,
+	}
+	return sender.run(ctx, emails)
+}
+
+// batchSender splits messages into batches and sends them concurrently
+type batchSender[T any] struct {
+	client   *Client
+	opts     BatchOptions
+	overhead int
+	path     string
+	validate func(T) error
+	to       func(T) string
+	send     func(context.Context, []T) ([]EmailResponse, error)
+
+	mu        sync.Mutex
+	responses []EmailResponse
+	failed    map[int]error
+}
+
+// chunk is a batch of messages along with their indexes in the input
+type chunk[T any] struct {
+	indexes  []int
+	messages []T
+}
+
+// run sends the messages and collects their responses and errors
+func (b *batchSender[T]) run(ctx context.Context, messages []T) ([]EmailResponse, error) {
+	b.responses = make([]EmailResponse, len(messages))
+	b.failed = make(map[int]error)
+
+	chunks := b.split(messages)
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, b.opts.Concurrency)
+	for _, c := range chunks {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			b.fail(c, messages, ctx.Err())
+			continue
+		}
+
+		wg.Add(1)
+		go func(c chunk[T]) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			b.sendChunk(ctx, c, messages)
+		}(c)
+	}
+	wg.Wait()
+
+	if len(b.failed) > 0 {
+		return b.responses, BatchError{Failed: b.failed, Total: len(messages)}
+	}
+	return b.responses, nil
+}
+
+// split groups the messages into chunks within the count and size limits. Messages that fail validation (when
+// the client validates emails) or that cannot fit in any batch are marked as failed instead.
+func (b *batchSender[T]) split(messages []T) []chunk[T] {
+	var chunks []chunk[T]
+	var current chunk[T]
+	size := b.overhead
+
+	for i, message := range messages {
+		if b.client.ValidateEmails {
+			if err := b.validate(message); err != nil {
+				b.failMessage(i, b.to(message), err)
+				continue
+			}
+		}
+
+		data, err := json.Marshal(message)
+		if err != nil {
+			b.failMessage(i, b.to(message), err)
+			continue
+		}
+		if b.overhead+len(data) > b.opts.MaxBytes {
+			b.failMessage(i, b.to(message), fmt.Errorf("%w: %d bytes", ErrMessageTooLarge, len(data)))
+			continue
+		}
+
+		// Messages after the first one are separated by a comma
+		messageSize := len(data)
+		if len(current.messages) > 0 {
+			messageSize++
+		}
+		if len(current.messages) == b.opts.MaxMessages || size+messageSize > b.opts.MaxBytes {
+			chunks = append(chunks, current)
+			current, size, messageSize = chunk[T]{}, b.overhead, len(data)
+		}
+
+		current.indexes = append(current.indexes, i)
+		current.messages = append(current.messages, message)
+		size += messageSize
+	}
+
+	if len(current.messages) > 0 {
+		chunks = append(chunks, current)
+	}
+	return chunks
+}
+
+// sendChunk sends one batch and records the response of each of its messages
+func (b *batchSender[T]) sendChunk(ctx context.Context, c chunk[T], messages []T) {
+	res, err := b.send(ctx, c.messages)
+	if err != nil {
+		b.fail(c, messages, err)
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for n, i := range c.indexes {
+		if n >= len(res) {
+			b.failed[i] = APIError{Message: "missing from the batch response", StatusCode: http.StatusOK, Method: http.MethodPost, Path: b.path}
+			continue
+		}
+		b.responses[i] = res[n]
+		if res[n].ErrorCode != 0 {
+			b.failed[i] = APIError{ErrorCode: res[n].ErrorCode, Message: res[n].Message, StatusCode: http.StatusOK, Method: http.MethodPost, Path: b.path}
+		}
+	}
+}
+
+// fail marks every message of a chunk as failed with the same error
+func (b *batchSender[T]) fail(c chunk[T], messages []T, err error) {
+	for _, i := range c.indexes {
+		b.failMessage(i, b.to(messages[i]), err)
+	}
+}
+
+// failMessage marks a message as failed, its response carries the error code and message
+func (b *batchSender[T]) failMessage(i int, to string, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failed[i] = err
+	b.responses[i] = EmailResponse{To: to, ErrorCode: ErrorCode(err), Message: err.Error()}
+}
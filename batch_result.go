@@ -0,0 +1,188 @@
+package postmark
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+)
+
+// FailureKind classifies why a message of a batch was not accepted
+type FailureKind string
+
+// Failure kinds, derived from the Postmark ErrorCode of a message
+const (
+	// FailureNone is the kind of accepted messages
+	FailureNone FailureKind = ""
+	// FailureInactiveRecipient means every recipient bounced, complained or was suppressed (406)
+	FailureInactiveRecipient FailureKind = "InactiveRecipient"
+	// FailureInvalidAddress means an address could not be parsed by Validate (ErrInvalidAddress)
+	FailureInvalidAddress FailureKind = "InvalidAddress"
+	// FailureInvalidRequest means the message is invalid (300, 402, 403, 409, 410, 411) or failed Validate
+	FailureInvalidRequest FailureKind = "InvalidRequest"
+	// FailureSenderSignature means the From address has no confirmed sender signature (400, 401)
+	FailureSenderSignature FailureKind = "SenderSignature"
+	// FailureAccount means the account or server may not send (10, 405, 412, 413)
+	FailureAccount FailureKind = "Account"
+	// FailureTemplateNotFound means the template does not exist or is inactive (1101)
+	FailureTemplateNotFound FailureKind = "TemplateNotFound"
+	// FailureTransient means the message can be sent again later: maintenance, throttling, server or network errors
+	FailureTransient FailureKind = "Transient"
+	// FailureOther is any other failure, including local errors such as an undecodable response
+	FailureOther FailureKind = "Other"
+)
+
+// BatchItem is one message of a batch along with its outcome
+type BatchItem[T any] struct {
+	// Index is the position of the message in the batch
+	Index int
+	// Email is the message that was sent
+	Email T
+	// Response is the response of Postmark for the message, ErrorCode is set when the message was not sent
+	Response EmailResponse
+	// Err is the error of the message, nil when it was accepted
+	Err error
+}
+
+// Failure classifies the error of the message, FailureNone when it was accepted
+func (i BatchItem[T]) Failure() FailureKind {
+	return classifyFailure(i.Err)
+}
+
+// Retryable reports whether sending the message again may succeed
+func (i BatchItem[T]) Retryable() bool {
+	return i.Failure() == FailureTransient
+}
+
+// BatchResult pairs every message of a batch with its response, T is Email or TemplatedEmail
+type BatchResult[T any] struct {
+	Items []BatchItem[T]
+}
+
+// NewBatchResult pairs the messages of a batch with the responses and error returned by SendEmailBatch,
+// SendEmailBatches or their templated variants. A BatchError assigns errors to single messages, any other error
+// is assigned to every message without an error code in its response.
+func NewBatchResult[T any](emails []T, responses []EmailResponse, err error) BatchResult[T] {
+	var batchErr BatchError
+	isBatchErr := errors.As(err, &batchErr)
+
+	items := make([]BatchItem[T], len(emails))
+	for n, email := range emails {
+		item := BatchItem[T]{Index: n, Email: email}
+		if n < len(responses) {
+			item.Response = responses[n]
+		}
+
+		switch {
+		case isBatchErr:
+			item.Err = batchErr.Failed[n]
+		case item.Response.ErrorCode != 0:
+			item.Err = APIError{ErrorCode: item.Response.ErrorCode, Message: item.Response.Message, StatusCode: http.StatusOK}
+		case err != nil:
+			item.Err = err
+		}
+		if item.Err != nil && item.Response.ErrorCode == 0 && item.Response.Message == "" {
+			item.Response.ErrorCode = ErrorCode(item.Err)
+			item.Response.Message = item.Err.Error()
+		}
+
+		items[n] = item
+	}
+	return BatchResult[T]{Items: items}
+}
+
+// Succeeded returns the accepted messages
+func (r BatchResult[T]) Succeeded() []BatchItem[T] {
+	return r.filter(func(item BatchItem[T]) bool { return item.Err == nil })
+}
+
+// Failed returns the messages that were not accepted
+func (r BatchResult[T]) Failed() []BatchItem[T] {
+	return r.filter(func(item BatchItem[T]) bool { return item.Err != nil })
+}
+
+// Retryable returns the failed messages that may succeed when sent again
+func (r BatchResult[T]) Retryable() []BatchItem[T] {
+	return r.filter(BatchItem[T].Retryable)
+}
+
+// ByFailure groups the failed messages by kind
+func (r BatchResult[T]) ByFailure() map[FailureKind][]BatchItem[T] {
+	groups := make(map[FailureKind][]BatchItem[T])
+	for _, item := range r.Failed() {
+		groups[item.Failure()] = append(groups[item.Failure()], item)
+	}
+	return groups
+}
+
+// RetryBatch returns a follow-up batch containing only the retryable messages, in their original order
+func (r BatchResult[T]) RetryBatch() []T {
+	retryable := r.Retryable()
+	emails := make([]T, 0, len(retryable))
+	for _, item := range retryable {
+		emails = append(emails, item.Email)
+	}
+	return emails
+}
+
+// filter returns the items matching keep
+func (r BatchResult[T]) filter(keep func(BatchItem[T]) bool) []BatchItem[T] {
+	var items []BatchItem[T]
+	for _, item := range r.Items {
+		if keep(item) {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// classifyFailure returns the kind of failure of an error, from its ErrorCode or its type
+func classifyFailure(err error) FailureKind {
+	if err == nil {
+		return FailureNone
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return FailureTransient
+	}
+	if errors.Is(err, ErrInvalidAddress) {
+		return FailureInvalidAddress
+	}
+
+	var validationErr EmailValidationError
+	if errors.As(err, &validationErr) || errors.Is(err, ErrMessageTooLarge) || errors.Is(err, ErrHeaderInjection) {
+		return FailureInvalidRequest
+	}
+
+	var apiErr APIError
+	if !errors.As(err, &apiErr) {
+		// The request never got a response, other local errors will not go away by sending again
+		var urlErr *url.Error
+		var netErr net.Error
+		if errors.As(err, &urlErr) || errors.As(err, &netErr) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return FailureTransient
+		}
+		return FailureOther
+	}
+
+	policy := DefaultRetryPolicy()
+	switch apiErr.ErrorCode {
+	case ErrorCodeInactiveRecipient:
+		return FailureInactiveRecipient
+	case ErrorCodeInvalidEmailRequest, ErrorCodeInvalidJSON, ErrorCodeIncompatibleJSON, ErrorCodeJSONRequired,
+		ErrorCodeForbiddenAttachmentType, ErrorCodeTooManyBatchMessages:
+		return FailureInvalidRequest
+	case ErrorCodeSenderSignatureNotFound, ErrorCodeSenderSignatureNotConfirmed:
+		return FailureSenderSignature
+	case ErrorCodeInvalidAPIToken, ErrorCodeNotAllowedToSend, ErrorCodeAccountPending, ErrorCodeAccountMayNotSend:
+		return FailureAccount
+	case ErrorCodeTemplateNotFound:
+		return FailureTemplateNotFound
+	}
+	if slices.Contains(policy.RetryableErrorCodes, apiErr.ErrorCode) || slices.Contains(policy.RetryableStatusCodes, apiErr.StatusCode) {
+		return FailureTransient
+	}
+	return FailureOther
+}
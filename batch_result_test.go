@@ -0,0 +1,124 @@
+package postmark
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewBatchResult(t *testing.T) {
+	emails := []Email{
+		{To: "ok@example.com"},
+		{To: "inactive@example.com"},
+		{To: "bad"},
+		{To: "down@example.com"},
+	}
+	responses := []EmailResponse{
+		{To: "ok@example.com", MessageID: "1", Message: "OK"},
+		{To: "inactive@example.com", ErrorCode: ErrorCodeInactiveRecipient, Message: "Inactive recipient"},
+		{To: "bad", ErrorCode: ErrorCodeInvalidEmailRequest, Message: "Error parsing 'To': Illegal email address 'bad'."},
+		{To: "down@example.com", ErrorCode: ErrorCodeMaintenance, Message: "Maintenance"},
+	}
+
+	result := NewBatchResult(emails, responses, nil)
+
+	require.Len(t, result.Items, 4)
+	assert.Equal(t, "1", result.Items[0].Response.MessageID)
+	require.Len(t, result.Succeeded(), 1)
+	assert.Equal(t, "ok@example.com", result.Succeeded()[0].Email.To)
+	assert.Len(t, result.Failed(), 3)
+
+	assert.Equal(t, FailureNone, result.Items[0].Failure())
+	assert.Equal(t, FailureInactiveRecipient, result.Items[1].Failure())
+	assert.Equal(t, FailureInvalidRequest, result.Items[2].Failure())
+	assert.Equal(t, FailureTransient, result.Items[3].Failure())
+	require.ErrorIs(t, result.Items[1].Err, ErrInactiveRecipient)
+
+	groups := result.ByFailure()
+	assert.Len(t, groups, 3)
+	assert.Equal(t, 1, groups[FailureInactiveRecipient][0].Index)
+
+	assert.Equal(t, []Email{{To: "down@example.com"}}, result.RetryBatch())
+}
+
+func TestNewBatchResultRequestError(t *testing.T) {
+	emails := []TemplatedEmail{{To: "one@example.com"}, {To: "two@example.com"}}
+	err := APIError{ErrorCode: 0, Message: "", StatusCode: http.StatusBadGateway}
+
+	result := NewBatchResult(emails, nil, err)
+
+	assert.Len(t, result.Failed(), 2)
+	assert.Equal(t, emails, result.RetryBatch())
+	assert.Equal(t, "request failed with status 502", result.Items[0].Response.Message)
+
+	result = NewBatchResult(emails, nil, APIError{ErrorCode: ErrorCodeInvalidAPIToken})
+	assert.Equal(t, FailureAccount, result.Items[1].Failure())
+	assert.Empty(t, result.Retryable())
+}
+
+func TestClassifyFailure(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want FailureKind
+	}{
+		{"none", nil, FailureNone},
+		{"canceled", context.Canceled, FailureTransient},
+		{"transport", &url.Error{Op: "Post", URL: "https://api.postmarkapp.com/email", Err: errors.New("connection reset")}, FailureTransient},
+		{"wrapped transport", fmt.Errorf("%w: %w", ErrEmailFailed, &url.Error{Op: "Post", Err: io.EOF}), FailureTransient},
+		{"truncated body", io.ErrUnexpectedEOF, FailureTransient},
+		{"undecodable response", json.Unmarshal([]byte("<html>"), &EmailResponse{}), FailureOther},
+		{"unknown local", errors.New("something went wrong"), FailureOther},
+		{"missing idempotency key", ErrMissingIdempotencyKey, FailureOther},
+		{"throttled", APIError{StatusCode: http.StatusTooManyRequests}, FailureTransient},
+		{"maintenance", APIError{ErrorCode: ErrorCodeMaintenance, StatusCode: http.StatusServiceUnavailable}, FailureTransient},
+		{"inactive", APIError{ErrorCode: ErrorCodeInactiveRecipient}, FailureInactiveRecipient},
+		{"account", APIError{ErrorCode: ErrorCodeAccountPending}, FailureAccount},
+		{"sender", APIError{ErrorCode: ErrorCodeSenderSignatureNotConfirmed}, FailureSenderSignature},
+		{"template", APIError{ErrorCode: ErrorCodeTemplateNotFound}, FailureTemplateNotFound},
+		{"attachment", APIError{ErrorCode: ErrorCodeForbiddenAttachmentType}, FailureInvalidRequest},
+		{"body", APIError{ErrorCode: ErrorCodeInvalidEmailRequest, Message: "Provide either email TextBody or HtmlBody or both."}, FailureInvalidRequest},
+		{"api address", APIError{ErrorCode: ErrorCodeInvalidEmailRequest, Message: "Error parsing 'To': Illegal email address 'bad'."}, FailureInvalidRequest},
+		{"batch size", APIError{ErrorCode: ErrorCodeTooManyBatchMessages}, FailureInvalidRequest},
+		{"too large", ErrMessageTooLarge, FailureInvalidRequest},
+		{"invalid address", Email{From: "bad", To: "to@example.com", TextBody: "x"}.Validate(), FailureInvalidAddress},
+		{"invalid reply to", Email{From: "from@example.com", To: "to@example.com", ReplyTo: "bad", TextBody: "x"}.Validate(), FailureInvalidAddress},
+		{"invalid email", Email{From: "from@example.com", To: "to@example.com"}.Validate(), FailureInvalidRequest},
+		{"other", APIError{ErrorCode: 9999, StatusCode: http.StatusUnprocessableEntity}, FailureOther},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, classifyFailure(tt.err))
+		})
+	}
+}
+
+func (s *PostmarkTestSuite) TestBatchResultFromSendEmailBatches() {
+	s.mux.Post("/email/batch", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`[
+			{"To": "one@example.com", "MessageID": "1", "ErrorCode": 0, "Message": "OK"},
+			{"To": "two@example.com", "ErrorCode": 406, "Message": "Inactive recipient"}
+		]`))
+	})
+
+	emails := []Email{
+		{From: testSenderEmail, To: "one@example.com", TextBody: "Hello"},
+		{From: testSenderEmail, To: "two@example.com", TextBody: "Hello"},
+	}
+	res, err := s.client.SendEmailBatches(context.Background(), emails, nil)
+	result := NewBatchResult(emails, res, err)
+
+	s.Require().Len(result.Succeeded(), 1)
+	s.Require().Len(result.Failed(), 1)
+	s.Equal(FailureInactiveRecipient, result.Failed()[0].Failure())
+	s.Equal("two@example.com", result.Failed()[0].Email.To)
+	s.Empty(result.RetryBatch())
+}
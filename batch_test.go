@@ -0,0 +1,135 @@
+package postmark
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (s *PostmarkTestSuite) TestSendEmailBatches() {
+	var mu sync.Mutex
+	var sizes []int
+	s.mux.Post("/email/batch", func(w http.ResponseWriter, req *http.Request) {
+		var emails []Email
+		s.Require().NoError(json.NewDecoder(req.Body).Decode(&emails))
+
+		mu.Lock()
+		sizes = append(sizes, len(emails))
+		mu.Unlock()
+
+		res := make([]EmailResponse, 0, len(emails))
+		for _, email := range emails {
+			if strings.HasPrefix(email.To, "inactive") {
+				res = append(res, EmailResponse{To: email.To, ErrorCode: ErrorCodeInactiveRecipient, Message: "Inactive recipient"})
+				continue
+			}
+			res = append(res, EmailResponse{To: email.To, MessageID: "id-" + email.To, Message: "OK"})
+		}
+		_ = json.NewEncoder(w).Encode(res)
+	})
+
+	emails := make([]Email, 5)
+	for i := range emails {
+		emails[i] = Email{From: testSenderEmail, To: fmt.Sprintf("user%d@example.com", i), TextBody: "Hello"}
+	}
+	emails[3].To = "inactive@example.com"
+
+	res, err := s.client.SendEmailBatches(context.Background(), emails, &BatchOptions{MaxMessages: 2, Concurrency: 2})
+
+	s.Require().ErrorIs(err, ErrInactiveRecipient)
+	var batchErr BatchError
+	s.Require().ErrorAs(err, &batchErr)
+	s.Equal(5, batchErr.Total)
+	s.Equal([]int{3}, batchErr.indexes())
+	s.Contains(err.Error(), "1 of 5 messages failed: [3] Inactive recipient")
+
+	s.Require().Len(res, 5)
+	for i, email := range emails {
+		s.Equal(email.To, res[i].To)
+	}
+	s.Equal("id-user4@example.com", res[4].MessageID)
+	s.Equal(ErrorCodeInactiveRecipient, res[3].ErrorCode)
+	s.ElementsMatch([]int{2, 2, 1}, sizes)
+}
+
+func (s *PostmarkTestSuite) TestSendTemplatedEmailBatchesFailedRequest() {
+	calls := 0
+	s.mux.Post("/email/batchWithTemplates", func(w http.ResponseWriter, _ *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"ErrorCode": 10, "Message": "Bad or missing API token"}`))
+	})
+
+	client := s.newClient()
+	client.ValidateEmails = true
+
+	emails := []TemplatedEmail{
+		{TemplateAlias: "welcome", From: testSenderEmail, To: "one@example.com"},
+		{TemplateAlias: "welcome", From: testSenderEmail},
+		{TemplateAlias: "welcome", From: testSenderEmail, To: "three@example.com"},
+	}
+
+	res, err := client.SendTemplatedEmailBatches(context.Background(), emails, nil)
+
+	s.Require().ErrorIs(err, ErrInvalidAPIToken)
+	s.Require().ErrorIs(err, ErrInvalidEmailRequest)
+	s.Equal(1, calls)
+	s.Require().Len(res, 3)
+	s.Equal(ErrorCodeInvalidAPIToken, res[0].ErrorCode)
+	s.Equal("one@example.com", res[0].To)
+	s.Contains(res[1].Message, "To: is required")
+	s.Equal(ErrorCodeInvalidAPIToken, res[2].ErrorCode)
+}
+
+func (s *PostmarkTestSuite) TestSendEmailBatchesCanceled() {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	res, err := s.client.SendEmailBatches(ctx, []Email{{From: testSenderEmail, To: "one@example.com", TextBody: "Hello"}}, nil)
+
+	s.Require().ErrorIs(err, context.Canceled)
+	s.Require().Len(res, 1)
+	s.Equal("one@example.com", res[0].To)
+}
+
+func TestBatchSplitBySize(t *testing.T) {
+	email := Email{From: "sender@example.com", To: "to@example.com", TextBody: strings.Repeat("x", 100)}
+	data, err := json.Marshal(email)
+	require.NoError(t, err)
+
+	large := email
+	large.TextBody = strings.Repeat("x", 1000)
+
+	sender := batchSender[Email]{
+		client:   &Client{},
+		opts:     (&BatchOptions{MaxBytes: 2 + 3*len(data) + 2}).withDefaults(),
+		overhead: 2,
+		to:       func(email Email) string { return email.To },
+	}
+	messages := []Email{email, email, email, large, email, email}
+	sender.responses = make([]EmailResponse, len(messages))
+	sender.failed = make(map[int]error)
+
+	chunks := sender.split(messages)
+
+	require.Len(t, chunks, 2)
+	assert.Equal(t, []int{0, 1, 2}, chunks[0].indexes)
+	assert.Equal(t, []int{4, 5}, chunks[1].indexes)
+	require.ErrorIs(t, sender.failed[3], ErrMessageTooLarge)
+	assert.Equal(t, "to@example.com", sender.responses[3].To)
+}
+
+func TestBatchOptionsDefaults(t *testing.T) {
+	var opts *BatchOptions
+	assert.Equal(t, BatchOptions{MaxMessages: MaxBatchMessages, MaxBytes: MaxBatchSize, Concurrency: 1}, opts.withDefaults())
+
+	opts = &BatchOptions{MaxMessages: 1000, MaxBytes: 10, Concurrency: 4}
+	assert.Equal(t, BatchOptions{MaxMessages: MaxBatchMessages, MaxBytes: 10, Concurrency: 4}, opts.withDefaults())
+}
@@ -3,6 +3,8 @@ package postmark
 import (
 	"context"
 	"fmt"
+	"iter"
+	"strconv"
 	"time"
 )
 
@@ -28,7 +30,7 @@ type DeliveryStats struct {
 // GetDeliveryStats returns delivery stats for the server
 func (client *Client) GetDeliveryStats(ctx context.Context) (DeliveryStats, error) {
 	res := DeliveryStats{}
-	err := client.get(ctx, "deliverystats", &res)
+	err := client.get(ctx, "GetDeliveryStats", "deliverystats", &res)
 	return res, err
 }
 
@@ -77,7 +79,7 @@ type bouncesResponse struct {
 
 // GetBounces returns bounces for the server
 // It returns a Bounce slice, the total bounce count, and any error that occurred
-// Available options: http://developer.postmarkapp.com/developer-api-bounce.html#bounces
+// Available options: http://developer.postmarkapp.com/developer-api-bounce.html#bounces (see BounceFilter)
 func (client *Client) GetBounces(ctx context.Context, count, offset int64, options map[string]interface{}) ([]Bounce, int64, error) {
 	res := bouncesResponse{}
 
@@ -88,14 +90,26 @@ func (client *Client) GetBounces(ctx context.Context, count, offset int64, optio
 	options["count"] = count
 	options["offset"] = offset
 
-	err := client.get(ctx, buildURL("bounces", options), &res)
+	err := client.get(ctx, "GetBounces", buildURL("bounces", options), &res)
 	return res.Bounces, res.TotalCount, err
 }
 
+// Bounces returns an iterator over every bounce matching the options, fetching pages as needed
+// Searches with more than 10,000 results are split into smaller date ranges automatically
+// Available options: http://developer.postmarkapp.com/developer-api-bounce.html#bounces
+func (client *Client) Bounces(ctx context.Context, options map[string]interface{}) iter.Seq2[Bounce, error] {
+	return paginate(ctx, pager[Bounce]{
+		fetch:     client.GetBounces,
+		limit:     maxSearchOffset,
+		timestamp: func(bounce Bounce) time.Time { return bounce.BouncedAt },
+		key:       func(bounce Bounce) string { return strconv.FormatInt(bounce.ID, 10) },
+	}, options)
+}
+
 // GetBounce fetches a single bounce with bounceID
 func (client *Client) GetBounce(ctx context.Context, bounceID int64) (Bounce, error) {
 	res := Bounce{}
-	err := client.get(ctx, fmt.Sprintf("bounces/%v", bounceID), &res)
+	err := client.get(ctx, "GetBounce", fmt.Sprintf("bounces/%v", bounceID), &res)
 	return res, err
 }
 
@@ -106,7 +120,7 @@ type dumpResponse struct {
 // GetBounceDump fetches an SMTP data dump for a single bounce
 func (client *Client) GetBounceDump(ctx context.Context, bounceID int64) (string, error) {
 	res := dumpResponse{}
-	err := client.get(ctx, fmt.Sprintf("bounces/%v/dump", bounceID), &res)
+	err := client.get(ctx, "GetBounceDump", fmt.Sprintf("bounces/%v/dump", bounceID), &res)
 	return res.Body, err
 }
 
@@ -120,13 +134,13 @@ type activateBounceResponse struct {
 // TODO: clarify this with Postmark
 func (client *Client) ActivateBounce(ctx context.Context, bounceID int64) (Bounce, string, error) {
 	res := activateBounceResponse{}
-	err := client.put(ctx, fmt.Sprintf("bounces/%v/activate", bounceID), nil, &res)
+	err := client.put(ctx, "ActivateBounce", fmt.Sprintf("bounces/%v/activate", bounceID), nil, &res)
 	return res.Bounce, res.Message, err
 }
 
 // GetBouncedTags retrieves a list of tags that have generated bounced emails
 func (client *Client) GetBouncedTags(ctx context.Context) ([]string, error) {
 	var res []string
-	err := client.get(ctx, "bounces/tags", &res)
+	err := client.get(ctx, "GetBouncedTags", "bounces/tags", &res)
 	return res, err
 }
@@ -0,0 +1,136 @@
+package postmark
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// BulkStatus is the processing status of a bulk email request
+type BulkStatus string
+
+const (
+	// BulkStatusAccepted means the bulk request was received and is queued
+	BulkStatusAccepted BulkStatus = "Accepted"
+
+	// BulkStatusProcessing means the messages of the bulk request are being sent
+	BulkStatusProcessing BulkStatus = "Processing"
+
+	// BulkStatusCompleted means every message of the bulk request was processed
+	BulkStatusCompleted BulkStatus = "Completed"
+)
+
+// DefaultBulkPollInterval is how often WaitBulkEmail checks the status when no interval is given
+const DefaultBulkPollInterval = 5 * time.Second
+
+// BulkEmail is one message sent to many recipients through a broadcast stream
+// The Subject and bodies can use template placeholders filled from each recipient's TemplateModel
+type BulkEmail struct {
+	// From: REQUIRED The sender email address. Must have a registered and confirmed Sender Signature.
+	From string `json:",omitempty"`
+	// Subject: Email subject
+	Subject string `json:",omitempty"`
+	// HTMLBody: HTML email message. REQUIRED, If no TextBody specified
+	HTMLBody string `json:"HtmlBody,omitempty"`
+	// TextBody: Plain text email message. REQUIRED, If no HTMLBody specified
+	TextBody string `json:",omitempty"`
+	// ReplyTo: Reply To override email address. Defaults to the Reply To set in the sender signature.
+	ReplyTo string `json:",omitempty"`
+	// Tag: Email tag that allows you to categorize outgoing emails and get detailed statistics.
+	Tag string `json:",omitempty"`
+	// Headers: List of custom headers to include.
+	Headers []Header `json:",omitempty"`
+	// TrackOpens: Activate open tracking for this email.
+	TrackOpens bool `json:",omitempty"`
+	// TrackLinks: Activate link tracking. Possible options: None HtmlAndText HtmlOnly TextOnly
+	TrackLinks string `json:",omitempty"`
+	// Attachments: List of attachments
+	Attachments []Attachment `json:",omitempty"`
+	// Metadata: metadata shared by every message
+	Metadata map[string]string `json:",omitempty"`
+	// MessageStream: REQUIRED ID of the broadcast message stream to send through
+	MessageStream string `json:",omitempty"`
+	// Messages: REQUIRED the recipients, each one receives its own message
+	Messages []BulkRecipient
+}
+
+// BulkRecipient is a recipient of a bulk email with its template model
+type BulkRecipient struct {
+	// To: REQUIRED Recipient email address. Multiple addresses are comma separated.
+	To string `json:",omitempty"`
+	// Cc recipient email address. Multiple addresses are comma separated.
+	Cc string `json:",omitempty"`
+	// Bcc recipient email address. Multiple addresses are comma separated.
+	Bcc string `json:",omitempty"`
+	// TemplateModel: values for the placeholders of the Subject and bodies
+	TemplateModel map[string]interface{} `json:",omitempty"`
+	// Metadata: metadata of this recipient's message
+	Metadata map[string]string `json:",omitempty"`
+}
+
+// BulkEmailResponse is returned when a bulk email is accepted
+type BulkEmailResponse struct {
+	// ID: ID of the bulk request, used with GetBulkEmailStatus
+	ID string `json:"Id"`
+	// Status: processing status of the bulk request
+	Status BulkStatus
+	// SubmittedAt: when the bulk request was received
+	SubmittedAt time.Time
+}
+
+// BulkEmailStatus is the progress of a bulk email request
+type BulkEmailStatus struct {
+	// ID: ID of the bulk request
+	ID string `json:"Id"`
+	// SubmittedAt: when the bulk request was received
+	SubmittedAt time.Time
+	// TotalMessages: number of messages in the bulk request
+	TotalMessages int64
+	// PercentageCompleted: share of the messages processed, from 0 to 1
+	PercentageCompleted float64
+	// Status: processing status of the bulk request
+	Status BulkStatus
+}
+
+// Completed reports whether every message of the bulk request was processed
+func (s BulkEmailStatus) Completed() bool {
+	return s.Status == BulkStatusCompleted
+}
+
+// SendBulkEmail sends one message to many recipients through a broadcast stream
+// Postmark processes the request asynchronously, use GetBulkEmailStatus or WaitBulkEmail to follow it
+func (client *Client) SendBulkEmail(ctx context.Context, email BulkEmail) (BulkEmailResponse, error) {
+	res := BulkEmailResponse{}
+	err := client.post(ctx, "SendBulkEmail", "email/bulk", email, &res)
+	return res, sendFailed(err)
+}
+
+// GetBulkEmailStatus gets the progress of a bulk email request
+func (client *Client) GetBulkEmailStatus(ctx context.Context, id string) (BulkEmailStatus, error) {
+	res := BulkEmailStatus{}
+	err := client.get(ctx, "GetBulkEmailStatus", fmt.Sprintf("email/bulk/%s", url.PathEscape(id)), &res)
+	return res, err
+}
+
+// WaitBulkEmail polls the status of a bulk email request every interval until it is completed or the context is done
+// It returns the last status fetched, along with the context error if the request did not complete in time
+func (client *Client) WaitBulkEmail(ctx context.Context, id string, interval time.Duration) (BulkEmailStatus, error) {
+	if interval <= 0 {
+		interval = DefaultBulkPollInterval
+	}
+
+	var last BulkEmailStatus
+	for {
+		status, err := client.GetBulkEmailStatus(ctx, id)
+		if err != nil {
+			return last, err
+		}
+		if last = status; last.Completed() {
+			return last, nil
+		}
+		if err = sleepContext(ctx, interval); err != nil {
+			return last, err
+		}
+	}
+}
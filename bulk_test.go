@@ -0,0 +1,84 @@
+package postmark
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+func (s *PostmarkTestSuite) TestSendBulkEmail() {
+	s.mux.Post("/email/bulk", func(w http.ResponseWriter, r *http.Request) {
+		var email BulkEmail
+		s.Require().NoError(json.NewDecoder(r.Body).Decode(&email))
+		s.Equal("broadcast", email.MessageStream)
+		s.Equal("Hello {{name}}", email.Subject)
+		s.Require().Len(email.Messages, 2)
+		s.Equal("jane@example.com", email.Messages[1].To)
+		s.Equal(map[string]interface{}{"name": "Jane"}, email.Messages[1].TemplateModel)
+
+		_, _ = w.Write([]byte(`{
+			"Id": "f24af63c-533d-4b7a-ad65-4a7b3202d3a7",
+			"Status": "Accepted",
+			"SubmittedAt": "2024-03-17T07:25:01.4178645-05:00"
+		}`))
+	})
+
+	res, err := s.client.SendBulkEmail(context.Background(), BulkEmail{
+		From:          testSenderEmail,
+		Subject:       "Hello {{name}}",
+		TextBody:      "Hi {{name}}",
+		MessageStream: "broadcast",
+		Messages: []BulkRecipient{
+			{To: "john@example.com", TemplateModel: map[string]interface{}{"name": "John"}},
+			{To: "jane@example.com", TemplateModel: map[string]interface{}{"name": "Jane"}},
+		},
+	})
+	s.Require().NoError(err)
+	s.Equal("f24af63c-533d-4b7a-ad65-4a7b3202d3a7", res.ID)
+	s.Equal(BulkStatusAccepted, res.Status)
+}
+
+func (s *PostmarkTestSuite) TestGetBulkEmailStatus() {
+	s.mux.Get("/email/bulk/:bulkID", func(w http.ResponseWriter, r *http.Request) {
+		s.Equal("f24af63c", GetPathParam(r, "bulkID"))
+		_, _ = w.Write([]byte(`{
+			"Id": "f24af63c",
+			"SubmittedAt": "2024-03-17T07:25:01.4178645-05:00",
+			"TotalMessages": 3,
+			"PercentageCompleted": 0.5,
+			"Status": "Processing"
+		}`))
+	})
+
+	res, err := s.client.GetBulkEmailStatus(context.Background(), "f24af63c")
+	s.Require().NoError(err)
+	s.Equal("f24af63c", res.ID)
+	s.Equal(int64(3), res.TotalMessages)
+	s.InDelta(0.5, res.PercentageCompleted, 0)
+	s.False(res.Completed())
+}
+
+func (s *PostmarkTestSuite) TestWaitBulkEmail() {
+	var calls atomic.Int32
+	s.mux.Get("/email/bulk/:bulkID", func(w http.ResponseWriter, _ *http.Request) {
+		status := BulkStatusProcessing
+		if calls.Add(1) >= 3 {
+			status = BulkStatusCompleted
+		}
+		_ = json.NewEncoder(w).Encode(BulkEmailStatus{ID: "f24af63c", Status: status})
+	})
+
+	res, err := s.client.WaitBulkEmail(context.Background(), "f24af63c", time.Millisecond)
+	s.Require().NoError(err)
+	s.True(res.Completed())
+	s.Equal(int32(3), calls.Load())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	calls.Store(-1000)
+	res, err = s.client.WaitBulkEmail(ctx, "f24af63c", time.Millisecond)
+	s.Require().ErrorIs(err, context.DeadlineExceeded)
+	s.Equal(BulkStatusProcessing, res.Status)
+}
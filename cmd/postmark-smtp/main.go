@@ -0,0 +1,59 @@
+// Command postmark-smtp runs a local SMTP server that relays every message it receives to Postmark.
+//
+//	POSTMARK_SERVER_TOKEN=... postmark-smtp -addr 127.0.0.1:2525
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/mrz1836/postmark"
+	"github.com/mrz1836/postmark/postmarksmtp"
+)
+
+func main() {
+	addr := flag.String("addr", "127.0.0.1:2525", "address to listen on")
+	hostname := flag.String("hostname", "localhost", "hostname announced in the SMTP greeting")
+	debug := flag.Bool("debug", false, "log every Postmark API call")
+	flag.Parse()
+
+	level := slog.LevelInfo
+	if *debug {
+		level = slog.LevelDebug
+	}
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: level}))
+
+	token := os.Getenv("POSTMARK_SERVER_TOKEN")
+	if token == "" {
+		logger.Error("POSTMARK_SERVER_TOKEN is not set")
+		os.Exit(2)
+	}
+
+	client := postmark.NewClient(token, "")
+	client.Logger = logger
+
+	srv := &postmarksmtp.Server{
+		Addr:     *addr,
+		Sender:   client,
+		Hostname: *hostname,
+		Logger:   logger,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		_ = srv.Close()
+	}()
+
+	logger.Info("relaying SMTP to Postmark", slog.String("addr", *addr))
+	if err := srv.ListenAndServe(); !errors.Is(err, postmarksmtp.ErrServerClosed) {
+		logger.Error("smtp server failed", slog.Any("error", err))
+		os.Exit(1)
+	}
+}
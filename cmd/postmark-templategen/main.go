@@ -0,0 +1,83 @@
+// Command postmark-templategen generates a typed Go model struct and SendXxx function for each template,
+// so a TemplateModel that does not match its template is a compile error.
+//
+// Models are derived locally from a template directory, or suggested by ValidateTemplate for the templates
+// of a server or, with -validate, of a directory:
+//
+//	postmark-templategen -dir templates -pkg emails -out emails/templates_gen.go
+//	POSTMARK_SERVER_TOKEN=... postmark-templategen -pkg emails -out emails/templates_gen.go
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/mrz1836/postmark"
+	"github.com/mrz1836/postmark/postmarktemplates"
+)
+
+// errNoToken is returned when the server must be called without a token
+var errNoToken = errors.New("POSTMARK_SERVER_TOKEN is not set")
+
+func main() {
+	dir := flag.String("dir", "", "template directory, as synced or exported by postmarktemplates (default: the templates of the server)")
+	validate := flag.Bool("validate", false, "suggest the models of -dir with ValidateTemplate instead of locally")
+	pkg := flag.String("pkg", "templates", "package name of the generated code")
+	out := flag.String("out", "", "file to write (default: standard output)")
+	flag.Parse()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if err := run(ctx, *dir, *validate, *pkg, *out); err != nil {
+		fmt.Fprintln(os.Stderr, "postmark-templategen:", err)
+		os.Exit(1)
+	}
+}
+
+// run loads the templates, derives their models and writes the generated code
+func run(ctx context.Context, dir string, validate bool, pkg, out string) error {
+	var client *postmark.Client
+	if dir == "" || validate {
+		token := os.Getenv("POSTMARK_SERVER_TOKEN")
+		if token == "" {
+			return errNoToken
+		}
+		client = postmark.NewClient(token, "")
+	}
+
+	var templates []postmark.Template
+	var err error
+	if dir != "" {
+		templates, err = postmarktemplates.Load(os.DirFS(dir))
+	} else {
+		templates, err = postmarktemplates.Fetch(ctx, client)
+	}
+	if err != nil {
+		return err
+	}
+
+	var models []postmarktemplates.TemplateModel
+	if client != nil {
+		models, err = postmarktemplates.SuggestModels(ctx, client, templates)
+	} else {
+		models, err = postmarktemplates.LocalModels(templates)
+	}
+	if err != nil {
+		return err
+	}
+
+	source, err := postmarktemplates.GenerateCode(pkg, models)
+	if err != nil {
+		return err
+	}
+	if out == "" {
+		_, err = os.Stdout.Write(source)
+		return err
+	}
+	return os.WriteFile(out, source, 0o644)
+}
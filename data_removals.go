@@ -29,13 +29,13 @@ type DataRemovalResponse struct {
 // CreateDataRemoval creates a new data removal request
 func (client *Client) CreateDataRemoval(ctx context.Context, request DataRemovalRequest) (DataRemovalResponse, error) {
 	res := DataRemovalResponse{}
-	err := client.postWithAccountToken(ctx, "data-removals", request, &res)
+	err := client.postWithAccountToken(ctx, "CreateDataRemoval", "data-removals", request, &res)
 	return res, err
 }
 
 // GetDataRemovalStatus checks the status of a data removal request
 func (client *Client) GetDataRemovalStatus(ctx context.Context, id int64) (DataRemovalResponse, error) {
 	res := DataRemovalResponse{}
-	err := client.getWithAccountToken(ctx, fmt.Sprintf("data-removals/%d", id), &res)
+	err := client.getWithAccountToken(ctx, "GetDataRemovalStatus", fmt.Sprintf("data-removals/%d", id), &res)
 	return res, err
 }
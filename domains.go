@@ -3,6 +3,7 @@ package postmark
 import (
 	"context"
 	"fmt"
+	"iter"
 	"net/url"
 )
 
@@ -109,55 +110,57 @@ func (client *Client) GetDomains(ctx context.Context, count, offset int) (Domain
 	values.Add("count", fmt.Sprintf("%d", count))
 	values.Add("offset", fmt.Sprintf("%d", offset))
 
-	err := client.getWithAccountToken(ctx, buildURLWithQuery("domains", *values), &res)
+	err := client.getWithAccountToken(ctx, "GetDomains", buildURLWithQuery("domains", *values), &res)
 	return res, err
 }
 
+// Domains returns an iterator over every domain on the account, fetching pages as needed
+func (client *Client) Domains(ctx context.Context) iter.Seq2[Domain, error] {
+	return paginate(ctx, pager[Domain]{
+		fetch: func(ctx context.Context, count, offset int64, _ map[string]interface{}) ([]Domain, int64, error) {
+			res, err := client.GetDomains(ctx, int(count), int(offset))
+			return res.Domains, int64(res.TotalCount), err
+		},
+	}, nil)
+}
+
 // GetDomain fetches a specific domain via domainID
 func (client *Client) GetDomain(ctx context.Context, domainID int64) (DomainDetails, error) {
 	res := DomainDetails{}
-	err := client.getWithAccountToken(ctx, fmt.Sprintf("domains/%d", domainID), &res)
+	err := client.getWithAccountToken(ctx, "GetDomain", fmt.Sprintf("domains/%d", domainID), &res)
 	return res, err
 }
 
 // EditDomain updates details for a specific domain with domainID
 func (client *Client) EditDomain(ctx context.Context, domainID int64, request DomainEditRequest) (DomainDetails, error) {
 	res := DomainDetails{}
-	err := client.putWithAccountToken(ctx, fmt.Sprintf("domains/%d", domainID), request, &res)
+	err := client.putWithAccountToken(ctx, "EditDomain", fmt.Sprintf("domains/%d", domainID), request, &res)
 	return res, err
 }
 
 // CreateDomain creates a domain
 func (client *Client) CreateDomain(ctx context.Context, request DomainCreateRequest) (DomainDetails, error) {
 	res := DomainDetails{}
-	err := client.postWithAccountToken(ctx, "domains", request, &res)
+	err := client.postWithAccountToken(ctx, "CreateDomain", "domains", request, &res)
 	return res, err
 }
 
 // DeleteDomain deletes a specific domain via domainID
 func (client *Client) DeleteDomain(ctx context.Context, domainID int64) error {
-	res := APIError{}
-	err := client.deleteWithAccountToken(ctx, fmt.Sprintf("domains/%d", domainID), &res)
-	if err != nil {
-		return err
-	}
-	if res.ErrorCode != 0 {
-		return res
-	}
-	return nil
+	return client.deleteWithAccountToken(ctx, "DeleteDomain", fmt.Sprintf("domains/%d", domainID), &APIError{})
 }
 
 // VerifyDKIMStatus verifies DKIM keys for the specified domain.
 func (client *Client) VerifyDKIMStatus(ctx context.Context, domainID int64) (DomainDetails, error) {
 	res := DomainDetails{}
-	err := client.putWithAccountToken(ctx, fmt.Sprintf("domains/%d/verifyDkim", domainID), nil, &res)
+	err := client.putWithAccountToken(ctx, "VerifyDKIMStatus", fmt.Sprintf("domains/%d/verifyDkim", domainID), nil, &res)
 	return res, err
 }
 
 // VerifyReturnPath verifies Return-Path DNS record for the specified domain.
 func (client *Client) VerifyReturnPath(ctx context.Context, domainID int64) (DomainDetails, error) {
 	res := DomainDetails{}
-	err := client.putWithAccountToken(ctx, fmt.Sprintf("domains/%d/verifyReturnPath", domainID), nil, &res)
+	err := client.putWithAccountToken(ctx, "VerifyReturnPath", fmt.Sprintf("domains/%d/verifyReturnPath", domainID), nil, &res)
 	return res, err
 }
 
@@ -167,6 +170,6 @@ func (client *Client) VerifyReturnPath(ctx context.Context, domainID int64) (Dom
 // the new DKIM key.
 func (client *Client) RotateDKIM(ctx context.Context, domainID int64) (DomainDetails, error) {
 	res := DomainDetails{}
-	err := client.postWithAccountToken(ctx, fmt.Sprintf("domains/%d/rotatedkim", domainID), nil, &res)
+	err := client.postWithAccountToken(ctx, "RotateDKIM", fmt.Sprintf("domains/%d/rotatedkim", domainID), nil, &res)
 	return res, err
 }
@@ -2,8 +2,11 @@ package postmark
 
 import (
 	"context"
+	"encoding/base64"
 	"errors"
 	"fmt"
+	"io"
+	"strings"
 	"time"
 )
 
@@ -63,9 +66,28 @@ type Attachment struct {
 	ContentID string `json:",omitempty"`
 }
 
-// ErrEmailFailed is returned when email sending fails
+// Reader returns a reader that decodes the base64 attachment content
+func (a Attachment) Reader() io.Reader {
+	return base64.NewDecoder(base64.StdEncoding, strings.NewReader(a.Content))
+}
+
+// Decode returns the decoded attachment content
+func (a Attachment) Decode() ([]byte, error) {
+	return base64.StdEncoding.DecodeString(a.Content)
+}
+
+// ErrEmailFailed is returned by the send methods when Postmark rejects a request or cannot be reached,
+// it wraps the underlying (APIError) cause. Errors found before sending, such as validation errors, are not wrapped.
 var ErrEmailFailed = errors.New("email send failed")
 
+// sendFailed wraps an API or transport failure of a send method in ErrEmailFailed
+func sendFailed(err error) error {
+	if err == nil {
+		return nil
+	}
+	return fmt.Errorf("%w: %w", ErrEmailFailed, err)
+}
+
 // EmailResponse holds info in response to a send/send-batch request
 // Even if API request comes back successful, check the ErrorCode to see if there might be a delivery problem
 type EmailResponse struct {
@@ -83,24 +105,29 @@ type EmailResponse struct {
 
 // SendEmail sends, well, an email.
 func (client *Client) SendEmail(ctx context.Context, email Email) (EmailResponse, error) {
-	res := EmailResponse{}
-	err := client.post(ctx, "email", email, &res)
-	if err != nil {
-		return res, err
-	}
-
-	if res.ErrorCode != 0 {
-		return res, fmt.Errorf("%w: %v %s", ErrEmailFailed, res.ErrorCode, res.Message)
+	if client.ValidateEmails {
+		if err := email.Validate(); err != nil {
+			return EmailResponse{}, err
+		}
 	}
 
-	return res, nil
+	res := EmailResponse{}
+	err := client.post(ctx, "SendEmail", "email", email, &res)
+	return res, sendFailed(err)
 }
 
 // SendEmailBatch sends multiple emails together
-// Individual emails in the batch can error, so it would be wise to
-// range over the responses and sniff for errors
+// Individual emails in the batch can error, use NewBatchResult to pair
+// the emails with their responses and find the failed ones
+// Use SendEmailBatches for more than MaxBatchMessages emails
 func (client *Client) SendEmailBatch(ctx context.Context, emails []Email) ([]EmailResponse, error) {
+	if client.ValidateEmails {
+		if err := ValidateEmailBatch(emails); err != nil {
+			return nil, err
+		}
+	}
+
 	var res []EmailResponse
-	err := client.post(ctx, "email/batch", emails, &res)
-	return res, err
+	err := client.post(ctx, "SendEmailBatch", "email/batch", emails, &res)
+	return res, sendFailed(err)
 }
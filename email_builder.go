@@ -0,0 +1,390 @@
+package postmark
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"net/mail"
+	"path"
+	"strings"
+)
+
+// message holds the fields shared by Email and TemplatedEmail while they are built
+type message struct {
+	from        string
+	to          []string
+	cc          []string
+	bcc         []string
+	replyTo     []string
+	tag         string
+	headers     []Header
+	trackOpens  bool
+	trackLinks  string
+	attachments []Attachment
+	metadata    map[string]string
+	stream      string
+	errs        []error
+}
+
+// formatAddresses formats addresses, quoting and encoding (RFC 2047) display names as needed
+func formatAddresses(addresses []mail.Address) []string {
+	formatted := make([]string, 0, len(addresses))
+	for _, address := range addresses {
+		formatted = append(formatted, address.String())
+	}
+	return formatted
+}
+
+// setMetadata sets a metadata field
+func (m *message) setMetadata(key, value string) {
+	if m.metadata == nil {
+		m.metadata = make(map[string]string)
+	}
+	m.metadata[key] = value
+}
+
+// attach reads an attachment, detecting its MIME type from its name or content
+func (m *message) attach(name, contentID string, r io.Reader) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		m.errs = append(m.errs, fmt.Errorf("attachment %q: %w", name, err))
+		return
+	}
+
+	contentType := mime.TypeByExtension(path.Ext(name))
+	if contentType == "" {
+		contentType = http.DetectContentType(data)
+	}
+	if contentID != "" && !strings.HasPrefix(contentID, "cid:") {
+		contentID = "cid:" + contentID
+	}
+
+	m.attachments = append(m.attachments, Attachment{
+		Name:        name,
+		Content:     base64.StdEncoding.EncodeToString(data),
+		ContentType: contentType,
+		ContentID:   contentID,
+	})
+}
+
+// attachFile reads an attachment from a file system, named after the base name of the file
+func (m *message) attachFile(fsys fs.FS, name, contentID string) {
+	file, err := fsys.Open(name)
+	if err != nil {
+		m.errs = append(m.errs, fmt.Errorf("attachment %q: %w", name, err))
+		return
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+	m.attach(path.Base(name), contentID, file)
+}
+
+// EmailBuilder builds an Email with typed recipients and attachments
+type EmailBuilder struct {
+	message
+	subject   string
+	htmlBody  string
+	textBody  string
+	inlineCSS bool
+}
+
+// NewEmailBuilder returns an empty EmailBuilder
+func NewEmailBuilder() *EmailBuilder {
+	return &EmailBuilder{}
+}
+
+// From sets the sender
+func (b *EmailBuilder) From(address mail.Address) *EmailBuilder {
+	b.from = address.String()
+	return b
+}
+
+// To adds recipients
+func (b *EmailBuilder) To(addresses ...mail.Address) *EmailBuilder {
+	b.to = append(b.to, formatAddresses(addresses)...)
+	return b
+}
+
+// Cc adds carbon copy recipients
+func (b *EmailBuilder) Cc(addresses ...mail.Address) *EmailBuilder {
+	b.cc = append(b.cc, formatAddresses(addresses)...)
+	return b
+}
+
+// Bcc adds blind carbon copy recipients
+func (b *EmailBuilder) Bcc(addresses ...mail.Address) *EmailBuilder {
+	b.bcc = append(b.bcc, formatAddresses(addresses)...)
+	return b
+}
+
+// ReplyTo adds Reply To addresses
+func (b *EmailBuilder) ReplyTo(addresses ...mail.Address) *EmailBuilder {
+	b.replyTo = append(b.replyTo, formatAddresses(addresses)...)
+	return b
+}
+
+// Subject sets the subject
+func (b *EmailBuilder) Subject(subject string) *EmailBuilder {
+	b.subject = subject
+	return b
+}
+
+// HTMLBody sets the HTML body
+func (b *EmailBuilder) HTMLBody(body string) *EmailBuilder {
+	b.htmlBody = body
+	return b
+}
+
+// TextBody sets the plain text body
+func (b *EmailBuilder) TextBody(body string) *EmailBuilder {
+	b.textBody = body
+	return b
+}
+
+// InlineCSS sets whether the style blocks of the HTML body are inlined
+func (b *EmailBuilder) InlineCSS(inline bool) *EmailBuilder {
+	b.inlineCSS = inline
+	return b
+}
+
+// Tag sets the tag
+func (b *EmailBuilder) Tag(tag string) *EmailBuilder {
+	b.tag = tag
+	return b
+}
+
+// Header adds a custom header
+func (b *EmailBuilder) Header(name, value string) *EmailBuilder {
+	b.headers = append(b.headers, Header{Name: name, Value: value})
+	return b
+}
+
+// Metadata sets a metadata field
+func (b *EmailBuilder) Metadata(key, value string) *EmailBuilder {
+	b.setMetadata(key, value)
+	return b
+}
+
+// TrackOpens sets open tracking
+func (b *EmailBuilder) TrackOpens(track bool) *EmailBuilder {
+	b.trackOpens = track
+	return b
+}
+
+// TrackLinks sets link tracking: None, HtmlAndText, HtmlOnly or TextOnly
+func (b *EmailBuilder) TrackLinks(track string) *EmailBuilder {
+	b.trackLinks = track
+	return b
+}
+
+// MessageStream sets the message stream
+func (b *EmailBuilder) MessageStream(stream string) *EmailBuilder {
+	b.stream = stream
+	return b
+}
+
+// Attach adds an attachment read from r, its MIME type is detected from the name or the content
+func (b *EmailBuilder) Attach(name string, r io.Reader) *EmailBuilder {
+	b.attach(name, "", r)
+	return b
+}
+
+// AttachFile adds an attachment read from a file system
+func (b *EmailBuilder) AttachFile(fsys fs.FS, name string) *EmailBuilder {
+	b.attachFile(fsys, name, "")
+	return b
+}
+
+// AttachInline adds an inline image, referenced from the HTML body as <img src="cid:contentID">
+func (b *EmailBuilder) AttachInline(contentID, name string, r io.Reader) *EmailBuilder {
+	b.attach(name, contentID, r)
+	return b
+}
+
+// AttachInlineFile adds an inline image read from a file system
+func (b *EmailBuilder) AttachInlineFile(contentID string, fsys fs.FS, name string) *EmailBuilder {
+	b.attachFile(fsys, name, contentID)
+	return b
+}
+
+// Build returns the Email, or the errors of the attachments and of Email.Validate
+func (b *EmailBuilder) Build() (Email, error) {
+	email := Email{
+		From:          b.from,
+		To:            strings.Join(b.to, ", "),
+		Cc:            strings.Join(b.cc, ", "),
+		Bcc:           strings.Join(b.bcc, ", "),
+		Subject:       b.subject,
+		Tag:           b.tag,
+		HTMLBody:      b.htmlBody,
+		TextBody:      b.textBody,
+		ReplyTo:       strings.Join(b.replyTo, ", "),
+		Headers:       b.headers,
+		TrackOpens:    b.trackOpens,
+		TrackLinks:    b.trackLinks,
+		Attachments:   b.attachments,
+		Metadata:      b.metadata,
+		MessageStream: b.stream,
+		InlineCSS:     b.inlineCSS,
+	}
+	return email, errors.Join(append([]error{email.Validate()}, b.errs...)...)
+}
+
+// TemplatedEmailBuilder builds a TemplatedEmail with typed recipients and attachments
+type TemplatedEmailBuilder struct {
+	message
+	templateID    int64
+	templateAlias string
+	model         map[string]interface{}
+	inlineCSS     bool
+}
+
+// NewTemplatedEmailBuilder returns an empty TemplatedEmailBuilder
+func NewTemplatedEmailBuilder() *TemplatedEmailBuilder {
+	return &TemplatedEmailBuilder{}
+}
+
+// TemplateID sets the template by ID
+func (b *TemplatedEmailBuilder) TemplateID(id int64) *TemplatedEmailBuilder {
+	b.templateID = id
+	return b
+}
+
+// TemplateAlias sets the template by alias
+func (b *TemplatedEmailBuilder) TemplateAlias(alias string) *TemplatedEmailBuilder {
+	b.templateAlias = alias
+	return b
+}
+
+// Model sets a value of the template model
+func (b *TemplatedEmailBuilder) Model(key string, value interface{}) *TemplatedEmailBuilder {
+	if b.model == nil {
+		b.model = make(map[string]interface{})
+	}
+	b.model[key] = value
+	return b
+}
+
+// InlineCSS sets whether the style blocks of the template HTML body are inlined
+func (b *TemplatedEmailBuilder) InlineCSS(inline bool) *TemplatedEmailBuilder {
+	b.inlineCSS = inline
+	return b
+}
+
+// From sets the sender
+func (b *TemplatedEmailBuilder) From(address mail.Address) *TemplatedEmailBuilder {
+	b.from = address.String()
+	return b
+}
+
+// To adds recipients
+func (b *TemplatedEmailBuilder) To(addresses ...mail.Address) *TemplatedEmailBuilder {
+	b.to = append(b.to, formatAddresses(addresses)...)
+	return b
+}
+
+// Cc adds carbon copy recipients
+func (b *TemplatedEmailBuilder) Cc(addresses ...mail.Address) *TemplatedEmailBuilder {
+	b.cc = append(b.cc, formatAddresses(addresses)...)
+	return b
+}
+
+// Bcc adds blind carbon copy recipients
+func (b *TemplatedEmailBuilder) Bcc(addresses ...mail.Address) *TemplatedEmailBuilder {
+	b.bcc = append(b.bcc, formatAddresses(addresses)...)
+	return b
+}
+
+// ReplyTo adds Reply To addresses
+func (b *TemplatedEmailBuilder) ReplyTo(addresses ...mail.Address) *TemplatedEmailBuilder {
+	b.replyTo = append(b.replyTo, formatAddresses(addresses)...)
+	return b
+}
+
+// Tag sets the tag
+func (b *TemplatedEmailBuilder) Tag(tag string) *TemplatedEmailBuilder {
+	b.tag = tag
+	return b
+}
+
+// Header adds a custom header
+func (b *TemplatedEmailBuilder) Header(name, value string) *TemplatedEmailBuilder {
+	b.headers = append(b.headers, Header{Name: name, Value: value})
+	return b
+}
+
+// Metadata sets a metadata field
+func (b *TemplatedEmailBuilder) Metadata(key, value string) *TemplatedEmailBuilder {
+	b.setMetadata(key, value)
+	return b
+}
+
+// TrackOpens sets open tracking
+func (b *TemplatedEmailBuilder) TrackOpens(track bool) *TemplatedEmailBuilder {
+	b.trackOpens = track
+	return b
+}
+
+// TrackLinks sets link tracking: None, HtmlAndText, HtmlOnly or TextOnly
+func (b *TemplatedEmailBuilder) TrackLinks(track string) *TemplatedEmailBuilder {
+	b.trackLinks = track
+	return b
+}
+
+// MessageStream sets the message stream
+func (b *TemplatedEmailBuilder) MessageStream(stream string) *TemplatedEmailBuilder {
+	b.stream = stream
+	return b
+}
+
+// Attach adds an attachment read from r, its MIME type is detected from the name or the content
+func (b *TemplatedEmailBuilder) Attach(name string, r io.Reader) *TemplatedEmailBuilder {
+	b.attach(name, "", r)
+	return b
+}
+
+// AttachFile adds an attachment read from a file system
+func (b *TemplatedEmailBuilder) AttachFile(fsys fs.FS, name string) *TemplatedEmailBuilder {
+	b.attachFile(fsys, name, "")
+	return b
+}
+
+// AttachInline adds an inline image, referenced from the template HTML body as <img src="cid:contentID">
+func (b *TemplatedEmailBuilder) AttachInline(contentID, name string, r io.Reader) *TemplatedEmailBuilder {
+	b.attach(name, contentID, r)
+	return b
+}
+
+// AttachInlineFile adds an inline image read from a file system
+func (b *TemplatedEmailBuilder) AttachInlineFile(contentID string, fsys fs.FS, name string) *TemplatedEmailBuilder {
+	b.attachFile(fsys, name, contentID)
+	return b
+}
+
+// Build returns the TemplatedEmail, or the errors of the attachments and of TemplatedEmail.Validate
+func (b *TemplatedEmailBuilder) Build() (TemplatedEmail, error) {
+	email := TemplatedEmail{
+		TemplateID:    b.templateID,
+		TemplateAlias: b.templateAlias,
+		TemplateModel: b.model,
+		InlineCSS:     b.inlineCSS,
+		From:          b.from,
+		To:            strings.Join(b.to, ", "),
+		Cc:            strings.Join(b.cc, ", "),
+		Bcc:           strings.Join(b.bcc, ", "),
+		Tag:           b.tag,
+		ReplyTo:       strings.Join(b.replyTo, ", "),
+		Headers:       b.headers,
+		TrackOpens:    b.trackOpens,
+		TrackLinks:    b.trackLinks,
+		Attachments:   b.attachments,
+		MessageStream: b.stream,
+		Metadata:      b.metadata,
+	}
+	return email, errors.Join(append([]error{email.Validate()}, b.errs...)...)
+}
@@ -0,0 +1,109 @@
+package postmark
+
+import (
+	"errors"
+	"net/mail"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmailBuilder(t *testing.T) {
+	fsys := fstest.MapFS{
+		"static/logo.png":   {Data: []byte("\x89PNG\r\n\x1a\n")},
+		"reports/march.pdf": {Data: []byte("%PDF-1.4")},
+	}
+
+	email, err := NewEmailBuilder().
+		From(mail.Address{Name: "Acme, Inc.", Address: "news@example.com"}).
+		To(mail.Address{Name: "Jöhn Doe", Address: "john@example.com"}, mail.Address{Address: "jane@example.com"}).
+		Bcc(mail.Address{Address: "audit@example.com"}).
+		ReplyTo(mail.Address{Address: "support@example.com"}).
+		Subject("March report").
+		HTMLBody(`<img src="cid:logo.png"> Your report`).
+		TextBody("Your report").
+		Tag("reports").
+		Header("X-Campaign", "march").
+		Metadata("customer", "42").
+		TrackOpens(true).
+		TrackLinks("HtmlOnly").
+		MessageStream("outbound").
+		Attach("notes.txt", strings.NewReader("hello")).
+		AttachFile(fsys, "reports/march.pdf").
+		AttachInlineFile("logo.png", fsys, "static/logo.png").
+		Build()
+	require.NoError(t, err)
+
+	assert.Equal(t, `"Acme, Inc." <news@example.com>`, email.From)
+	assert.Equal(t, "=?utf-8?q?J=C3=B6hn_Doe?= <john@example.com>, <jane@example.com>", email.To)
+	assert.Equal(t, "<audit@example.com>", email.Bcc)
+	assert.Empty(t, email.Cc)
+	assert.Equal(t, []Header{{Name: "X-Campaign", Value: "march"}}, email.Headers)
+	assert.Equal(t, map[string]string{"customer": "42"}, email.Metadata)
+	assert.True(t, email.TrackOpens)
+
+	require.Len(t, email.Attachments, 3)
+	assert.Equal(t, "notes.txt", email.Attachments[0].Name)
+	assert.True(t, strings.HasPrefix(email.Attachments[0].ContentType, "text/plain"))
+	content, err := email.Attachments[0].Decode()
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(content))
+	assert.Equal(t, "march.pdf", email.Attachments[1].Name)
+	assert.Equal(t, "application/pdf", email.Attachments[1].ContentType)
+	assert.Empty(t, email.Attachments[1].ContentID)
+	assert.Equal(t, "image/png", email.Attachments[2].ContentType)
+	assert.Equal(t, "cid:logo.png", email.Attachments[2].ContentID)
+
+	addresses, err := mail.ParseAddressList(email.To)
+	require.NoError(t, err)
+	assert.Equal(t, "Jöhn Doe", addresses[0].Name)
+}
+
+func TestEmailBuilderErrors(t *testing.T) {
+	readErr := errors.New("disk on fire")
+
+	email, err := NewEmailBuilder().
+		To(mail.Address{Address: "john@example.com"}).
+		Attach("unnamed", iotest.ErrReader(readErr)).
+		AttachFile(fstest.MapFS{}, "missing.txt").
+		AttachInline("img", "image", strings.NewReader("\x89PNG\r\n\x1a\n")).
+		Build()
+
+	require.ErrorIs(t, err, readErr)
+	require.ErrorIs(t, err, ErrInvalidEmailRequest)
+	assert.Contains(t, err.Error(), `attachment "missing.txt"`)
+	assert.Equal(t, "john@example.com", strings.Trim(email.To, "<>"))
+	require.Len(t, email.Attachments, 1)
+	assert.Equal(t, "image/png", email.Attachments[0].ContentType)
+	assert.Equal(t, "cid:img", email.Attachments[0].ContentID)
+}
+
+func TestTemplatedEmailBuilder(t *testing.T) {
+	email, err := NewTemplatedEmailBuilder().
+		TemplateAlias("welcome").
+		Model("name", "John").
+		Model("items", []string{"a", "b"}).
+		From(mail.Address{Address: "news@example.com"}).
+		To(mail.Address{Name: "John", Address: "john@example.com"}).
+		Cc(mail.Address{Address: "boss@example.com"}).
+		InlineCSS(true).
+		Attach("notes.txt", strings.NewReader("hello")).
+		Build()
+	require.NoError(t, err)
+
+	assert.Equal(t, "welcome", email.TemplateAlias)
+	assert.Equal(t, map[string]interface{}{"name": "John", "items": []string{"a", "b"}}, email.TemplateModel)
+	assert.Equal(t, `"John" <john@example.com>`, email.To)
+	assert.Equal(t, "<boss@example.com>", email.Cc)
+	assert.True(t, email.InlineCSS)
+	require.Len(t, email.Attachments, 1)
+
+	_, err = NewTemplatedEmailBuilder().From(mail.Address{Address: "news@example.com"}).Build()
+	var validationErr EmailValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Len(t, validationErr.Errors, 2)
+}
@@ -0,0 +1,588 @@
+package postmark
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Postmark SMTP headers, used to carry the Email fields that have no standard header
+const (
+	pmTagHeader            = "X-PM-Tag"
+	pmMetadataHeaderPrefix = "X-PM-Metadata-"
+	pmMessageStreamHeader  = "X-PM-Message-Stream"
+	pmTrackOpensHeader     = "X-PM-TrackOpens"
+	pmTrackLinksHeader     = "X-PM-TrackLinks"
+)
+
+// base64LineLength is the length of the lines of base64 encoded parts (RFC 2045)
+const base64LineLength = 76
+
+// ErrInvalidMIME is returned when a raw message cannot be parsed
+var ErrInvalidMIME = errors.New("invalid MIME message")
+
+// ErrInvalidHeader is returned by WriteMIME when a header name or value would break the message headers
+var ErrInvalidHeader = errors.New("invalid MIME header")
+
+// transportHeaders returns the headers added in transit, which are not kept when parsing a message into an Email
+func transportHeaders() []string {
+	return []string{
+		"Message-Id", "Date", "Received", "Return-Path", "Delivered-To", "Dkim-Signature", "Authentication-Results",
+		"Arc-Seal", "Arc-Message-Signature", "Arc-Authentication-Results", "Received-Spf", "Feedback-Id", "X-Pm-Message-Id",
+	}
+}
+
+// mimeEntity is a MIME part, either a leaf with an encoded body or a multipart with parts
+type mimeEntity struct {
+	header   textproto.MIMEHeader
+	body     []byte
+	parts    []mimeEntity
+	boundary string
+}
+
+// newMultipart returns a multipart entity of the given subtype (mixed, alternative, related)
+func newMultipart(subtype string, parts ...mimeEntity) mimeEntity {
+	boundary := multipart.NewWriter(io.Discard).Boundary()
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Type", mime.FormatMediaType("multipart/"+subtype, map[string]string{"boundary": boundary}))
+	return mimeEntity{header: header, parts: parts, boundary: boundary}
+}
+
+// newTextPart returns a quoted-printable text part
+func newTextPart(mediaType, text string) (mimeEntity, error) {
+	var body bytes.Buffer
+	qp := quotedprintable.NewWriter(&body)
+	if _, err := qp.Write([]byte(text)); err != nil {
+		return mimeEntity{}, err
+	}
+	if err := qp.Close(); err != nil {
+		return mimeEntity{}, err
+	}
+
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Type", mime.FormatMediaType(mediaType, map[string]string{"charset": "utf-8"}))
+	header.Set("Content-Transfer-Encoding", "quoted-printable")
+	return mimeEntity{header: header, body: body.Bytes()}, nil
+}
+
+// newAttachmentPart returns a base64 attachment part, inline when it has a ContentID
+func newAttachmentPart(attachment Attachment) (mimeEntity, error) {
+	content, err := attachment.Decode()
+	if err != nil {
+		return mimeEntity{}, fmt.Errorf("attachment %q: %w", attachment.Name, err)
+	}
+
+	encoded := base64.StdEncoding.EncodeToString(content)
+	var body bytes.Buffer
+	for len(encoded) > base64LineLength {
+		body.WriteString(encoded[:base64LineLength] + "\r\n")
+		encoded = encoded[base64LineLength:]
+	}
+	body.WriteString(encoded)
+
+	contentType := attachment.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	disposition := "attachment"
+
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Type", mime.FormatMediaType(contentType, map[string]string{"name": attachment.Name}))
+	header.Set("Content-Transfer-Encoding", "base64")
+	if attachment.ContentID != "" {
+		if strings.ContainsAny(attachment.ContentID, "\r\n") {
+			return mimeEntity{}, fmt.Errorf("%w: Content-Id of attachment %q has a line break", ErrInvalidHeader, attachment.Name)
+		}
+		disposition = "inline"
+		header.Set("Content-Id", "<"+strings.TrimPrefix(attachment.ContentID, "cid:")+">")
+	}
+	header.Set("Content-Disposition", mime.FormatMediaType(disposition, map[string]string{"filename": attachment.Name}))
+	return mimeEntity{header: header, body: body.Bytes()}, nil
+}
+
+// checkHeader returns an error when the name is not a valid header field name (printable ASCII without colon),
+// or when the value has a CR or LF that does not fold the line
+func checkHeader(name, value string) error {
+	if name == "" || strings.IndexFunc(name, func(r rune) bool { return r <= ' ' || r > '~' || r == ':' }) >= 0 {
+		return fmt.Errorf("%w: name %q", ErrInvalidHeader, name)
+	}
+	unfolded := strings.NewReplacer("\r\n ", "", "\r\n\t", "").Replace(value)
+	if strings.ContainsAny(unfolded, "\r\n") {
+		return fmt.Errorf("%w: value of %s has a line break", ErrInvalidHeader, name)
+	}
+	return nil
+}
+
+// writeHeader writes MIME headers sorted by name, after checking every name and value
+func writeHeader(w io.Writer, header textproto.MIMEHeader) error {
+	names := make([]string, 0, len(header))
+	for name, values := range header {
+		for _, value := range values {
+			if err := checkHeader(name, value); err != nil {
+				return err
+			}
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		for _, value := range header[name] {
+			if _, err := fmt.Fprintf(w, "%s: %s\r\n", name, value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// writeBody writes the body of the entity, including its parts
+func (m mimeEntity) writeBody(w io.Writer) error {
+	if m.parts == nil {
+		_, err := w.Write(m.body)
+		return err
+	}
+
+	mw := multipart.NewWriter(w)
+	if err := mw.SetBoundary(m.boundary); err != nil {
+		return err
+	}
+	for _, part := range m.parts {
+		pw, err := mw.CreatePart(part.header)
+		if err != nil {
+			return err
+		}
+		if err = part.writeBody(pw); err != nil {
+			return err
+		}
+	}
+	return mw.Close()
+}
+
+// formatAddressHeader re-encodes an address list header, folding it one address per line
+func formatAddressHeader(list string) string {
+	addresses, err := mail.ParseAddressList(list)
+	if err != nil {
+		return mime.QEncoding.Encode("utf-8", list)
+	}
+	return strings.Join(formatAddresses(derefAddresses(addresses)), ",\r\n ")
+}
+
+// derefAddresses returns the addresses as values
+func derefAddresses(addresses []*mail.Address) []mail.Address {
+	values := make([]mail.Address, 0, len(addresses))
+	for _, address := range addresses {
+		values = append(values, *address)
+	}
+	return values
+}
+
+// body returns the MIME structure of the email: text and HTML alternatives, related inline parts and attachments
+func (e Email) body() (mimeEntity, error) {
+	var alternatives []mimeEntity
+	if e.TextBody != "" || e.HTMLBody == "" {
+		text, err := newTextPart("text/plain", e.TextBody)
+		if err != nil {
+			return mimeEntity{}, err
+		}
+		alternatives = append(alternatives, text)
+	}
+	if e.HTMLBody != "" {
+		html, err := newTextPart("text/html", e.HTMLBody)
+		if err != nil {
+			return mimeEntity{}, err
+		}
+		alternatives = append(alternatives, html)
+	}
+
+	body := alternatives[0]
+	if len(alternatives) > 1 {
+		body = newMultipart("alternative", alternatives...)
+	}
+
+	var inline, attached []mimeEntity
+	for _, attachment := range e.Attachments {
+		part, err := newAttachmentPart(attachment)
+		if err != nil {
+			return mimeEntity{}, err
+		}
+		if attachment.ContentID != "" {
+			inline = append(inline, part)
+		} else {
+			attached = append(attached, part)
+		}
+	}
+
+	if len(inline) > 0 {
+		body = newMultipart("related", append([]mimeEntity{body}, inline...)...)
+	}
+	if len(attached) > 0 {
+		body = newMultipart("mixed", append([]mimeEntity{body}, attached...)...)
+	}
+	return body, nil
+}
+
+// WriteMIME writes the email as an RFC 5322 message. Tag, Metadata, MessageStream and tracking options are
+// written as Postmark SMTP headers (X-PM-Tag, X-PM-Metadata-*, ...), so ParseMIME can restore them.
+// Header names that are not valid field names and values with line breaks are rejected with ErrInvalidHeader.
+func (e Email) WriteMIME(w io.Writer) error {
+	body, err := e.body()
+	if err != nil {
+		return err
+	}
+
+	header := make(textproto.MIMEHeader)
+	header.Set("Mime-Version", "1.0")
+	header.Set("Date", time.Now().Format(time.RFC1123Z))
+	header.Set("Subject", mime.QEncoding.Encode("utf-8", e.Subject))
+	for _, field := range []struct{ name, value string }{
+		{"From", e.From}, {"To", e.To}, {"Cc", e.Cc}, {"Bcc", e.Bcc}, {"Reply-To", e.ReplyTo},
+	} {
+		if field.value != "" {
+			header.Set(field.name, formatAddressHeader(field.value))
+		}
+	}
+
+	// The X-PM headers are not canonicalized, Postmark documents them with this casing
+	if e.Tag != "" {
+		header[pmTagHeader] = []string{mime.QEncoding.Encode("utf-8", e.Tag)}
+	}
+	for key, value := range e.Metadata {
+		header[pmMetadataHeaderPrefix+key] = []string{mime.QEncoding.Encode("utf-8", value)}
+	}
+	if e.MessageStream != "" {
+		header[pmMessageStreamHeader] = []string{e.MessageStream}
+	}
+	if e.TrackOpens {
+		header[pmTrackOpensHeader] = []string{"true"}
+	}
+	if e.TrackLinks != "" {
+		header[pmTrackLinksHeader] = []string{e.TrackLinks}
+	}
+	for _, h := range e.Headers {
+		header.Add(h.Name, mime.QEncoding.Encode("utf-8", h.Value))
+	}
+	for name, values := range body.header {
+		header[name] = values
+	}
+
+	if err = writeHeader(w, header); err != nil {
+		return err
+	}
+	if _, err = io.WriteString(w, "\r\n"); err != nil {
+		return err
+	}
+	return body.writeBody(w)
+}
+
+// MIME returns the email as an RFC 5322 message, see WriteMIME
+func (e Email) MIME() ([]byte, error) {
+	var buf bytes.Buffer
+	err := e.WriteMIME(&buf)
+	return buf.Bytes(), err
+}
+
+// mimeContent holds the bodies and attachments found in a MIME message
+type mimeContent struct {
+	textBody    string
+	htmlBody    string
+	attachments []Attachment
+}
+
+// walk collects the bodies and attachments of a MIME entity and its parts
+func (c *mimeContent) walk(header textproto.MIMEHeader, body io.Reader) error {
+	mediaType, params, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		mediaType, params = "text/plain", map[string]string{}
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		reader := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := reader.NextRawPart()
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("%w: %w", ErrInvalidMIME, err)
+			}
+			if err = c.walk(part.Header, part); err != nil {
+				return err
+			}
+		}
+	}
+
+	data, err := io.ReadAll(decodeTransferEncoding(header.Get("Content-Transfer-Encoding"), body))
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidMIME, err)
+	}
+
+	disposition, dispositionParams, _ := mime.ParseMediaType(header.Get("Content-Disposition"))
+	name := dispositionParams["filename"]
+	if name == "" {
+		name = params["name"]
+	}
+
+	switch {
+	case disposition != "attachment" && name == "" && mediaType == "text/plain" && c.textBody == "":
+		c.textBody = decodeCharset(data, params["charset"])
+	case disposition != "attachment" && name == "" && mediaType == "text/html" && c.htmlBody == "":
+		c.htmlBody = decodeCharset(data, params["charset"])
+	default:
+		attachment := Attachment{
+			Name:        name,
+			Content:     base64.StdEncoding.EncodeToString(data),
+			ContentType: mediaType,
+		}
+		if attachment.Name == "" {
+			attachment.Name = "attachment"
+		}
+		if id := strings.Trim(header.Get("Content-Id"), "<> "); id != "" {
+			attachment.ContentID = "cid:" + id
+		}
+		c.attachments = append(c.attachments, attachment)
+	}
+	return nil
+}
+
+// decodeTransferEncoding returns a reader that decodes a base64 or quoted-printable body
+func decodeTransferEncoding(encoding string, body io.Reader) io.Reader {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "base64":
+		return base64.NewDecoder(base64.StdEncoding, newlineStripper{body})
+	case "quoted-printable":
+		return quotedprintable.NewReader(body)
+	default:
+		return body
+	}
+}
+
+// newlineStripper removes the line breaks of a base64 body
+type newlineStripper struct {
+	r io.Reader
+}
+
+// Read reads the body without its line breaks
+func (s newlineStripper) Read(p []byte) (int, error) {
+	n, err := s.r.Read(p)
+	kept := 0
+	for _, b := range p[:n] {
+		if b != '\r' && b != '\n' {
+			p[kept] = b
+			kept++
+		}
+	}
+	return kept, err
+}
+
+// decodeCharset returns the text of a body, converting ISO-8859-1 and windows-1252 to UTF-8
+func decodeCharset(data []byte, charset string) string {
+	// windows1252 maps the bytes 0x80 to 0x9F of windows-1252, the unassigned ones map to themselves
+	windows1252 := [32]rune{
+		'\u20AC', '\u0081', '\u201A', '\u0192', '\u201E', '\u2026', '\u2020', '\u2021',
+		'\u02C6', '\u2030', '\u0160', '\u2039', '\u0152', '\u008D', '\u017D', '\u008F',
+		'\u0090', '\u2018', '\u2019', '\u201C', '\u201D', '\u2022', '\u2013', '\u2014',
+		'\u02DC', '\u2122', '\u0161', '\u203A', '\u0153', '\u009D', '\u017E', '\u0178',
+	}
+
+	charset = strings.ToLower(charset)
+	switch charset {
+	case "iso-8859-1", "latin1", "windows-1252", "cp1252":
+		runes := make([]rune, 0, len(data))
+		for _, b := range data {
+			if b >= 0x80 && b <= 0x9F && (charset == "windows-1252" || charset == "cp1252") {
+				runes = append(runes, windows1252[b-0x80])
+				continue
+			}
+			runes = append(runes, rune(b))
+		}
+		return string(runes)
+	default:
+		return string(data)
+	}
+}
+
+// decodeHeader decodes the RFC 2047 encoded words of a header value
+func decodeHeader(value string) string {
+	decoded, err := new(mime.WordDecoder).DecodeHeader(value)
+	if err != nil {
+		return value
+	}
+	return decoded
+}
+
+// addressHeader returns the addresses of a header, formatted and comma separated
+func addressHeader(header mail.Header, name string) string {
+	addresses, err := header.AddressList(name)
+	if err != nil {
+		return decodeHeader(header.Get(name))
+	}
+	return strings.Join(formatAddresses(derefAddresses(addresses)), ", ")
+}
+
+// readMIME parses a raw message into its headers and content
+func readMIME(r io.Reader) (mail.Header, mimeContent, error) {
+	msg, err := mail.ReadMessage(r)
+	if err != nil {
+		return nil, mimeContent{}, fmt.Errorf("%w: %w", ErrInvalidMIME, err)
+	}
+
+	var content mimeContent
+	err = content.walk(textproto.MIMEHeader(msg.Header), msg.Body)
+	return msg.Header, content, err
+}
+
+// sortedHeaderNames returns the names of the headers in order
+func sortedHeaderNames(header mail.Header) []string {
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ParseMIME parses a raw RFC 5322 message, e.g. one written by WriteMIME or returned by GetOutboundMessageDump,
+// into an Email that can be sent again. Postmark SMTP headers set Tag, Metadata (with lower-cased keys),
+// MessageStream and the tracking options. Headers added in transit, like Received or Message-ID, are dropped.
+func ParseMIME(r io.Reader) (Email, error) {
+	header, content, err := readMIME(r)
+	if err != nil {
+		return Email{}, err
+	}
+
+	email := Email{
+		From:        addressHeader(header, "From"),
+		To:          addressHeader(header, "To"),
+		Cc:          addressHeader(header, "Cc"),
+		Bcc:         addressHeader(header, "Bcc"),
+		ReplyTo:     addressHeader(header, "Reply-To"),
+		Subject:     decodeHeader(header.Get("Subject")),
+		TextBody:    content.textBody,
+		HTMLBody:    content.htmlBody,
+		Attachments: content.attachments,
+	}
+
+	for _, name := range sortedHeaderNames(header) {
+		canonical := textproto.CanonicalMIMEHeaderKey(name)
+		value := decodeHeader(header.Get(name))
+		switch {
+		case canonical == textproto.CanonicalMIMEHeaderKey(pmTagHeader):
+			email.Tag = value
+		case canonical == textproto.CanonicalMIMEHeaderKey(pmMessageStreamHeader):
+			email.MessageStream = value
+		case canonical == textproto.CanonicalMIMEHeaderKey(pmTrackOpensHeader):
+			email.TrackOpens, _ = strconv.ParseBool(value)
+		case canonical == textproto.CanonicalMIMEHeaderKey(pmTrackLinksHeader):
+			email.TrackLinks = value
+		case strings.HasPrefix(canonical, textproto.CanonicalMIMEHeaderKey(pmMetadataHeaderPrefix)):
+			if email.Metadata == nil {
+				email.Metadata = make(map[string]string)
+			}
+			email.Metadata[strings.ToLower(canonical[len(pmMetadataHeaderPrefix):])] = value
+		case slices.Contains(reservedHeaders(), canonical), slices.Contains(transportHeaders(), canonical):
+		default:
+			for _, v := range header[name] {
+				email.Headers = append(email.Headers, Header{Name: canonical, Value: decodeHeader(v)})
+			}
+		}
+	}
+	return email, nil
+}
+
+// recipients returns the addresses of a header as Recipients
+func recipients(header mail.Header, name string) []Recipient {
+	addresses, err := header.AddressList(name)
+	if err != nil {
+		return nil
+	}
+	list := make([]Recipient, 0, len(addresses))
+	for _, address := range addresses {
+		list = append(list, Recipient{Name: address.Name, Email: address.Address})
+	}
+	return list
+}
+
+// ParseInboundMIME parses a raw RFC 5322 message into an InboundMessage, like Postmark does for inbound email.
+// Every header is kept in Headers, except the ones with a dedicated field.
+func ParseInboundMIME(r io.Reader) (InboundMessage, error) {
+	header, content, err := readMIME(r)
+	if err != nil {
+		return InboundMessage{}, err
+	}
+
+	msg := InboundMessage{
+		To:          addressHeader(header, "To"),
+		ToFull:      recipients(header, "To"),
+		Cc:          addressHeader(header, "Cc"),
+		CcFull:      recipients(header, "Cc"),
+		ReplyTo:     addressHeader(header, "Reply-To"),
+		Subject:     decodeHeader(header.Get("Subject")),
+		Date:        header.Get("Date"),
+		MessageID:   strings.Trim(header.Get("Message-Id"), "<> "),
+		TextBody:    content.textBody,
+		HTMLBody:    content.htmlBody,
+		Attachments: content.attachments,
+	}
+	if from := recipients(header, "From"); len(from) > 0 {
+		msg.FromFull = from[0]
+		msg.From = from[0].Email
+		msg.FromName = from[0].Name
+	}
+	if len(msg.ToFull) > 0 {
+		msg.OriginalRecipient = msg.ToFull[0].Email
+		if local, _, ok := strings.Cut(msg.OriginalRecipient, "@"); ok {
+			_, msg.MailboxHash, _ = strings.Cut(local, "+")
+		}
+	}
+
+	for _, name := range sortedHeaderNames(header) {
+		switch textproto.CanonicalMIMEHeaderKey(name) {
+		case "From", "To", "Cc", "Reply-To", "Subject", "Date", "Content-Type", "Content-Transfer-Encoding":
+			continue
+		}
+		for _, value := range header[name] {
+			msg.Headers = append(msg.Headers, Header{Name: name, Value: decodeHeader(value)})
+		}
+	}
+	return msg, nil
+}
+
+// Email returns the message as an Email that can be sent again. The raw Body is parsed when the message has one,
+// otherwise the Email is built from the message fields, without attachments.
+func (m OutboundMessage) Email() (Email, error) {
+	if m.Body != "" {
+		return ParseMIME(strings.NewReader(m.Body))
+	}
+
+	email := Email{
+		From:     m.From,
+		Subject:  m.Subject,
+		Tag:      m.Tag,
+		HTMLBody: m.HTMLBody,
+		TextBody: m.TextBody,
+	}
+	for _, field := range []struct {
+		dst        *string
+		recipients []Recipient
+	}{{&email.To, m.To}, {&email.Cc, m.Cc}, {&email.Bcc, m.Bcc}} {
+		addresses := make([]mail.Address, 0, len(field.recipients))
+		for _, recipient := range field.recipients {
+			addresses = append(addresses, mail.Address{Name: recipient.Name, Address: recipient.Email})
+		}
+		*field.dst = strings.Join(formatAddresses(addresses), ", ")
+	}
+	return email, nil
+}
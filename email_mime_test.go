@@ -0,0 +1,214 @@
+package postmark
+
+import (
+	"bytes"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rawInboundMessage is a message as received from another mail server
+const rawInboundMessage = "Received: from mx.example.net by inbound.postmarkapp.com\r\n" +
+	"Message-ID: <abc123@example.net>\r\n" +
+	"Date: Tue, 13 Oct 2026 10:00:00 +0000\r\n" +
+	"From: =?iso-8859-1?q?Ren=E9?= <rene@example.net>\r\n" +
+	"To: Support <support+ticket42@inbound.example.com>\r\n" +
+	"Cc: boss@example.net\r\n" +
+	"Subject: =?utf-8?q?R=C3=A9sum=C3=A9?=\r\n" +
+	"X-Spam-Score: 1.5\r\n" +
+	"MIME-Version: 1.0\r\n" +
+	"Content-Type: multipart/mixed; boundary=outer\r\n" +
+	"\r\n" +
+	"--outer\r\n" +
+	"Content-Type: multipart/alternative; boundary=inner\r\n" +
+	"\r\n" +
+	"--inner\r\n" +
+	"Content-Type: text/plain; charset=iso-8859-1\r\n" +
+	"Content-Transfer-Encoding: quoted-printable\r\n" +
+	"\r\n" +
+	"Voil=E0 mon r=E9sum=E9\r\n" +
+	"--inner\r\n" +
+	"Content-Type: text/html; charset=utf-8\r\n" +
+	"\r\n" +
+	"<p>Voilà</p>\r\n" +
+	"--inner--\r\n" +
+	"--outer\r\n" +
+	"Content-Type: application/pdf; name=\"cv.pdf\"\r\n" +
+	"Content-Disposition: attachment; filename=\"cv.pdf\"\r\n" +
+	"Content-Transfer-Encoding: base64\r\n" +
+	"\r\n" +
+	"JVBERi0x\r\n" +
+	"LjQ=\r\n" +
+	"--outer--\r\n"
+
+func TestEmailMIMERoundTrip(t *testing.T) {
+	email := Email{
+		From:          `"Acme, Inc." <news@example.com>`,
+		To:            "Jöhn Doe <john@example.com>, jane@example.com",
+		Cc:            "cc@example.com",
+		Bcc:           "audit@example.com",
+		ReplyTo:       "support@example.com",
+		Subject:       "Résumé attached",
+		HTMLBody:      `<p>See <img src="cid:logo.png"></p>`,
+		TextBody:      "See the attachment, it's a long line that must be wrapped by the quoted-printable encoder because it is over 76 characters",
+		Tag:           "résumés",
+		Headers:       []Header{{Name: "X-Campaign", Value: "spring"}},
+		TrackOpens:    true,
+		TrackLinks:    "HtmlOnly",
+		Metadata:      map[string]string{"customer": "42"},
+		MessageStream: "outbound",
+		Attachments: []Attachment{
+			{Name: "cv.pdf", Content: base64.StdEncoding.EncodeToString(bytes.Repeat([]byte("%PDF"), 50)), ContentType: "application/pdf"},
+			{Name: "logo.png", Content: base64.StdEncoding.EncodeToString([]byte("\x89PNG")), ContentType: "image/png", ContentID: "cid:logo.png"},
+		},
+	}
+
+	raw, err := email.MIME()
+	require.NoError(t, err)
+	assert.Contains(t, string(raw), "X-PM-Tag: =?utf-8?q?r=C3=A9sum=C3=A9s?=\r\n")
+	assert.Contains(t, string(raw), "X-PM-Metadata-customer: 42\r\n")
+	assert.Contains(t, string(raw), "Content-Type: multipart/mixed;")
+	assert.Contains(t, string(raw), "Content-Type: multipart/related;")
+	assert.Contains(t, string(raw), "Content-Type: multipart/alternative;")
+	assert.Contains(t, string(raw), "Content-Id: <logo.png>")
+	for _, line := range strings.Split(string(raw), "\r\n") {
+		assert.LessOrEqual(t, len(line), 998)
+	}
+
+	parsed, err := ParseMIME(bytes.NewReader(raw))
+	require.NoError(t, err)
+
+	assert.Equal(t, `"Acme, Inc." <news@example.com>`, parsed.From)
+	assert.Equal(t, `=?utf-8?q?J=C3=B6hn_Doe?= <john@example.com>, <jane@example.com>`, parsed.To)
+	assert.Equal(t, "<cc@example.com>", parsed.Cc)
+	assert.Equal(t, "<audit@example.com>", parsed.Bcc)
+	assert.Equal(t, "<support@example.com>", parsed.ReplyTo)
+	assert.Equal(t, email.Subject, parsed.Subject)
+	assert.Equal(t, email.HTMLBody, parsed.HTMLBody)
+	assert.Equal(t, email.TextBody, parsed.TextBody)
+	assert.Equal(t, email.Tag, parsed.Tag)
+	assert.Equal(t, email.Headers, parsed.Headers)
+	assert.True(t, parsed.TrackOpens)
+	assert.Equal(t, email.TrackLinks, parsed.TrackLinks)
+	assert.Equal(t, email.Metadata, parsed.Metadata)
+	assert.Equal(t, email.MessageStream, parsed.MessageStream)
+	assert.ElementsMatch(t, email.Attachments, parsed.Attachments)
+	require.NoError(t, parsed.Validate())
+}
+
+func TestEmailMIMETextOnly(t *testing.T) {
+	raw, err := Email{From: "a@example.com", To: "b@example.com", TextBody: "Hello"}.MIME()
+	require.NoError(t, err)
+	assert.Contains(t, string(raw), "Content-Type: text/plain; charset=utf-8\r\n")
+	assert.NotContains(t, string(raw), "multipart")
+
+	_, err = Email{Attachments: []Attachment{{Name: "bad", Content: "%%%"}}}.MIME()
+	require.Error(t, err)
+}
+
+func TestEmailMIMERejectsInvalidHeaders(t *testing.T) {
+	base := Email{From: "a@example.com", To: "b@example.com", TextBody: "Hello"}
+	tests := []struct {
+		name   string
+		modify func(*Email)
+	}{
+		{"message stream", func(e *Email) { e.MessageStream = "outbound\r\nBcc: victim@example.com" }},
+		{"track links", func(e *Email) { e.TrackLinks = "None\nBcc: victim@example.com" }},
+		{"metadata key line break", func(e *Email) { e.Metadata = map[string]string{"id\r\nBcc": "1"} }},
+		{"metadata key colon", func(e *Email) { e.Metadata = map[string]string{"id: x": "1"} }},
+		{"header name", func(e *Email) { e.Headers = []Header{{Name: "X-Custom\r\nBcc", Value: "1"}} }},
+		{"header name space", func(e *Email) { e.Headers = []Header{{Name: "X Custom", Value: "1"}} }},
+		{"empty header name", func(e *Email) { e.Headers = []Header{{Value: "1"}} }},
+		{"content id", func(e *Email) {
+			e.Attachments = []Attachment{{Name: "a.png", Content: "aGk=", ContentType: "image/png", ContentID: "cid:a\r\nX-Bad: 1"}}
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			email := base
+			tt.modify(&email)
+			_, err := email.MIME()
+			require.ErrorIs(t, err, ErrInvalidHeader)
+		})
+	}
+
+	// Values written by WriteMIME itself may fold lines, and header values with line breaks are Q-encoded
+	email := base
+	email.Cc = "c@example.com, d@example.com"
+	email.Headers = []Header{{Name: "X-Custom", Value: "one\r\ntwo"}}
+	raw, err := email.MIME()
+	require.NoError(t, err)
+	assert.Contains(t, string(raw), "Cc: <c@example.com>,\r\n <d@example.com>\r\n")
+	assert.NotContains(t, string(raw), "\r\ntwo")
+}
+
+func TestParseMIME(t *testing.T) {
+	email, err := ParseMIME(strings.NewReader(rawInboundMessage))
+	require.NoError(t, err)
+
+	assert.Equal(t, "=?utf-8?q?Ren=C3=A9?= <rene@example.net>", email.From)
+	assert.Equal(t, "Résumé", email.Subject)
+	assert.Equal(t, "Voilà mon résumé", email.TextBody)
+	assert.Equal(t, "<p>Voilà</p>", email.HTMLBody)
+	assert.Equal(t, []Header{{Name: "X-Spam-Score", Value: "1.5"}}, email.Headers)
+	require.Len(t, email.Attachments, 1)
+	assert.Equal(t, Attachment{Name: "cv.pdf", Content: "JVBERi0xLjQ=", ContentType: "application/pdf"}, email.Attachments[0])
+
+	_, err = ParseMIME(strings.NewReader("not a message"))
+	require.ErrorIs(t, err, ErrInvalidMIME)
+}
+
+func TestDecodeCharset(t *testing.T) {
+	data := []byte{'C', 'a', 'f', 0xE9, ' ', 0x80, '5', ' ', 0x93, 'o', 'k', 0x94, 0x85}
+
+	assert.Equal(t, "Caf\u00e9 \u20ac5 \u201cok\u201d\u2026", decodeCharset(data, "Windows-1252"))
+	assert.Equal(t, "Caf\u00e9 \u00805 \u0093ok\u0094\u0085", decodeCharset(data, "iso-8859-1"))
+	assert.Equal(t, "Hi", decodeCharset([]byte("Hi"), "utf-8"))
+}
+
+func TestParseInboundMIME(t *testing.T) {
+	msg, err := ParseInboundMIME(strings.NewReader(rawInboundMessage))
+	require.NoError(t, err)
+
+	assert.Equal(t, "rene@example.net", msg.From)
+	assert.Equal(t, "René", msg.FromName)
+	assert.Equal(t, []Recipient{{Name: "Support", Email: "support+ticket42@inbound.example.com"}}, msg.ToFull)
+	assert.Equal(t, []Recipient{{Email: "boss@example.net"}}, msg.CcFull)
+	assert.Equal(t, "ticket42", msg.MailboxHash)
+	assert.Equal(t, "support+ticket42@inbound.example.com", msg.OriginalRecipient)
+	assert.Equal(t, "abc123@example.net", msg.MessageID)
+	assert.Equal(t, "Résumé", msg.Subject)
+	assert.Equal(t, "Voilà mon résumé", msg.TextBody)
+	score, ok := msg.SpamScore()
+	assert.True(t, ok)
+	assert.InDelta(t, 1.5, score, 0)
+	assert.Contains(t, msg.Headers, Header{Name: "Received", Value: "from mx.example.net by inbound.postmarkapp.com"})
+
+	date, err := msg.Time()
+	require.NoError(t, err)
+	assert.Equal(t, 2026, date.Year())
+}
+
+func TestOutboundMessageEmail(t *testing.T) {
+	email, err := OutboundMessage{Body: rawInboundMessage}.Email()
+	require.NoError(t, err)
+	assert.Equal(t, "Résumé", email.Subject)
+
+	email, err = OutboundMessage{
+		From:     "news@example.com",
+		To:       []Recipient{{Name: "John", Email: "john@example.com"}, {Email: "jane@example.com"}},
+		Bcc:      []Recipient{{Email: "audit@example.com"}},
+		Subject:  "Hi",
+		TextBody: "Hello",
+		Tag:      "welcome",
+	}.Email()
+	require.NoError(t, err)
+	assert.Equal(t, `"John" <john@example.com>, <jane@example.com>`, email.To)
+	assert.Equal(t, "<audit@example.com>", email.Bcc)
+	assert.Empty(t, email.Cc)
+	assert.Equal(t, "welcome", email.Tag)
+	require.NoError(t, email.Validate())
+}
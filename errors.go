@@ -0,0 +1,131 @@
+package postmark
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Postmark API error codes
+// https://postmarkapp.com/developer/api/overview#error-codes
+const (
+	ErrorCodeInvalidAPIToken             int64 = 10
+	ErrorCodeMaintenance                 int64 = 100
+	ErrorCodeInvalidEmailRequest         int64 = 300
+	ErrorCodeSenderSignatureNotFound     int64 = 400
+	ErrorCodeSenderSignatureNotConfirmed int64 = 401
+	ErrorCodeInvalidJSON                 int64 = 402
+	ErrorCodeIncompatibleJSON            int64 = 403
+	ErrorCodeNotAllowedToSend            int64 = 405
+	ErrorCodeInactiveRecipient           int64 = 406
+	ErrorCodeJSONRequired                int64 = 409
+	ErrorCodeTooManyBatchMessages        int64 = 410
+	ErrorCodeForbiddenAttachmentType     int64 = 411
+	ErrorCodeAccountPending              int64 = 412
+	ErrorCodeAccountMayNotSend           int64 = 413
+	ErrorCodeTemplateNotFound            int64 = 1101
+)
+
+// Sentinel errors for common Postmark error codes, match them with errors.Is
+var (
+	ErrInvalidAPIToken             = errors.New("bad or missing API token")
+	ErrMaintenance                 = errors.New("postmark API is offline for maintenance")
+	ErrInvalidEmailRequest         = errors.New("invalid email request")
+	ErrSenderSignatureNotFound     = errors.New("sender signature not found")
+	ErrSenderSignatureNotConfirmed = errors.New("sender signature not confirmed")
+	ErrInvalidJSON                 = errors.New("invalid JSON")
+	ErrNotAllowedToSend            = errors.New("not allowed to send")
+	ErrInactiveRecipient           = errors.New("inactive recipient")
+	ErrTooManyBatchMessages        = errors.New("too many batch messages")
+	ErrForbiddenAttachmentType     = errors.New("forbidden attachment type")
+	ErrAccountPending              = errors.New("account is pending")
+	ErrAccountMayNotSend           = errors.New("account may not send")
+	ErrTemplateNotFound            = errors.New("template not found")
+)
+
+// APIError represents errors returned by Postmark
+type APIError struct {
+	// ErrorCode: see error codes here (https://postmarkapp.com/developer/api/overview#error-codes)
+	ErrorCode int64 `json:"ErrorCode"`
+	// Message contains error details
+	Message string `json:"Message"`
+	// StatusCode is the HTTP status of the response
+	StatusCode int `json:"-"`
+	// Method is the HTTP method of the failed request
+	Method string `json:"-"`
+	// Path is the API path of the failed request, relative to the BaseURL
+	Path string `json:"-"`
+	// Body is the raw response body
+	Body string `json:"-"`
+}
+
+// Error returns the error message details
+func (res APIError) Error() string {
+	if res.Message == "" && res.StatusCode != 0 {
+		return fmt.Sprintf("request failed with status %d", res.StatusCode)
+	}
+	return res.Message
+}
+
+// Is reports whether the error matches one of the sentinel errors for its ErrorCode
+func (res APIError) Is(target error) bool {
+	sentinel := errorForCode(res.ErrorCode)
+	return sentinel != nil && sentinel == target //nolint:errorlint // comparing sentinels is the purpose of Is
+}
+
+// errorForCode returns the sentinel error for a Postmark error code, or nil if there is none
+func errorForCode(code int64) error {
+	switch code {
+	case ErrorCodeInvalidAPIToken:
+		return ErrInvalidAPIToken
+	case ErrorCodeMaintenance:
+		return ErrMaintenance
+	case ErrorCodeInvalidEmailRequest:
+		return ErrInvalidEmailRequest
+	case ErrorCodeSenderSignatureNotFound:
+		return ErrSenderSignatureNotFound
+	case ErrorCodeSenderSignatureNotConfirmed:
+		return ErrSenderSignatureNotConfirmed
+	case ErrorCodeInvalidJSON, ErrorCodeIncompatibleJSON, ErrorCodeJSONRequired:
+		return ErrInvalidJSON
+	case ErrorCodeNotAllowedToSend:
+		return ErrNotAllowedToSend
+	case ErrorCodeInactiveRecipient:
+		return ErrInactiveRecipient
+	case ErrorCodeTooManyBatchMessages:
+		return ErrTooManyBatchMessages
+	case ErrorCodeForbiddenAttachmentType:
+		return ErrForbiddenAttachmentType
+	case ErrorCodeAccountPending:
+		return ErrAccountPending
+	case ErrorCodeAccountMayNotSend:
+		return ErrAccountMayNotSend
+	case ErrorCodeTemplateNotFound:
+		return ErrTemplateNotFound
+	default:
+		return nil
+	}
+}
+
+// ErrorCode returns the Postmark ErrorCode carried by err, or 0 if err is not an APIError
+func ErrorCode(err error) int64 {
+	var apiErr APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.ErrorCode
+	}
+	return 0
+}
+
+// errorCoder is implemented by responses that can report a failure in a successful (2xx) HTTP response
+type errorCoder interface {
+	postmarkError() APIError
+}
+
+// postmarkError returns the response as an APIError
+func (res APIError) postmarkError() APIError {
+	return res
+}
+
+// postmarkError returns the ErrorCode and Message of the response as an APIError
+func (res EmailResponse) postmarkError() APIError {
+	return APIError{ErrorCode: res.ErrorCode, Message: res.Message}
+}
@@ -0,0 +1,190 @@
+package postmark
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+)
+
+func (s *PostmarkTestSuite) TestDoRequestAPIErrorDetails() {
+	tests := []struct {
+		name        string
+		statusCode  int
+		body        string
+		wantCode    int64
+		wantMessage string
+		wantError   string
+	}{
+		{
+			name:        "json error body",
+			statusCode:  http.StatusUnprocessableEntity,
+			body:        `{"ErrorCode": 406, "Message": "You tried to send to a recipient that has been marked as inactive."}`,
+			wantCode:    ErrorCodeInactiveRecipient,
+			wantMessage: "You tried to send to a recipient that has been marked as inactive.",
+			wantError:   "You tried to send to a recipient that has been marked as inactive.",
+		},
+		{
+			name:       "non json error body",
+			statusCode: http.StatusBadGateway,
+			body:       `<html>Bad Gateway</html>`,
+			wantError:  "request failed with status 502",
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			s.mux.Post("/error-details", func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(tt.statusCode)
+				_, _ = w.Write([]byte(tt.body))
+			})
+
+			err := s.client.doRequest(context.Background(), "", http.MethodPost, "error-details", map[string]string{}, nil, serverToken)
+
+			var apiErr APIError
+			s.Require().ErrorAs(err, &apiErr)
+			s.Equal(tt.statusCode, apiErr.StatusCode)
+			s.Equal(http.MethodPost, apiErr.Method)
+			s.Equal("error-details", apiErr.Path)
+			s.Equal(tt.body, apiErr.Body)
+			s.Equal(tt.wantCode, apiErr.ErrorCode)
+			s.Equal(tt.wantMessage, apiErr.Message)
+			s.Equal(tt.wantError, err.Error())
+		})
+	}
+}
+
+func (s *PostmarkTestSuite) TestAPIErrorIs() {
+	tests := []struct {
+		name   string
+		code   int64
+		target error
+		want   bool
+	}{
+		{name: "inactive recipient", code: 406, target: ErrInactiveRecipient, want: true},
+		{name: "invalid email request", code: 300, target: ErrInvalidEmailRequest, want: true},
+		{name: "sender signature not found", code: 400, target: ErrSenderSignatureNotFound, want: true},
+		{name: "sender signature not confirmed", code: 401, target: ErrSenderSignatureNotConfirmed, want: true},
+		{name: "incompatible json is invalid json", code: 403, target: ErrInvalidJSON, want: true},
+		{name: "template not found", code: 1101, target: ErrTemplateNotFound, want: true},
+		{name: "mismatched sentinel", code: 406, target: ErrInvalidEmailRequest, want: false},
+		{name: "unknown code", code: 9999, target: ErrInactiveRecipient, want: false},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			err := fmt.Errorf("wrapped: %w", APIError{ErrorCode: tt.code, Message: "message"})
+			s.Equal(tt.want, errors.Is(err, tt.target))
+		})
+	}
+}
+
+func (s *PostmarkTestSuite) TestErrorCode() {
+	s.Equal(int64(0), ErrorCode(nil))
+	s.Equal(int64(0), ErrorCode(ErrEmailFailed))
+	s.Equal(int64(406), ErrorCode(fmt.Errorf("%w: %w", ErrEmailFailed, APIError{ErrorCode: 406})))
+}
+
+func (s *PostmarkTestSuite) TestSendEmailAPIError() {
+	s.mux.Post("/email", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_, _ = w.Write([]byte(`{"ErrorCode": 406, "Message": "Inactive recipient"}`))
+	})
+
+	_, err := s.client.SendEmail(context.Background(), getTestEmail())
+
+	s.Require().ErrorIs(err, ErrEmailFailed)
+	s.Require().ErrorIs(err, ErrInactiveRecipient)
+
+	var apiErr APIError
+	s.Require().ErrorAs(err, &apiErr)
+	s.Equal(http.StatusUnprocessableEntity, apiErr.StatusCode)
+	s.Equal("email", apiErr.Path)
+}
+
+func (s *PostmarkTestSuite) TestSendMethodsWrapAPIErrors() {
+	reject := func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_, _ = w.Write([]byte(`{"ErrorCode": 406, "Message": "Inactive recipient"}`))
+	}
+	s.mux.Post("/email/batch", reject)
+	s.mux.Post("/email/withTemplate", reject)
+	s.mux.Post("/email/batchWithTemplates", reject)
+	s.mux.Post("/email/bulk", reject)
+
+	ctx := context.Background()
+	templated := TemplatedEmail{TemplateID: 1, From: testSenderEmail, To: "receiver@example.com"}
+
+	_, err := s.client.SendEmailBatch(ctx, []Email{getTestEmail()})
+	s.Require().ErrorIs(err, ErrEmailFailed)
+	s.Require().ErrorIs(err, ErrInactiveRecipient)
+
+	_, err = s.client.SendTemplatedEmail(ctx, templated)
+	s.Require().ErrorIs(err, ErrEmailFailed)
+	s.Require().ErrorIs(err, ErrInactiveRecipient)
+
+	_, err = s.client.SendTemplatedEmailBatch(ctx, []TemplatedEmail{templated})
+	s.Require().ErrorIs(err, ErrEmailFailed)
+
+	_, err = s.client.SendBulkEmail(ctx, BulkEmail{From: testSenderEmail, Subject: "Hi", TextBody: "Hi"})
+	s.Require().ErrorIs(err, ErrEmailFailed)
+
+	_, err = s.client.SendTemplatedEmailIdempotent(ctx, "", templated)
+	s.Require().ErrorIs(err, ErrMissingIdempotencyKey)
+	s.Require().NotErrorIs(err, ErrEmailFailed, "errors found before sending are not wrapped")
+}
+
+func (s *PostmarkTestSuite) TestErrorCodeInSuccessfulResponse() {
+	// Use a separate mux, the suite mux already has parameterized routes for these paths
+	testMux := NewTestRouter()
+	testServer := httptest.NewServer(testMux)
+	defer testServer.Close()
+
+	testClient := NewClient("server-token", "account-token")
+	testClient.BaseURL = testServer.URL
+
+	tests := []struct {
+		name string
+		call func() error
+		path string
+	}{
+		{
+			name: "DeleteWebhook",
+			call: func() error { return testClient.DeleteWebhook(context.Background(), 42) },
+			path: "webhooks/42",
+		},
+		{
+			name: "BypassInboundMessage",
+			call: func() error { return testClient.BypassInboundMessage(context.Background(), "abc") },
+			path: "messages/inbound/abc/bypass",
+		},
+		{
+			name: "DeleteServer",
+			call: func() error { return testClient.DeleteServer(context.Background(), 42) },
+			path: "servers/42",
+		},
+	}
+
+	handler := func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"ErrorCode": 10, "Message": "Bad or missing API token"}`))
+	}
+	testMux.Delete("/webhooks/42", handler)
+	testMux.Put("/messages/inbound/abc/bypass", handler)
+	testMux.Delete("/servers/42", handler)
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			err := tt.call()
+
+			s.Require().ErrorIs(err, ErrInvalidAPIToken)
+
+			var apiErr APIError
+			s.Require().ErrorAs(err, &apiErr)
+			s.Equal(http.StatusOK, apiErr.StatusCode)
+			s.Equal(tt.path, apiErr.Path)
+			s.NotEmpty(apiErr.Method)
+			s.Contains(apiErr.Body, "Bad or missing API token")
+		})
+	}
+}
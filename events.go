@@ -2,6 +2,16 @@ package postmark
 
 import "time"
 
+// Webhook event record types, as found in BaseEvent.RecordType
+const (
+	RecordTypeDelivery           = "Delivery"
+	RecordTypeBounce             = "Bounce"
+	RecordTypeOpen               = "Open"
+	RecordTypeClick              = "Click"
+	RecordTypeSpamComplaint      = "SpamComplaint"
+	RecordTypeSubscriptionChange = "SubscriptionChange"
+)
+
 // BaseEvent contains fields that are common across all webhook event types
 type BaseEvent struct {
 	RecordType    string                 `json:"RecordType"`
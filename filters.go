@@ -0,0 +1,325 @@
+package postmark
+
+import (
+	"context"
+	"iter"
+	"strconv"
+	"time"
+)
+
+// statsDateFormat is the date format accepted by the stats endpoints
+const statsDateFormat = "2006-01-02"
+
+// BounceFilter holds the search options for GetBounces and Bounces
+// http://developer.postmarkapp.com/developer-api-bounce.html#bounces
+type BounceFilter struct {
+	// Type: Filter by bounce type (e.g. HardBounce, SoftBounce, Transient)
+	Type string
+	// Inactive: Filter by emails that were deactivated by Postmark due to the bounce
+	Inactive *bool
+	// EmailFilter: Filter by email address
+	EmailFilter string
+	// Tag: Filter by tag
+	Tag string
+	// MessageID: Filter by messageID
+	MessageID string
+	// FromDate: Filter messages starting from the date/time specified (inclusive)
+	FromDate time.Time
+	// ToDate: Filter messages up to the date/time specified (inclusive)
+	ToDate time.Time
+	// MessageStream: Filter by message stream ID
+	MessageStream string
+}
+
+// Options returns the filter as options for GetBounces and Bounces
+func (f BounceFilter) Options() map[string]interface{} {
+	options := make(map[string]interface{})
+	addString(options, "type", f.Type)
+	if f.Inactive != nil {
+		options["inactive"] = strconv.FormatBool(*f.Inactive)
+	}
+	addString(options, "emailFilter", f.EmailFilter)
+	addString(options, "tag", f.Tag)
+	addString(options, "messageID", f.MessageID)
+	addSearchDate(options, "fromdate", f.FromDate)
+	addSearchDate(options, "todate", f.ToDate)
+	addString(options, "messagestream", f.MessageStream)
+	return options
+}
+
+// OutboundMessageFilter holds the search options for GetOutboundMessages and OutboundMessages
+// http://developer.postmarkapp.com/developer-api-messages.html#outbound-message-search
+type OutboundMessageFilter struct {
+	// Recipient: Filter by the user who was receiving the email
+	Recipient string
+	// FromEmail: Filter by the sender email address
+	FromEmail string
+	// Tag: Filter by tag
+	Tag string
+	// Status: Filter by status (queued, sent or processed)
+	Status string
+	// FromDate: Filter messages starting from the date/time specified (inclusive)
+	FromDate time.Time
+	// ToDate: Filter messages up to the date/time specified (inclusive)
+	ToDate time.Time
+	// MessageStream: Filter by message stream ID
+	MessageStream string
+	// Metadata: Filter by metadata key/value pairs
+	Metadata map[string]string
+}
+
+// Options returns the filter as options for GetOutboundMessages and OutboundMessages
+func (f OutboundMessageFilter) Options() map[string]interface{} {
+	options := make(map[string]interface{})
+	addString(options, "recipient", f.Recipient)
+	addString(options, "fromemail", f.FromEmail)
+	addString(options, "tag", f.Tag)
+	addString(options, "status", f.Status)
+	addSearchDate(options, "fromdate", f.FromDate)
+	addSearchDate(options, "todate", f.ToDate)
+	addString(options, "messagestream", f.MessageStream)
+	for key, value := range f.Metadata {
+		addString(options, "metadata_"+key, value)
+	}
+	return options
+}
+
+// InboundMessageFilter holds the search options for GetInboundMessages and InboundMessages
+// http://developer.postmarkapp.com/developer-api-messages.html#inbound-message-search
+type InboundMessageFilter struct {
+	// Recipient: Filter by the user who was receiving the email
+	Recipient string
+	// FromEmail: Filter by the sender email address
+	FromEmail string
+	// Subject: Filter by email subject
+	Subject string
+	// MailboxHash: Filter by mailbox hash
+	MailboxHash string
+	// Tag: Filter by tag
+	Tag string
+	// Status: Filter by status (blocked, processed, queued, failed or scheduled)
+	Status string
+	// FromDate: Filter messages starting from the date/time specified (inclusive)
+	FromDate time.Time
+	// ToDate: Filter messages up to the date/time specified (inclusive)
+	ToDate time.Time
+}
+
+// Options returns the filter as options for GetInboundMessages and InboundMessages
+func (f InboundMessageFilter) Options() map[string]interface{} {
+	options := make(map[string]interface{})
+	addString(options, "recipient", f.Recipient)
+	addString(options, "fromemail", f.FromEmail)
+	addString(options, "subject", f.Subject)
+	addString(options, "mailboxhash", f.MailboxHash)
+	addString(options, "tag", f.Tag)
+	addString(options, "status", f.Status)
+	addSearchDate(options, "fromdate", f.FromDate)
+	addSearchDate(options, "todate", f.ToDate)
+	return options
+}
+
+// MessageEventFilter holds the search options for the opens and clicks searches
+// (GetOutboundMessagesOpens, GetOutboundMessagesClicks and their iterators)
+// http://developer.postmarkapp.com/developer-api-messages.html#message-opens
+type MessageEventFilter struct {
+	// Recipient: Filter by the user who was receiving the email
+	Recipient string
+	// Tag: Filter by tag
+	Tag string
+	// ClientName: Filter by client name, i.e. Outlook, Gmail
+	ClientName string
+	// ClientCompany: Filter by company, i.e. Microsoft, Apple, Google
+	ClientCompany string
+	// ClientFamily: Filter by client family, i.e. OS X, Chrome
+	ClientFamily string
+	// OSName: Filter by full OS name and specific version, i.e. OS X 10.9 Mavericks
+	OSName string
+	// OSFamily: Filter by kind of OS used without specific version, i.e. OS X, Windows
+	OSFamily string
+	// OSCompany: Filter by company which produced the OS, i.e. Apple Computer, Inc.
+	OSCompany string
+	// Platform: Filter by platform, i.e. webmail, desktop, mobile
+	Platform string
+	// Country: Filter by country the event happened in
+	Country string
+	// Region: Filter by full name of region, i.e. California
+	Region string
+	// City: Filter by full name of city, i.e. Montreal
+	City string
+	// MessageStream: Filter by message stream ID
+	MessageStream string
+}
+
+// Options returns the filter as options for the opens and clicks searches
+func (f MessageEventFilter) Options() map[string]interface{} {
+	options := make(map[string]interface{})
+	addString(options, "recipient", f.Recipient)
+	addString(options, "tag", f.Tag)
+	addString(options, "client_name", f.ClientName)
+	addString(options, "client_company", f.ClientCompany)
+	addString(options, "client_family", f.ClientFamily)
+	addString(options, "os_name", f.OSName)
+	addString(options, "os_family", f.OSFamily)
+	addString(options, "os_company", f.OSCompany)
+	addString(options, "platform", f.Platform)
+	addString(options, "country", f.Country)
+	addString(options, "region", f.Region)
+	addString(options, "city", f.City)
+	addString(options, "messagestream", f.MessageStream)
+	return options
+}
+
+// StatsFilter holds the options for GetOutboundStats and the Get*Counts stats methods
+// http://developer.postmarkapp.com/developer-api-stats.html
+type StatsFilter struct {
+	// Tag: Filter by tag
+	Tag string
+	// FromDate: Filter stats starting from the date specified (inclusive), the time of day is ignored
+	FromDate time.Time
+	// ToDate: Filter stats up to the date specified (inclusive), the time of day is ignored
+	ToDate time.Time
+	// MessageStream: Filter by message stream ID
+	MessageStream string
+}
+
+// Options returns the filter as options for the stats methods
+func (f StatsFilter) Options() map[string]interface{} {
+	options := make(map[string]interface{})
+	addString(options, "tag", f.Tag)
+	if !f.FromDate.IsZero() {
+		options["fromdate"] = f.FromDate.Format(statsDateFormat)
+	}
+	if !f.ToDate.IsZero() {
+		options["todate"] = f.ToDate.Format(statsDateFormat)
+	}
+	addString(options, "messagestream", f.MessageStream)
+	return options
+}
+
+// GetBouncesFiltered is GetBounces with a typed filter
+func (client *Client) GetBouncesFiltered(ctx context.Context, count, offset int64, filter BounceFilter) ([]Bounce, int64, error) {
+	return client.GetBounces(ctx, count, offset, filter.Options())
+}
+
+// BouncesFiltered is Bounces with a typed filter
+func (client *Client) BouncesFiltered(ctx context.Context, filter BounceFilter) iter.Seq2[Bounce, error] {
+	return client.Bounces(ctx, filter.Options())
+}
+
+// GetOutboundMessagesFiltered is GetOutboundMessages with a typed filter
+func (client *Client) GetOutboundMessagesFiltered(ctx context.Context, count, offset int64, filter OutboundMessageFilter) ([]OutboundMessage, int64, error) {
+	return client.GetOutboundMessages(ctx, count, offset, filter.Options())
+}
+
+// OutboundMessagesFiltered is OutboundMessages with a typed filter
+func (client *Client) OutboundMessagesFiltered(ctx context.Context, filter OutboundMessageFilter) iter.Seq2[OutboundMessage, error] {
+	return client.OutboundMessages(ctx, filter.Options())
+}
+
+// GetInboundMessagesFiltered is GetInboundMessages with a typed filter
+func (client *Client) GetInboundMessagesFiltered(ctx context.Context, count, offset int64, filter InboundMessageFilter) ([]InboundMessage, int64, error) {
+	return client.GetInboundMessages(ctx, count, offset, filter.Options())
+}
+
+// InboundMessagesFiltered is InboundMessages with a typed filter
+func (client *Client) InboundMessagesFiltered(ctx context.Context, filter InboundMessageFilter) iter.Seq2[InboundMessage, error] {
+	return client.InboundMessages(ctx, filter.Options())
+}
+
+// GetOutboundMessagesOpensFiltered is GetOutboundMessagesOpens with a typed filter
+func (client *Client) GetOutboundMessagesOpensFiltered(ctx context.Context, count, offset int64, filter MessageEventFilter) ([]Open, int64, error) {
+	return client.GetOutboundMessagesOpens(ctx, count, offset, filter.Options())
+}
+
+// OutboundMessagesOpensFiltered is OutboundMessagesOpens with a typed filter
+func (client *Client) OutboundMessagesOpensFiltered(ctx context.Context, filter MessageEventFilter) iter.Seq2[Open, error] {
+	return client.OutboundMessagesOpens(ctx, filter.Options())
+}
+
+// GetOutboundMessagesClicksFiltered is GetOutboundMessagesClicks with a typed filter
+func (client *Client) GetOutboundMessagesClicksFiltered(ctx context.Context, count, offset int64, filter MessageEventFilter) ([]Click, int64, error) {
+	return client.GetOutboundMessagesClicks(ctx, count, offset, filter.Options())
+}
+
+// OutboundMessagesClicksFiltered is OutboundMessagesClicks with a typed filter
+func (client *Client) OutboundMessagesClicksFiltered(ctx context.Context, filter MessageEventFilter) iter.Seq2[Click, error] {
+	return client.OutboundMessagesClicks(ctx, filter.Options())
+}
+
+// GetOutboundStatsFiltered is GetOutboundStats with a typed filter
+func (client *Client) GetOutboundStatsFiltered(ctx context.Context, filter StatsFilter) (OutboundStats, error) {
+	return client.GetOutboundStats(ctx, filter.Options())
+}
+
+// GetSentCountsFiltered is GetSentCounts with a typed filter
+func (client *Client) GetSentCountsFiltered(ctx context.Context, filter StatsFilter) (SendCounts, error) {
+	return client.GetSentCounts(ctx, filter.Options())
+}
+
+// GetBounceCountsFiltered is GetBounceCounts with a typed filter
+func (client *Client) GetBounceCountsFiltered(ctx context.Context, filter StatsFilter) (BounceCounts, error) {
+	return client.GetBounceCounts(ctx, filter.Options())
+}
+
+// GetSpamCountsFiltered is GetSpamCounts with a typed filter
+func (client *Client) GetSpamCountsFiltered(ctx context.Context, filter StatsFilter) (SpamCounts, error) {
+	return client.GetSpamCounts(ctx, filter.Options())
+}
+
+// GetTrackedCountsFiltered is GetTrackedCounts with a typed filter
+func (client *Client) GetTrackedCountsFiltered(ctx context.Context, filter StatsFilter) (TrackedCounts, error) {
+	return client.GetTrackedCounts(ctx, filter.Options())
+}
+
+// GetOpenCountsFiltered is GetOpenCounts with a typed filter
+func (client *Client) GetOpenCountsFiltered(ctx context.Context, filter StatsFilter) (OpenCounts, error) {
+	return client.GetOpenCounts(ctx, filter.Options())
+}
+
+// GetPlatformCountsFiltered is GetPlatformCounts with a typed filter
+func (client *Client) GetPlatformCountsFiltered(ctx context.Context, filter StatsFilter) (PlatformCounts, error) {
+	return client.GetPlatformCounts(ctx, filter.Options())
+}
+
+// GetClickCountsFiltered is GetClickCounts with a typed filter
+func (client *Client) GetClickCountsFiltered(ctx context.Context, filter StatsFilter) (ClickCounts, error) {
+	return client.GetClickCounts(ctx, filter.Options())
+}
+
+// GetBrowserFamilyCountsFiltered is GetBrowserFamilyCounts with a typed filter
+func (client *Client) GetBrowserFamilyCountsFiltered(ctx context.Context, filter StatsFilter) (BrowserFamilyCounts, error) {
+	return client.GetBrowserFamilyCounts(ctx, filter.Options())
+}
+
+// GetClickLocationCountsFiltered is GetClickLocationCounts with a typed filter
+func (client *Client) GetClickLocationCountsFiltered(ctx context.Context, filter StatsFilter) (ClickLocationCounts, error) {
+	return client.GetClickLocationCounts(ctx, filter.Options())
+}
+
+// GetClickPlatformCountsFiltered is GetClickPlatformCounts with a typed filter
+func (client *Client) GetClickPlatformCountsFiltered(ctx context.Context, filter StatsFilter) (ClickPlatformCounts, error) {
+	return client.GetClickPlatformCounts(ctx, filter.Options())
+}
+
+// GetEmailClientCountsFiltered is GetEmailClientCounts with a typed filter
+func (client *Client) GetEmailClientCountsFiltered(ctx context.Context, filter StatsFilter) (EmailClientCounts, error) {
+	return client.GetEmailClientCounts(ctx, filter.Options())
+}
+
+// addString sets the option when the value is not empty
+func addString(options map[string]interface{}, key, value string) {
+	if value != "" {
+		options[key] = value
+	}
+}
+
+// addSearchDate sets a search date option when the date is set.
+// The time of day is always sent: Postmark reads a plain date as the whole day, so a ToDate at midnight
+// would include the entire following day.
+// Postmark interprets dates in its own timezone (US Eastern), convert with time.In before filtering if needed.
+func addSearchDate(options map[string]interface{}, key string, date time.Time) {
+	if !date.IsZero() {
+		options[key] = date.Format(searchDateFormat)
+	}
+}
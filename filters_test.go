@@ -0,0 +1,178 @@
+package postmark
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+func (s *PostmarkTestSuite) TestFilterOptions() {
+	inactive := false
+	morning := time.Date(2024, 3, 5, 9, 30, 15, 0, time.UTC)
+	midnight := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		options map[string]interface{}
+		want    map[string]interface{}
+	}{
+		{
+			name:    "empty bounce filter",
+			options: BounceFilter{}.Options(),
+			want:    map[string]interface{}{},
+		},
+		{
+			name: "bounce filter",
+			options: BounceFilter{
+				Type:          "HardBounce",
+				Inactive:      &inactive,
+				EmailFilter:   "example.com",
+				Tag:           "welcome",
+				MessageID:     "abc",
+				FromDate:      midnight,
+				ToDate:        morning,
+				MessageStream: "outbound",
+			}.Options(),
+			want: map[string]interface{}{
+				"type":          "HardBounce",
+				"inactive":      "false",
+				"emailFilter":   "example.com",
+				"tag":           "welcome",
+				"messageID":     "abc",
+				"fromdate":      "2024-03-01T00:00:00",
+				"todate":        "2024-03-05T09:30:15",
+				"messagestream": "outbound",
+			},
+		},
+		{
+			name: "outbound message filter sends midnight with its time of day",
+			options: OutboundMessageFilter{
+				Recipient: "john@example.com",
+				Status:    "sent",
+				ToDate:    midnight,
+				Metadata:  map[string]string{"order": "42"},
+			}.Options(),
+			want: map[string]interface{}{
+				"recipient":      "john@example.com",
+				"status":         "sent",
+				"todate":         "2024-03-01T00:00:00",
+				"metadata_order": "42",
+			},
+		},
+		{
+			name: "inbound message filter",
+			options: InboundMessageFilter{
+				MailboxHash: "ticket-1",
+				Status:      "blocked",
+				FromDate:    morning,
+			}.Options(),
+			want: map[string]interface{}{
+				"mailboxhash": "ticket-1",
+				"status":      "blocked",
+				"fromdate":    "2024-03-05T09:30:15",
+			},
+		},
+		{
+			name: "message event filter",
+			options: MessageEventFilter{
+				Recipient:  "john@example.com",
+				ClientName: "Gmail",
+				OSFamily:   "Android",
+				Platform:   "Mobile",
+			}.Options(),
+			want: map[string]interface{}{
+				"recipient":   "john@example.com",
+				"client_name": "Gmail",
+				"os_family":   "Android",
+				"platform":    "Mobile",
+			},
+		},
+		{
+			name: "stats filter ignores time of day",
+			options: StatsFilter{
+				Tag:           "welcome",
+				FromDate:      midnight,
+				ToDate:        morning,
+				MessageStream: "outbound",
+			}.Options(),
+			want: map[string]interface{}{
+				"tag":           "welcome",
+				"fromdate":      "2024-03-01",
+				"todate":        "2024-03-05",
+				"messagestream": "outbound",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			s.Equal(tt.want, tt.options)
+		})
+	}
+}
+
+func (s *PostmarkTestSuite) TestGetBouncesWithFilter() {
+	s.mux.Get("/bounces", func(w http.ResponseWriter, req *http.Request) {
+		query := req.URL.Query()
+		s.Equal("SoftBounce", query.Get("type"))
+		s.Equal("true", query.Get("inactive"))
+		s.Equal("2024-01-31T00:00:00", query.Get("fromdate"))
+		s.Equal("25", query.Get("count"))
+		s.Equal("0", query.Get("offset"))
+		_, _ = w.Write([]byte(`{"TotalCount": 0, "Bounces": []}`))
+	})
+
+	inactive := true
+	filter := BounceFilter{
+		Type:     "SoftBounce",
+		Inactive: &inactive,
+		FromDate: time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
+	}
+
+	_, total, err := s.client.GetBouncesFiltered(context.Background(), 25, 0, filter)
+
+	s.Require().NoError(err)
+	s.Equal(int64(0), total)
+}
+
+func (s *PostmarkTestSuite) TestGetSentCountsWithFilter() {
+	s.mux.Get("/stats/outbound/sends", func(w http.ResponseWriter, req *http.Request) {
+		query := req.URL.Query()
+		s.Equal(testStatsFromDate, query.Get(testFromDateKey))
+		s.Equal(testStatsToDate, query.Get(testToDateKey))
+		_, _ = w.Write([]byte(`{"Days": [], "Sent": 615}`))
+	})
+
+	filter := StatsFilter{
+		FromDate: time.Date(2014, 1, 1, 0, 0, 0, 0, time.UTC),
+		ToDate:   time.Date(2014, 2, 1, 18, 0, 0, 0, time.UTC),
+	}
+
+	res, err := s.client.GetSentCountsFiltered(context.Background(), filter)
+
+	s.Require().NoError(err)
+	s.Equal(int64(615), res.Sent)
+}
+
+func (s *PostmarkTestSuite) TestOutboundMessagesFiltered() {
+	s.mux.Get("/messages/outbound", func(w http.ResponseWriter, req *http.Request) {
+		query := req.URL.Query()
+		s.Equal("sent", query.Get("status"))
+		s.Equal("2024-01-02T00:00:00", query.Get("todate"), "a midnight ToDate must not cover the whole day")
+		s.Equal("42", query.Get("metadata_order"))
+		_, _ = w.Write([]byte(`{"TotalCount": 1, "Messages": [{"MessageID": "abc"}]}`))
+	})
+
+	filter := OutboundMessageFilter{
+		Status:   "sent",
+		ToDate:   time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+		Metadata: map[string]string{"order": "42"},
+	}
+
+	var ids []string
+	for message, err := range s.client.OutboundMessagesFiltered(context.Background(), filter) {
+		s.Require().NoError(err)
+		ids = append(ids, message.MessageID)
+	}
+	s.Equal([]string{"abc"}, ids)
+}
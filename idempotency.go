@@ -0,0 +1,186 @@
+package postmark
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"strings"
+	"sync"
+)
+
+const (
+	// IdempotencyKeyMetadata is the Metadata key holding the idempotency key of messages sent with
+	// SendEmailIdempotent and SendTemplatedEmailIdempotent
+	IdempotencyKeyMetadata = "idempotency_key"
+
+	// DefaultDedupeCacheSize is the number of idempotency keys remembered by the default Dedupe cache
+	DefaultDedupeCacheSize = 10000
+)
+
+// ErrMissingIdempotencyKey is returned by the idempotent send methods when the key is empty
+var ErrMissingIdempotencyKey = errors.New("idempotency key is required")
+
+// DedupeCache remembers the response of every message sent with an idempotency key.
+// Implementations must be safe for concurrent use, a shared cache (e.g. Redis) deduplicates across processes.
+type DedupeCache interface {
+	// Get returns the response stored for the key, if any
+	Get(ctx context.Context, key string) (EmailResponse, bool, error)
+	// Set stores the response of the message sent with the key
+	Set(ctx context.Context, key string, res EmailResponse) error
+}
+
+// LRUDedupeCache is an in-memory DedupeCache that forgets the least recently used keys beyond its size
+type LRUDedupeCache struct {
+	mu      sync.Mutex
+	size    int
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+// lruEntry is an element of the LRUDedupeCache list
+type lruEntry struct {
+	key string
+	res EmailResponse
+}
+
+// NewLRUDedupeCache returns a cache remembering up to size keys, DefaultDedupeCacheSize if size is not positive
+func NewLRUDedupeCache(size int) *LRUDedupeCache {
+	if size <= 0 {
+		size = DefaultDedupeCacheSize
+	}
+	return &LRUDedupeCache{size: size, order: list.New(), entries: make(map[string]*list.Element)}
+}
+
+// Get returns the response stored for the key and marks it as recently used
+func (c *LRUDedupeCache) Get(_ context.Context, key string) (EmailResponse, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return EmailResponse{}, false, nil
+	}
+	c.order.MoveToFront(element)
+	return element.Value.(*lruEntry).res, true, nil //nolint:errcheck,forcetypeassert // the list only holds entries
+}
+
+// Set stores the response for the key, evicting the least recently used key when the cache is full
+func (c *LRUDedupeCache) Set(_ context.Context, key string, res EmailResponse) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.entries[key]; ok {
+		element.Value.(*lruEntry).res = res //nolint:errcheck,forcetypeassert // the list only holds entries
+		c.order.MoveToFront(element)
+		return nil
+	}
+
+	c.entries[key] = c.order.PushFront(&lruEntry{key: key, res: res})
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*lruEntry).key) //nolint:errcheck,forcetypeassert // the list only holds entries
+	}
+	return nil
+}
+
+// Len returns the number of keys in the cache
+func (c *LRUDedupeCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// SendEmailIdempotent sends an email at most once per idempotency key: the key is stored in the
+// IdempotencyKeyMetadata metadata of the email, and a key already sent returns the original response instead of
+// sending again. Sent keys are looked up in the Dedupe cache, then in the outbound messages when DedupeLookup is set.
+// Two concurrent calls with the same key are not deduplicated.
+func (client *Client) SendEmailIdempotent(ctx context.Context, key string, email Email) (EmailResponse, error) {
+	if key == "" {
+		return EmailResponse{}, ErrMissingIdempotencyKey
+	}
+	email.Metadata = withIdempotencyKey(email.Metadata, key)
+
+	return client.sendIdempotent(ctx, key, email.MessageStream, func(ctx context.Context) (EmailResponse, error) {
+		return client.SendEmail(ctx, email)
+	})
+}
+
+// SendTemplatedEmailIdempotent sends a templated email at most once per idempotency key, see SendEmailIdempotent
+func (client *Client) SendTemplatedEmailIdempotent(ctx context.Context, key string, email TemplatedEmail) (EmailResponse, error) {
+	if key == "" {
+		return EmailResponse{}, ErrMissingIdempotencyKey
+	}
+	email.Metadata = withIdempotencyKey(email.Metadata, key)
+
+	return client.sendIdempotent(ctx, key, email.MessageStream, func(ctx context.Context) (EmailResponse, error) {
+		return client.SendTemplatedEmail(ctx, email)
+	})
+}
+
+// sendIdempotent returns the response of an earlier send with the key, or sends and remembers the response
+func (client *Client) sendIdempotent(ctx context.Context, key, stream string, send func(context.Context) (EmailResponse, error)) (EmailResponse, error) {
+	client.dedupeOnce.Do(func() {
+		if client.Dedupe == nil {
+			client.Dedupe = NewLRUDedupeCache(DefaultDedupeCacheSize)
+		}
+	})
+
+	if res, ok, err := client.findIdempotent(ctx, key, stream); err != nil || ok {
+		return res, err
+	}
+
+	res, err := send(ctx)
+	if err != nil {
+		return res, err
+	}
+	if client.Dedupe != nil {
+		if err = client.Dedupe.Set(ctx, key, res); err != nil {
+			return res, fmt.Errorf("message sent but not remembered: %w", err)
+		}
+	}
+	return res, nil
+}
+
+// findIdempotent looks for a message already sent with the key
+func (client *Client) findIdempotent(ctx context.Context, key, stream string) (EmailResponse, bool, error) {
+	if client.Dedupe != nil {
+		if res, ok, err := client.Dedupe.Get(ctx, key); err != nil || ok {
+			return res, ok, err
+		}
+	}
+	if !client.DedupeLookup {
+		return EmailResponse{}, false, nil
+	}
+
+	filter := OutboundMessageFilter{MessageStream: stream, Metadata: map[string]string{IdempotencyKeyMetadata: key}}
+	messages, _, err := client.GetOutboundMessages(ctx, 1, 0, filter.Options())
+	if err != nil || len(messages) == 0 {
+		return EmailResponse{}, false, err
+	}
+
+	res := EmailResponse{
+		To:          strings.Join(messages[0].Recipients, ", "),
+		SubmittedAt: messages[0].ReceivedAt,
+		MessageID:   messages[0].MessageID,
+		Message:     "OK",
+	}
+	if client.Dedupe != nil {
+		if err = client.Dedupe.Set(ctx, key, res); err != nil {
+			return res, true, err
+		}
+	}
+	return res, true, nil
+}
+
+// withIdempotencyKey returns a copy of the metadata with the idempotency key
+func withIdempotencyKey(metadata map[string]string, key string) map[string]string {
+	metadata = maps.Clone(metadata)
+	if metadata == nil {
+		metadata = make(map[string]string, 1)
+	}
+	metadata[IdempotencyKeyMetadata] = key
+	return metadata
+}
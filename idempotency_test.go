@@ -0,0 +1,103 @@
+package postmark
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (s *PostmarkTestSuite) TestSendEmailIdempotent() {
+	var sent atomic.Int32
+	s.mux.Post("/email", func(w http.ResponseWriter, r *http.Request) {
+		var email Email
+		s.Require().NoError(json.NewDecoder(r.Body).Decode(&email))
+		s.Equal("order-42", email.Metadata[IdempotencyKeyMetadata])
+		s.Equal("42", email.Metadata["order"])
+
+		n := sent.Add(1)
+		_ = json.NewEncoder(w).Encode(EmailResponse{To: email.To, MessageID: "message-" + strconv.Itoa(int(n)), Message: "OK"})
+	})
+
+	client := s.newClient()
+	client.Dedupe = NewLRUDedupeCache(10)
+	metadata := map[string]string{"order": "42"}
+	email := Email{From: testSenderEmail, To: "jane@example.com", TextBody: "Hi", Metadata: metadata}
+
+	first, err := client.SendEmailIdempotent(context.Background(), "order-42", email)
+	s.Require().NoError(err)
+	second, err := client.SendEmailIdempotent(context.Background(), "order-42", email)
+	s.Require().NoError(err)
+
+	s.Equal("message-1", first.MessageID)
+	s.Equal(first, second)
+	s.Equal(int32(1), sent.Load())
+	s.NotContains(metadata, IdempotencyKeyMetadata)
+
+	_, err = client.SendEmailIdempotent(context.Background(), "", email)
+	s.Require().ErrorIs(err, ErrMissingIdempotencyKey)
+}
+
+func (s *PostmarkTestSuite) TestSendTemplatedEmailIdempotentLookup() {
+	var sent atomic.Int32
+	s.mux.Post("/email/withTemplate", func(w http.ResponseWriter, _ *http.Request) {
+		sent.Add(1)
+		_, _ = w.Write([]byte(`{"MessageID": "new-message", "Message": "OK"}`))
+	})
+	s.mux.Get("/messages/outbound", func(w http.ResponseWriter, r *http.Request) {
+		s.Equal("broadcast", r.URL.Query().Get("messagestream"))
+		if r.URL.Query().Get("metadata_idempotency_key") != "welcome-7" {
+			_, _ = w.Write([]byte(`{"TotalCount": 0, "Messages": []}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"TotalCount": 1, "Messages": [{
+			"MessageID": "old-message",
+			"Recipients": ["jane@example.com"],
+			"ReceivedAt": "2026-10-17T12:00:00Z"
+		}]}`))
+	})
+
+	client := s.newClient()
+	client.DedupeLookup = true
+	email := TemplatedEmail{TemplateAlias: "welcome", From: testSenderEmail, To: "jane@example.com", MessageStream: "broadcast"}
+	s.Nil(client.Dedupe)
+
+	res, err := client.SendTemplatedEmailIdempotent(context.Background(), "welcome-7", email)
+	s.Require().NoError(err)
+	s.NotNil(client.Dedupe)
+	s.Equal("old-message", res.MessageID)
+	s.Equal("jane@example.com", res.To)
+	s.Equal(int32(0), sent.Load())
+
+	res, err = client.SendTemplatedEmailIdempotent(context.Background(), "welcome-8", email)
+	s.Require().NoError(err)
+	s.Equal("new-message", res.MessageID)
+	s.Equal(int32(1), sent.Load())
+}
+
+func TestLRUDedupeCache(t *testing.T) {
+	ctx := context.Background()
+	cache := NewLRUDedupeCache(2)
+
+	require.NoError(t, cache.Set(ctx, "a", EmailResponse{MessageID: "1"}))
+	require.NoError(t, cache.Set(ctx, "b", EmailResponse{MessageID: "2"}))
+	_, ok, err := cache.Get(ctx, "a")
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	require.NoError(t, cache.Set(ctx, "c", EmailResponse{MessageID: "3"}))
+	assert.Equal(t, 2, cache.Len())
+	_, ok, _ = cache.Get(ctx, "b")
+	assert.False(t, ok, "least recently used key is evicted")
+
+	require.NoError(t, cache.Set(ctx, "a", EmailResponse{MessageID: "4"}))
+	res, ok, _ := cache.Get(ctx, "a")
+	assert.True(t, ok)
+	assert.Equal(t, "4", res.MessageID)
+	assert.Equal(t, 2, cache.Len())
+}
@@ -3,6 +3,7 @@ package postmark
 import (
 	"context"
 	"fmt"
+	"iter"
 	"net/url"
 )
 
@@ -37,11 +38,20 @@ func (client *Client) GetInboundRuleTriggers(ctx context.Context, count, offset
 	values.Add("count", fmt.Sprintf("%d", count))
 	values.Add("offset", fmt.Sprintf("%d", offset))
 
-	err := client.get(ctx, buildURLWithQuery("triggers/inboundrules", *values), &res)
+	err := client.get(ctx, "GetInboundRuleTriggers", buildURLWithQuery("triggers/inboundrules", *values), &res)
 
 	return res.InboundRules, res.TotalCount, err
 }
 
+// InboundRuleTriggers returns an iterator over every inbound rule trigger on the server, fetching pages as needed
+func (client *Client) InboundRuleTriggers(ctx context.Context) iter.Seq2[InboundRuleTrigger, error] {
+	return paginate(ctx, pager[InboundRuleTrigger]{
+		fetch: func(ctx context.Context, count, offset int64, _ map[string]interface{}) ([]InboundRuleTrigger, int64, error) {
+			return client.GetInboundRuleTriggers(ctx, count, offset)
+		},
+	}, nil)
+}
+
 // CreateInboundRuleTrigger creates an inbound rule trigger to block emails
 func (client *Client) CreateInboundRuleTrigger(ctx context.Context, rule string) (InboundRuleTrigger, error) {
 	res := InboundRuleTrigger{}
@@ -50,20 +60,12 @@ func (client *Client) CreateInboundRuleTrigger(ctx context.Context, rule string)
 		Rule: rule,
 	}
 
-	err := client.post(ctx, "triggers/inboundrules", requestData, &res)
+	err := client.post(ctx, "CreateInboundRuleTrigger", "triggers/inboundrules", requestData, &res)
 
 	return res, err
 }
 
 // DeleteInboundRuleTrigger deletes an inbound rule trigger by ID
 func (client *Client) DeleteInboundRuleTrigger(ctx context.Context, triggerID int64) error {
-	res := APIError{}
-	err := client.delete(ctx, fmt.Sprintf("triggers/inboundrules/%d", triggerID), &res)
-	if err != nil {
-		return err
-	}
-	if res.ErrorCode != 0 {
-		return res
-	}
-	return nil
+	return client.delete(ctx, "DeleteInboundRuleTrigger", fmt.Sprintf("triggers/inboundrules/%d", triggerID), &APIError{})
 }
@@ -0,0 +1,48 @@
+package postmark
+
+import (
+	"context"
+	"net/http"
+)
+
+// Operation describes a single logical Postmark API call as seen by interceptors
+type Operation struct {
+	// Name is the Client method that made the call, e.g. "SendEmail"
+	Name string
+	// Method is the HTTP method
+	Method string
+	// Path is the API path, relative to the BaseURL
+	Path string
+	// TokenType is the token used to authenticate the call
+	TokenType TokenType
+	// Payload is the request body before it is encoded as JSON, nil when there is none
+	Payload interface{}
+	// Header holds extra headers sent with every attempt, e.g. a request signature
+	Header http.Header
+
+	// StatusCode is the HTTP status of the last response, zero until a response is received
+	StatusCode int
+	// Attempts is the number of HTTP requests made, including retries
+	Attempts int
+}
+
+// Invoker performs the call described by op and decodes the response into result (which may be nil)
+type Invoker func(ctx context.Context, op *Operation, result interface{}) error
+
+// Interceptor wraps a Postmark API call. It can inspect or change the operation and context before
+// calling next, and inspect the decoded result and error afterward. Returning without calling next
+// skips the call.
+//
+// Interceptors wrap the whole call, including retries and rate limiting.
+type Interceptor func(ctx context.Context, op *Operation, result interface{}, next Invoker) error
+
+// chain returns an Invoker that runs the interceptors in order around invoke
+func chain(interceptors []Interceptor, invoke Invoker) Invoker {
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor, next := interceptors[i], invoke
+		invoke = func(ctx context.Context, op *Operation, result interface{}) error {
+			return interceptor(ctx, op, result, next)
+		}
+	}
+	return invoke
+}
@@ -0,0 +1,131 @@
+package postmark
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"time"
+)
+
+func (s *PostmarkTestSuite) TestInterceptors() {
+	s.mux.Post("/email", func(w http.ResponseWriter, req *http.Request) {
+		s.Equal("signed", req.Header.Get("X-Signature"))
+		_, _ = w.Write([]byte(`{"To": "receiver@example.com", "MessageID": "0a129aee-e1cd-480d-b08d-4f48548ff48d", "ErrorCode": 0, "Message": "OK"}`))
+	})
+
+	var calls []string
+	var seen Operation
+	var seenResult EmailResponse
+
+	client := s.newClient()
+	client.Interceptors = []Interceptor{
+		func(ctx context.Context, op *Operation, result interface{}, next Invoker) error {
+			calls = append(calls, "outer before")
+			err := next(ctx, op, result)
+			calls = append(calls, "outer after")
+			return err
+		},
+		func(ctx context.Context, op *Operation, result interface{}, next Invoker) error {
+			calls = append(calls, "inner before")
+			op.Header.Set("X-Signature", "signed")
+			err := next(ctx, op, result)
+			seen = *op
+			seenResult = *result.(*EmailResponse)
+			calls = append(calls, "inner after")
+			return err
+		},
+	}
+
+	res, err := client.SendEmail(context.Background(), Email{From: testSenderEmail, To: "receiver@example.com"})
+
+	s.Require().NoError(err)
+	s.Equal("0a129aee-e1cd-480d-b08d-4f48548ff48d", res.MessageID)
+	s.Equal([]string{"outer before", "inner before", "inner after", "outer after"}, calls)
+	s.Equal("SendEmail", seen.Name)
+	s.Equal(http.MethodPost, seen.Method)
+	s.Equal("email", seen.Path)
+	s.Equal(ServerTokenType, seen.TokenType)
+	s.Equal(testSenderEmail, seen.Payload.(Email).From)
+	s.Equal(http.StatusOK, seen.StatusCode)
+	s.Equal(1, seen.Attempts)
+	s.Equal(res, seenResult)
+}
+
+func (s *PostmarkTestSuite) TestInterceptorSeesErrorsAndRetries() {
+	// A separate router, the suite's /servers/:serverID route would shadow this one
+	mux := NewTestRouter()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	attempts := 0
+	mux.Get("/servers/42", func(w http.ResponseWriter, _ *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_, _ = w.Write([]byte(`{"ErrorCode": 10, "Message": "Bad token"}`))
+	})
+
+	var seen Operation
+	var seenErr error
+
+	client := *NewClient(testServerToken, testAccountToken)
+	client.BaseURL = server.URL
+	client.Retry = testRetryPolicy()
+	client.Retry.MaxBackoff = time.Millisecond
+	client.Interceptors = []Interceptor{
+		func(ctx context.Context, op *Operation, result interface{}, next Invoker) error {
+			seenErr = next(ctx, op, result)
+			seen = *op
+			return seenErr
+		},
+	}
+
+	_, err := client.GetServer(context.Background(), 42)
+
+	s.Require().ErrorIs(err, ErrInvalidAPIToken)
+	s.Require().ErrorIs(seenErr, ErrInvalidAPIToken)
+	s.Equal("GetServer", seen.Name)
+	s.Equal(AccountTokenType, seen.TokenType)
+	s.Equal(http.StatusUnprocessableEntity, seen.StatusCode)
+	s.Equal(2, seen.Attempts)
+}
+
+func (s *PostmarkTestSuite) TestInterceptorCanSkipCall() {
+	client := s.newClient()
+	client.BaseURL = "http://127.0.0.1:0"
+	client.Interceptors = []Interceptor{
+		func(_ context.Context, _ *Operation, result interface{}, _ Invoker) error {
+			*result.(*[]string) = []string{"cached"}
+			return nil
+		},
+	}
+
+	tags, err := client.GetBouncedTags(context.Background())
+
+	s.Require().NoError(err)
+	s.Equal([]string{"cached"}, tags)
+}
+
+func (s *PostmarkTestSuite) TestOperationNameFromIterator() {
+	s.mux.Get("/bounces", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"TotalCount": 0, "Bounces": []}`))
+	})
+
+	var names []string
+	client := s.newClient()
+	client.Interceptors = []Interceptor{
+		func(ctx context.Context, op *Operation, result interface{}, next Invoker) error {
+			names = append(names, op.Name)
+			return next(ctx, op, result)
+		},
+	}
+
+	for _, err := range client.Bounces(context.Background(), nil) {
+		s.Require().NoError(err)
+	}
+
+	s.Equal([]string{"GetBounces"}, names)
+}
@@ -0,0 +1,118 @@
+package postmark
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// redacted replaces secret values in logs
+const redacted = "[REDACTED]"
+
+// redactedHeaders returns the request headers whose values are never logged
+func redactedHeaders() []string {
+	return []string{"X-Postmark-Server-Token", "X-Postmark-Account-Token"}
+}
+
+// redactedFields returns the JSON payload fields whose values are never logged: attachment contents,
+// message bodies and webhook HTTP auth passwords
+func redactedFields() []string {
+	return []string{"Content", "HtmlBody", "TextBody", "Password"}
+}
+
+// logCall logs a finished API call at debug level
+func (client *Client) logCall(ctx context.Context, op *Operation, header http.Header, duration time.Duration, err error) {
+	if client.Logger == nil || !client.Logger.Enabled(ctx, slog.LevelDebug) {
+		return
+	}
+
+	attrs := []slog.Attr{
+		slog.String("operation", op.Name),
+		slog.String("method", op.Method),
+		slog.String("path", op.Path),
+		slog.Int("status", op.StatusCode),
+		slog.Duration("duration", duration),
+		slog.Int("attempts", op.Attempts),
+		slog.Any("headers", redactHeaders(header)),
+	}
+	if op.Payload != nil {
+		attrs = append(attrs, slog.String("payload", redactPayload(op.Payload)))
+	}
+	if err != nil {
+		attrs = append(attrs, slog.Int64("error_code", ErrorCode(err)), slog.String("error", err.Error()))
+	}
+
+	client.Logger.LogAttrs(ctx, slog.LevelDebug, "postmark request", attrs...)
+}
+
+// logRetry logs a failed attempt that is about to be retried
+func (client *Client) logRetry(ctx context.Context, op *Operation, backoff time.Duration, err error) {
+	if client.Logger == nil || !client.Logger.Enabled(ctx, slog.LevelDebug) {
+		return
+	}
+
+	client.Logger.LogAttrs(ctx, slog.LevelDebug, "postmark request retry",
+		slog.String("operation", op.Name),
+		slog.String("method", op.Method),
+		slog.String("path", op.Path),
+		slog.Int("status", op.StatusCode),
+		slog.Int("attempt", op.Attempts),
+		slog.Duration("backoff", backoff),
+		slog.Int64("error_code", ErrorCode(err)),
+		slog.String("error", err.Error()),
+	)
+}
+
+// redactHeaders returns a copy of the headers with the API tokens redacted
+func redactHeaders(header http.Header) http.Header {
+	header = header.Clone()
+	for _, name := range redactedHeaders() {
+		if header.Get(name) != "" {
+			header.Set(name, redacted)
+		}
+	}
+	return header
+}
+
+// redactPayload returns the JSON encoding of a payload with the secret fields redacted
+func redactPayload(payload interface{}) string {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return ""
+	}
+
+	var value interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err = decoder.Decode(&value); err != nil {
+		return ""
+	}
+
+	if data, err = json.Marshal(redactValue(value)); err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// redactValue replaces the values of the secret fields of a decoded JSON value, at any depth
+func redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			v[key] = redactValue(field)
+		}
+		for _, key := range redactedFields() {
+			if field, ok := v[key]; ok && field != nil && field != "" {
+				v[key] = redacted
+			}
+		}
+	case []interface{}:
+		for i := range v {
+			v[i] = redactValue(v[i])
+		}
+	}
+	return value
+}
@@ -0,0 +1,127 @@
+package postmark
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestLogger returns a debug logger writing JSON lines into buf
+func newTestLogger(buf *bytes.Buffer) *slog.Logger {
+	return slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+}
+
+func (s *PostmarkTestSuite) TestLoggerRedactsSecrets() {
+	s.mux.Post("/email", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"To": "receiver@example.com", "MessageID": "0a129aee-e1cd-480d-b08d-4f48548ff48d", "ErrorCode": 0, "Message": "OK"}`))
+	})
+
+	var buf bytes.Buffer
+	client := s.newClient()
+	client.Logger = newTestLogger(&buf)
+
+	_, err := client.SendEmail(context.Background(), Email{
+		From:        testSenderEmail,
+		To:          "receiver@example.com",
+		HTMLBody:    "<p>secret html</p>",
+		TextBody:    "secret text",
+		Attachments: []Attachment{{Name: "report.pdf", Content: "c2VjcmV0IGZpbGU=", ContentType: "application/pdf"}},
+	})
+	s.Require().NoError(err)
+
+	logged := buf.String()
+	s.NotContains(logged, testServerToken)
+	s.NotContains(logged, "secret html")
+	s.NotContains(logged, "secret text")
+	s.NotContains(logged, "c2VjcmV0IGZpbGU=")
+	s.Contains(logged, "report.pdf")
+
+	var entry map[string]interface{}
+	s.Require().NoError(json.Unmarshal(buf.Bytes(), &entry))
+	s.Equal("postmark request", entry["msg"])
+	s.Equal("DEBUG", entry["level"])
+	s.Equal("SendEmail", entry["operation"])
+	s.Equal("email", entry["path"])
+	s.InDelta(float64(http.StatusOK), entry["status"], 0)
+	s.Contains(entry, "duration")
+	s.NotContains(entry, "error_code")
+	s.Equal([]interface{}{redacted}, entry["headers"].(map[string]interface{})["X-Postmark-Server-Token"])
+}
+
+func (s *PostmarkTestSuite) TestLoggerLogsErrorCode() {
+	s.mux.Post("/email", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_, _ = w.Write([]byte(`{"ErrorCode": 406, "Message": "You tried to send to a recipient that has been marked as inactive."}`))
+	})
+
+	var buf bytes.Buffer
+	client := s.newClient()
+	client.Logger = newTestLogger(&buf)
+
+	_, err := client.SendEmail(context.Background(), Email{From: testSenderEmail, To: "receiver@example.com"})
+	s.Require().ErrorIs(err, ErrInactiveRecipient)
+
+	var entry map[string]interface{}
+	s.Require().NoError(json.Unmarshal(buf.Bytes(), &entry))
+	s.InDelta(float64(http.StatusUnprocessableEntity), entry["status"], 0)
+	s.InDelta(float64(ErrorCodeInactiveRecipient), entry["error_code"], 0)
+	s.Contains(entry["error"], "inactive")
+}
+
+func (s *PostmarkTestSuite) TestLoggerDisabledAboveDebug() {
+	s.mux.Get("/bounces/tags", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`[]`))
+	})
+
+	var buf bytes.Buffer
+	client := s.newClient()
+	client.Logger = slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo}))
+
+	_, err := client.GetBouncedTags(context.Background())
+	s.Require().NoError(err)
+	s.Empty(buf.String())
+}
+
+func TestRedactPayload(t *testing.T) {
+	payload := map[string]interface{}{
+		"Messages": []TemplatedEmail{{
+			TemplateAlias: "welcome",
+			Attachments:   []Attachment{{Name: "a.txt", Content: "Zm9v"}},
+		}},
+		"Webhook": Webhook{
+			URL:      "https://example.com/hooks",
+			HTTPAuth: &WebhookHTTPAuth{Username: "user", Password: "hunter2"},
+		},
+		"Template": Template{Name: "Welcome", HTMLBody: "<b>hi</b>", TextBody: ""},
+	}
+
+	redactedJSON := redactPayload(payload)
+
+	var decoded map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(redactedJSON), &decoded))
+	assert.NotContains(t, redactedJSON, "Zm9v")
+	assert.NotContains(t, redactedJSON, "hunter2")
+	assert.NotContains(t, redactedJSON, "<b>hi</b>")
+	assert.Contains(t, redactedJSON, `"Username":"user"`)
+	assert.Contains(t, redactedJSON, `"TextBody":""`)
+	assert.Contains(t, redactedJSON, `"TemplateAlias":"welcome"`)
+}
+
+func TestRedactHeaders(t *testing.T) {
+	header := http.Header{}
+	header.Set("X-Postmark-Account-Token", "account-secret")
+	header.Set("Accept", "application/json")
+
+	redactedHeader := redactHeaders(header)
+
+	assert.Equal(t, redacted, redactedHeader.Get("X-Postmark-Account-Token"))
+	assert.Equal(t, "application/json", redactedHeader.Get("Accept"))
+	assert.Equal(t, "account-secret", header.Get("X-Postmark-Account-Token"))
+	assert.Empty(t, redactedHeader.Get("X-Postmark-Server-Token"))
+}
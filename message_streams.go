@@ -82,7 +82,7 @@ func (client *Client) ListMessageStreams(ctx context.Context, messageStreamType
 		MessageStreams []MessageStream
 	}
 
-	err := client.get(ctx, fmt.Sprintf("message-streams?MessageStreamType=%s&IncludeArchivedStreams=%t", messageStreamType, includeArchived), &res)
+	err := client.get(ctx, "ListMessageStreams", fmt.Sprintf("message-streams?MessageStreamType=%s&IncludeArchivedStreams=%t", messageStreamType, includeArchived), &res)
 
 	return res.MessageStreams, err
 }
@@ -90,7 +90,7 @@ func (client *Client) ListMessageStreams(ctx context.Context, messageStreamType
 // GetMessageStream retrieves a specific message stream by the message stream's ID.
 func (client *Client) GetMessageStream(ctx context.Context, id string) (MessageStream, error) {
 	var res MessageStream
-	err := client.get(ctx, fmt.Sprintf("message-streams/%s", id), &res)
+	err := client.get(ctx, "GetMessageStream", fmt.Sprintf("message-streams/%s", id), &res)
 	return res, err
 }
 
@@ -108,7 +108,7 @@ type EditMessageStreamRequest struct {
 // EditMessageStream updates a message stream.
 func (client *Client) EditMessageStream(ctx context.Context, id string, req EditMessageStreamRequest) (MessageStream, error) {
 	var res MessageStream
-	err := client.patch(ctx, fmt.Sprintf("message-streams/%s", id), req, &res)
+	err := client.patch(ctx, "EditMessageStream", fmt.Sprintf("message-streams/%s", id), req, &res)
 	return res, err
 }
 
@@ -131,7 +131,7 @@ type CreateMessageStreamRequest struct {
 // server of the token used by this Client.
 func (client *Client) CreateMessageStream(ctx context.Context, req CreateMessageStreamRequest) (MessageStream, error) {
 	var res MessageStream
-	err := client.post(ctx, "message-streams", req, &res)
+	err := client.post(ctx, "CreateMessageStream", "message-streams", req, &res)
 	return res, err
 }
 
@@ -150,7 +150,7 @@ type ArchiveMessageStreamResponse struct {
 // after 45 days, but they can be restored until that point.
 func (client *Client) ArchiveMessageStream(ctx context.Context, id string) (ArchiveMessageStreamResponse, error) {
 	var res ArchiveMessageStreamResponse
-	err := client.post(ctx, fmt.Sprintf("message-streams/%s/archive", id), nil, &res)
+	err := client.post(ctx, "ArchiveMessageStream", fmt.Sprintf("message-streams/%s/archive", id), nil, &res)
 	return res, err
 }
 
@@ -158,6 +158,6 @@ func (client *Client) ArchiveMessageStream(ctx context.Context, id string) (Arch
 // The ArchivedAt value will be null after calling this method.
 func (client *Client) UnarchiveMessageStream(ctx context.Context, id string) (MessageStream, error) {
 	var res MessageStream
-	err := client.post(ctx, fmt.Sprintf("message-streams/%s/unarchive", id), nil, &res)
+	err := client.post(ctx, "UnarchiveMessageStream", fmt.Sprintf("message-streams/%s/unarchive", id), nil, &res)
 	return res, err
 }
@@ -3,10 +3,22 @@ package postmark
 import (
 	"context"
 	"fmt"
+	"iter"
 	"net/mail"
+	"net/textproto"
+	"strconv"
+	"strings"
 	"time"
 )
 
+const (
+	// spamScoreHeader is the header SpamAssassin adds with the spam score of an inbound message
+	spamScoreHeader = "X-Spam-Score"
+
+	// spamStatusHeader is the header SpamAssassin adds with the spam verdict of an inbound message
+	spamStatusHeader = "X-Spam-Status"
+)
+
 // InboundMessage - a message received from the Postmark server
 type InboundMessage struct {
 	// From - The sender email address.
@@ -35,6 +47,8 @@ type InboundMessage struct {
 	MailboxHash string
 	// TextBody - Plain text email message.
 	TextBody string
+	// StrippedTextReply - Plain text reply with the quoted original message removed (webhook only).
+	StrippedTextReply string
 	// HTMLBody - HTML email message.
 	HTMLBody string `json:"HtmlBody"`
 	// Tag - Tag name
@@ -49,6 +63,8 @@ type InboundMessage struct {
 	BlockedReason string
 	// Status - Status of message in your Postmark activity.
 	Status string
+	// MessageStream - The inbound message stream ID.
+	MessageStream string `json:",omitempty"`
 }
 
 // Time returns a parsed time.Time struct
@@ -56,10 +72,45 @@ func (x InboundMessage) Time() (time.Time, error) {
 	return mail.ParseDate(x.Date)
 }
 
+// MailHeader returns the message headers as a mail.Header, keyed by canonical header name
+func (x InboundMessage) MailHeader() mail.Header {
+	header := make(mail.Header, len(x.Headers))
+	for _, h := range x.Headers {
+		key := textproto.CanonicalMIMEHeaderKey(h.Name)
+		header[key] = append(header[key], h.Value)
+	}
+	return header
+}
+
+// SpamScore returns the SpamAssassin score from the X-Spam-Score header,
+// ok is false when the header is missing or not a number
+func (x InboundMessage) SpamScore() (score float64, ok bool) {
+	value := x.MailHeader().Get(spamScoreHeader)
+	if value == "" {
+		return 0, false
+	}
+
+	score, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil {
+		return 0, false
+	}
+	return score, true
+}
+
+// SpamStatus returns the SpamAssassin verdict from the X-Spam-Status header, e.g. "No"
+func (x InboundMessage) SpamStatus() string {
+	return strings.TrimSpace(x.MailHeader().Get(spamStatusHeader))
+}
+
+// IsSpam reports whether SpamAssassin flagged the message as spam
+func (x InboundMessage) IsSpam() bool {
+	return strings.HasPrefix(strings.ToLower(x.SpamStatus()), "yes")
+}
+
 // GetInboundMessage fetches a specific inbound message via serverID
 func (client *Client) GetInboundMessage(ctx context.Context, messageID string) (InboundMessage, error) {
 	res := InboundMessage{}
-	err := client.get(ctx, fmt.Sprintf("messages/inbound/%s/details", messageID), &res)
+	err := client.get(ctx, "GetInboundMessage", fmt.Sprintf("messages/inbound/%s/details", messageID), &res)
 	return res, err
 }
 
@@ -70,7 +121,7 @@ type inboundMessagesResponse struct {
 
 // GetInboundMessages fetches a list of inbound message on the server
 // It returns a InboundMessage slice, the total message count, and any error that occurred
-// http://developer.postmarkapp.com/developer-api-messages.html#inbound-message-search
+// http://developer.postmarkapp.com/developer-api-messages.html#inbound-message-search (see InboundMessageFilter)
 func (client *Client) GetInboundMessages(ctx context.Context, count, offset int64, options map[string]interface{}) ([]InboundMessage, int64, error) {
 	res := inboundMessagesResponse{}
 
@@ -81,33 +132,28 @@ func (client *Client) GetInboundMessages(ctx context.Context, count, offset int6
 	options["count"] = count
 	options["offset"] = offset
 
-	err := client.get(ctx, buildURL("messages/inbound", options), &res)
+	err := client.get(ctx, "GetInboundMessages", buildURL("messages/inbound", options), &res)
 
 	return res.Messages, res.TotalCount, err
 }
 
+// InboundMessages returns an iterator over every inbound message matching the options, fetching pages as needed
+// The iterator yields ErrOffsetLimitReached if more than 10,000 messages match: inbound messages only carry the
+// Date header set by the sender, which cannot be used to split the search into date ranges
+// http://developer.postmarkapp.com/developer-api-messages.html#inbound-message-search
+func (client *Client) InboundMessages(ctx context.Context, options map[string]interface{}) iter.Seq2[InboundMessage, error] {
+	return paginate(ctx, pager[InboundMessage]{
+		fetch: client.GetInboundMessages,
+		limit: maxSearchOffset,
+	}, options)
+}
+
 // BypassInboundMessage - Bypass rules for a blocked inbound message
 func (client *Client) BypassInboundMessage(ctx context.Context, messageID string) error {
-	res := APIError{}
-	err := client.put(ctx, fmt.Sprintf("messages/inbound/%s/bypass", messageID), nil, &res)
-	if err != nil {
-		return err
-	}
-	if res.ErrorCode != 0 {
-		return res
-	}
-	return nil
+	return client.put(ctx, "BypassInboundMessage", fmt.Sprintf("messages/inbound/%s/bypass", messageID), nil, &APIError{})
 }
 
 // RetryInboundMessage - Retry a failed inbound message for processing
 func (client *Client) RetryInboundMessage(ctx context.Context, messageID string) error {
-	res := APIError{}
-	err := client.put(ctx, fmt.Sprintf("messages/inbound/%s/retry", messageID), nil, &res)
-	if err != nil {
-		return err
-	}
-	if res.ErrorCode != 0 {
-		return res
-	}
-	return nil
+	return client.put(ctx, "RetryInboundMessage", fmt.Sprintf("messages/inbound/%s/retry", messageID), nil, &APIError{})
 }
@@ -0,0 +1,123 @@
+package postmark
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+)
+
+// InboundFunc handles an inbound message received by the inbound webhook
+type InboundFunc func(ctx context.Context, message InboundMessage) error
+
+// InboundHandler is an http.Handler that receives Postmark inbound webhook POSTs, decodes them into
+// InboundMessage and routes each message to the first registered handler matching its MailboxHash
+// or OriginalRecipient.
+//
+// It responds with 200 when the message was handled (or no route matched and there is no Fallback),
+// 400 when the payload is invalid, 401 when the credentials are wrong and 500 when a handler returns
+// an error, so that Postmark retries the delivery later.
+type InboundHandler struct {
+	// HTTPAuth: basic auth credentials every request must carry (optional)
+	HTTPAuth *WebhookHTTPAuth
+	// HTTPHeaders: custom headers every request must carry (optional)
+	HTTPHeaders []Header
+	// MaxBodyBytes: largest payload accepted, defaults to 50 MB
+	MaxBodyBytes int64
+
+	// Fallback is called for messages that match no route (optional)
+	Fallback InboundFunc
+
+	// OnError is called whenever a request is rejected or a handler fails (optional)
+	OnError func(r *http.Request, status int, err error)
+
+	mu     sync.RWMutex
+	routes []inboundRoute
+}
+
+// inboundRoute is a registered inbound handler and the messages it matches
+type inboundRoute struct {
+	match   func(message InboundMessage) bool
+	handler InboundFunc
+}
+
+// HandleMailboxHash registers a handler for messages sent to the given MailboxHash (the part after + in the address)
+func (h *InboundHandler) HandleMailboxHash(hash string, handler InboundFunc) {
+	h.handle(func(message InboundMessage) bool {
+		return message.MailboxHash == hash
+	}, handler)
+}
+
+// HandleRecipient registers a handler for messages whose OriginalRecipient matches the pattern.
+// The pattern uses path.Match syntax and is matched case-insensitively, e.g. "support+*@inbound.example.com".
+// HandleRecipient panics if the pattern is malformed.
+func (h *InboundHandler) HandleRecipient(pattern string, handler InboundFunc) {
+	pattern = strings.ToLower(pattern)
+	if _, err := path.Match(pattern, ""); err != nil {
+		panic(fmt.Sprintf("postmark: invalid recipient pattern %q: %v", pattern, err))
+	}
+
+	h.handle(func(message InboundMessage) bool {
+		matched, _ := path.Match(pattern, strings.ToLower(message.OriginalRecipient))
+		return matched
+	}, handler)
+}
+
+// handle appends a route, routes are tried in registration order
+func (h *InboundHandler) handle(match func(message InboundMessage) bool, handler InboundFunc) {
+	if handler == nil {
+		panic("postmark: nil inbound handler")
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.routes = append(h.routes, inboundRoute{match: match, handler: handler})
+}
+
+// route returns the handler for the message, or the Fallback when no route matches
+func (h *InboundHandler) route(message InboundMessage) InboundFunc {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for _, route := range h.routes {
+		if route.match(message) {
+			return route.handler
+		}
+	}
+	return h.Fallback
+}
+
+// ServeHTTP implements http.Handler interface.
+func (h *InboundHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, status, err := readWebhook(w, r, h.HTTPAuth, h.HTTPHeaders, h.MaxBodyBytes)
+	if err != nil {
+		h.fail(w, r, status, err)
+		return
+	}
+
+	var message InboundMessage
+	if err = json.Unmarshal(body, &message); err != nil {
+		h.fail(w, r, http.StatusBadRequest, fmt.Errorf("%w: %w", ErrInvalidWebhookPayload, err))
+		return
+	}
+
+	if handler := h.route(message); handler != nil {
+		if err = handler(r.Context(), message); err != nil {
+			h.fail(w, r, http.StatusInternalServerError, err)
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// fail reports the error and writes the status code
+func (h *InboundHandler) fail(w http.ResponseWriter, r *http.Request, status int, err error) {
+	if h.OnError != nil {
+		h.OnError(r, status, err)
+	}
+	writeWebhookError(w, status)
+}
@@ -0,0 +1,178 @@
+package postmark
+
+import (
+	"context"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testInboundPayload = `{
+	"FromName": "Postmarkapp Support",
+	"MessageStream": "inbound",
+	"From": "support@postmarkapp.com",
+	"To": "\"Firstname Lastname\" <yourhash+SampleHash@inbound.postmarkapp.com>",
+	"OriginalRecipient": "yourhash+SampleHash@inbound.postmarkapp.com",
+	"Subject": "Test subject",
+	"MessageID": "73e6d360-66eb-11e1-8e72-a8904824019b",
+	"ReplyTo": "replyto@postmarkapp.com",
+	"MailboxHash": "SampleHash",
+	"Date": "Fri, 1 Aug 2014 16:45:32 -04:00",
+	"TextBody": "This is a test text body.",
+	"HtmlBody": "<html><body><p>This is a test html body.</p></body></html>",
+	"StrippedTextReply": "This is the reply text",
+	"Tag": "TestTag",
+	"Headers": [
+		{"Name": "X-Header-Test", "Value": ""},
+		{"Name": "X-Spam-Status", "Value": "No"},
+		{"Name": "X-Spam-Score", "Value": "-0.1"},
+		{"Name": "X-Spam-Tests", "Value": "DKIM_SIGNED,DKIM_VALID,DKIM_VALID_AU,SPF_PASS"},
+		{"Name": "Received", "Value": "by 10.0.0.1"},
+		{"Name": "received", "Value": "by 10.0.0.2"}
+	],
+	"Attachments": [
+		{"Name": "test.txt", "Content": "VGhpcyBpcyBhdHRhY2htZW50IGNvbnRlbnRzLCBiYXNlLTY0IGVuY29kZWQu", "ContentType": "text/plain", "ContentLength": 45}
+	]
+}`
+
+func TestInboundHandlerRouting(t *testing.T) {
+	tests := []struct {
+		name      string
+		setup     func(h *InboundHandler, got *string)
+		wantRoute string
+	}{
+		{
+			name: "mailbox hash",
+			setup: func(h *InboundHandler, got *string) {
+				h.HandleMailboxHash("OtherHash", recordInbound(got, "other"))
+				h.HandleMailboxHash("SampleHash", recordInbound(got, "hash"))
+			},
+			wantRoute: "hash",
+		},
+		{
+			name: "recipient pattern is case insensitive",
+			setup: func(h *InboundHandler, got *string) {
+				h.HandleRecipient("support@*", recordInbound(got, "support"))
+				h.HandleRecipient("YOURHASH+*@inbound.postmarkapp.com", recordInbound(got, "recipient"))
+			},
+			wantRoute: "recipient",
+		},
+		{
+			name: "first matching route wins",
+			setup: func(h *InboundHandler, got *string) {
+				h.HandleRecipient("*@inbound.postmarkapp.com", recordInbound(got, "first"))
+				h.HandleMailboxHash("SampleHash", recordInbound(got, "second"))
+			},
+			wantRoute: "first",
+		},
+		{
+			name: "fallback",
+			setup: func(h *InboundHandler, got *string) {
+				h.HandleMailboxHash("OtherHash", recordInbound(got, "other"))
+				h.Fallback = recordInbound(got, "fallback")
+			},
+			wantRoute: "fallback",
+		},
+		{
+			name:  "no route is acknowledged",
+			setup: func(*InboundHandler, *string) {},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			handler := &InboundHandler{}
+			tt.setup(handler, &got)
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, newTestWebhookRequest(http.MethodPost, testInboundPayload))
+
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, tt.wantRoute, got)
+		})
+	}
+}
+
+func recordInbound(got *string, route string) InboundFunc {
+	return func(_ context.Context, message InboundMessage) error {
+		if message.MessageID == "73e6d360-66eb-11e1-8e72-a8904824019b" {
+			*got = route
+		}
+		return nil
+	}
+}
+
+func TestInboundHandlerStatusCodes(t *testing.T) {
+	handler := &InboundHandler{HTTPAuth: &WebhookHTTPAuth{Username: "user", Password: "pass"}}
+	handler.HandleMailboxHash("SampleHash", func(context.Context, InboundMessage) error {
+		return errTestCallback
+	})
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, newTestWebhookRequest(http.MethodPost, testInboundPayload))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	req := newTestWebhookRequest(http.MethodPost, `[]`)
+	req.SetBasicAuth("user", "pass")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	req = newTestWebhookRequest(http.MethodPost, testInboundPayload)
+	req.SetBasicAuth("user", "pass")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+}
+
+func TestInboundHandlerInvalidPattern(t *testing.T) {
+	handler := &InboundHandler{}
+	assert.Panics(t, func() {
+		handler.HandleRecipient("[", func(context.Context, InboundMessage) error { return nil })
+	})
+}
+
+func TestInboundMessageHelpers(t *testing.T) {
+	var message InboundMessage
+	handler := &InboundHandler{Fallback: func(_ context.Context, m InboundMessage) error {
+		message = m
+		return nil
+	}}
+	handler.ServeHTTP(httptest.NewRecorder(), newTestWebhookRequest(http.MethodPost, testInboundPayload))
+
+	assert.Equal(t, "This is the reply text", message.StrippedTextReply)
+	assert.Equal(t, "inbound", message.MessageStream)
+
+	header := message.MailHeader()
+	assert.Equal(t, "No", header.Get("x-spam-status"))
+	assert.Equal(t, []string{"by 10.0.0.1", "by 10.0.0.2"}, header["Received"])
+
+	score, ok := message.SpamScore()
+	assert.True(t, ok)
+	assert.InDelta(t, -0.1, score, 0.0001)
+	assert.Equal(t, "No", message.SpamStatus())
+	assert.False(t, message.IsSpam())
+
+	_, ok = InboundMessage{}.SpamScore()
+	assert.False(t, ok)
+	assert.True(t, InboundMessage{Headers: []Header{{Name: "X-Spam-Status", Value: "Yes, score=6.2"}}}.IsSpam())
+
+	require.Len(t, message.Attachments, 1)
+	content, err := io.ReadAll(message.Attachments[0].Reader())
+	require.NoError(t, err)
+	assert.Equal(t, "This is attachment contents, base-64 encoded.", string(content))
+
+	decoded, err := message.Attachments[0].Decode()
+	require.NoError(t, err)
+	assert.Equal(t, content, decoded)
+
+	_, err = Attachment{Content: "not base64!"}.Decode()
+	var corrupt base64.CorruptInputError
+	require.ErrorAs(t, err, &corrupt)
+}
@@ -2,8 +2,11 @@ package postmark
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
+	"strings"
 )
 
 func (s *PostmarkTestSuite) TestGetInboundMessage() {
@@ -281,3 +284,32 @@ func (s *PostmarkTestSuite) TestRetryInboundMessage() {
 		})
 	}
 }
+
+func (s *PostmarkTestSuite) TestInboundMessagesIteratorStopsAtLimit() {
+	s.mux.Get("/messages/inbound", func(w http.ResponseWriter, req *http.Request) {
+		count, err := strconv.Atoi(req.URL.Query().Get("count"))
+		s.Require().NoError(err)
+		offset, err := strconv.Atoi(req.URL.Query().Get("offset"))
+		s.Require().NoError(err)
+		s.Empty(req.URL.Query().Get("todate"), "inbound searches are not split on the sender Date header")
+
+		messages := make([]string, count)
+		for i := range messages {
+			messages[i] = fmt.Sprintf(`{"MessageID": "%d", "Date": "Mon, 3 Jun 2024 10:00:00 +0200"}`, offset+i)
+		}
+		_, _ = fmt.Fprintf(w, `{"TotalCount": %d, "InboundMessages": [%s]}`, maxSearchOffset+1, strings.Join(messages, ","))
+	})
+
+	seen := 0
+	var gotErr error
+	for _, err := range s.client.InboundMessages(context.Background(), nil) {
+		if err != nil {
+			gotErr = err
+			break
+		}
+		seen++
+	}
+
+	s.Require().ErrorIs(gotErr, ErrOffsetLimitReached)
+	s.Equal(maxSearchOffset, seen)
+}
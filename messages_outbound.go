@@ -3,6 +3,7 @@ package postmark
 import (
 	"context"
 	"fmt"
+	"iter"
 	"net/url"
 	"time"
 )
@@ -65,14 +66,14 @@ type MessageEvent struct {
 // GetOutboundMessage fetches a specific outbound message via serverID
 func (client *Client) GetOutboundMessage(ctx context.Context, messageID string) (OutboundMessage, error) {
 	res := OutboundMessage{}
-	err := client.get(ctx, fmt.Sprintf("messages/outbound/%s/details", messageID), &res)
+	err := client.get(ctx, "GetOutboundMessage", fmt.Sprintf("messages/outbound/%s/details", messageID), &res)
 	return res, err
 }
 
 // GetOutboundMessageDump fetches the raw source of message. If no dump is available this will return an empty string.
 func (client *Client) GetOutboundMessageDump(ctx context.Context, messageID string) (string, error) {
 	res := dumpResponse{}
-	err := client.get(ctx, fmt.Sprintf("messages/outbound/%s/dump", messageID), &res)
+	err := client.get(ctx, "GetOutboundMessageDump", fmt.Sprintf("messages/outbound/%s/dump", messageID), &res)
 	return res.Body, err
 }
 
@@ -84,7 +85,7 @@ type outboundMessagesResponse struct {
 // GetOutboundMessages fetches a list of outbound message on the server
 // It returns a OutboundMessage slice, the total message count, and any error that occurred
 // A single open is bound to a single recipient, so if the same message was sent to two recipients and both of them opened it, that will be represented by two entries in this array.
-// Available options: http://developer.postmarkapp.com/developer-api-messages.html#outbound-message-search
+// Available options: http://developer.postmarkapp.com/developer-api-messages.html#outbound-message-search (see OutboundMessageFilter)
 func (client *Client) GetOutboundMessages(ctx context.Context, count, offset int64, options map[string]interface{}) ([]OutboundMessage, int64, error) {
 	res := outboundMessagesResponse{}
 
@@ -95,10 +96,22 @@ func (client *Client) GetOutboundMessages(ctx context.Context, count, offset int
 	options["count"] = count
 	options["offset"] = offset
 
-	err := client.get(ctx, buildURL("messages/outbound", options), &res)
+	err := client.get(ctx, "GetOutboundMessages", buildURL("messages/outbound", options), &res)
 	return res.Messages, res.TotalCount, err
 }
 
+// OutboundMessages returns an iterator over every outbound message matching the options, fetching pages as needed
+// Searches with more than 10,000 results are split into smaller date ranges automatically
+// Available options: http://developer.postmarkapp.com/developer-api-messages.html#outbound-message-search
+func (client *Client) OutboundMessages(ctx context.Context, options map[string]interface{}) iter.Seq2[OutboundMessage, error] {
+	return paginate(ctx, pager[OutboundMessage]{
+		fetch:     client.GetOutboundMessages,
+		limit:     maxSearchOffset,
+		timestamp: func(message OutboundMessage) time.Time { return message.ReceivedAt },
+		key:       func(message OutboundMessage) string { return message.MessageID },
+	}, options)
+}
+
 // Open represents a single email open.
 type Open struct {
 	// FirstOpen - Indicates if the open was first open of message with MessageID and by Recipient. Any subsequent opens of the same message by the same Recipient will show false in this field. Postmark only saves first opens to its store, while all opens are available via Open web hooks.
@@ -162,7 +175,7 @@ type outboundMessageClicksResponse struct {
 // GetOutboundMessagesOpens fetches a list of opens on the server
 // It returns an Open slice, the total opens count, and any error that occurred
 // To get opens for a specific message, use GetOutboundMessageOpens()
-// Available options: http://developer.postmarkapp.com/developer-api-messages.html#message-opens
+// Available options: http://developer.postmarkapp.com/developer-api-messages.html#message-opens (see MessageEventFilter)
 func (client *Client) GetOutboundMessagesOpens(ctx context.Context, count, offset int64, options map[string]interface{}) ([]Open, int64, error) {
 	res := outboundMessageOpensResponse{}
 
@@ -173,10 +186,20 @@ func (client *Client) GetOutboundMessagesOpens(ctx context.Context, count, offse
 	options["count"] = count
 	options["offset"] = offset
 
-	err := client.get(ctx, buildURL("messages/outbound/opens", options), &res)
+	err := client.get(ctx, "GetOutboundMessagesOpens", buildURL("messages/outbound/opens", options), &res)
 	return res.Opens, res.TotalCount, err
 }
 
+// OutboundMessagesOpens returns an iterator over every open matching the options, fetching pages as needed
+// The iterator yields ErrOffsetLimitReached if more than 10,000 opens match
+// Available options: http://developer.postmarkapp.com/developer-api-messages.html#message-opens
+func (client *Client) OutboundMessagesOpens(ctx context.Context, options map[string]interface{}) iter.Seq2[Open, error] {
+	return paginate(ctx, pager[Open]{
+		fetch: client.GetOutboundMessagesOpens,
+		limit: maxSearchOffset,
+	}, options)
+}
+
 // GetOutboundMessageOpens fetches a list of opens for a specific message
 // It returns an Open slice, the total opens count, and any error that occurred
 func (client *Client) GetOutboundMessageOpens(ctx context.Context, messageID string, count, offset int64) ([]Open, int64, error) {
@@ -186,14 +209,14 @@ func (client *Client) GetOutboundMessageOpens(ctx context.Context, messageID str
 	values.Add("count", fmt.Sprintf("%d", count))
 	values.Add("offset", fmt.Sprintf("%d", offset))
 
-	err := client.get(ctx, buildURLWithQuery(fmt.Sprintf("messages/outbound/opens/%s", messageID), *values), &res)
+	err := client.get(ctx, "GetOutboundMessageOpens", buildURLWithQuery(fmt.Sprintf("messages/outbound/opens/%s", messageID), *values), &res)
 	return res.Opens, res.TotalCount, err
 }
 
 // GetOutboundMessagesClicks fetches a list of clicks on the server
 // It returns a Click slice, the total clicks count, and any error that occurred
 // To get clicks for a specific message, use GetOutboundMessageClicks()
-// Available options: http://developer.postmarkapp.com/developer-api-messages.html#message-clicks
+// Available options: http://developer.postmarkapp.com/developer-api-messages.html#message-clicks (see MessageEventFilter)
 func (client *Client) GetOutboundMessagesClicks(ctx context.Context, count, offset int64, options map[string]interface{}) ([]Click, int64, error) {
 	res := outboundMessageClicksResponse{}
 
@@ -204,10 +227,20 @@ func (client *Client) GetOutboundMessagesClicks(ctx context.Context, count, offs
 	options["count"] = count
 	options["offset"] = offset
 
-	err := client.get(ctx, buildURL("messages/outbound/clicks", options), &res)
+	err := client.get(ctx, "GetOutboundMessagesClicks", buildURL("messages/outbound/clicks", options), &res)
 	return res.Clicks, res.TotalCount, err
 }
 
+// OutboundMessagesClicks returns an iterator over every click matching the options, fetching pages as needed
+// The iterator yields ErrOffsetLimitReached if more than 10,000 clicks match
+// Available options: http://developer.postmarkapp.com/developer-api-messages.html#message-clicks
+func (client *Client) OutboundMessagesClicks(ctx context.Context, options map[string]interface{}) iter.Seq2[Click, error] {
+	return paginate(ctx, pager[Click]{
+		fetch: client.GetOutboundMessagesClicks,
+		limit: maxSearchOffset,
+	}, options)
+}
+
 // GetOutboundMessageClicks fetches a list of clicks for a specific message
 // It returns a Click slice, the total clicks count, and any error that occurred
 func (client *Client) GetOutboundMessageClicks(ctx context.Context, messageID string, count, offset int64) ([]Click, int64, error) {
@@ -217,6 +250,6 @@ func (client *Client) GetOutboundMessageClicks(ctx context.Context, messageID st
 	values.Add("count", fmt.Sprintf("%d", count))
 	values.Add("offset", fmt.Sprintf("%d", offset))
 
-	err := client.get(ctx, buildURLWithQuery(fmt.Sprintf("messages/outbound/clicks/%s", messageID), *values), &res)
+	err := client.get(ctx, "GetOutboundMessageClicks", buildURLWithQuery(fmt.Sprintf("messages/outbound/clicks/%s", messageID), *values), &res)
 	return res.Clicks, res.TotalCount, err
 }
@@ -0,0 +1,334 @@
+package postmark
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Mustachio is the template language of Postmark: {{ value }} is HTML escaped, {{{ value }}} and {{& value }} are not,
+// {{#path}} and {{^path}} open sections and inverted sections, {{#each path}} loops over a list and {{! }} is a comment.
+// Paths are dotted, relative to the current scope: "." is the scope itself, "../" its parent and "~" the model root.
+
+// ErrTemplateSyntax is returned when a template cannot be parsed
+var ErrTemplateSyntax = errors.New("invalid template syntax")
+
+// TemplateSyntaxError locates a syntax error in a template field
+type TemplateSyntaxError struct {
+	// Field is the template field containing the error: Subject, HTMLBody or TextBody
+	Field string
+	ValidationError
+}
+
+// Error returns the field, position and message of the error
+func (e TemplateSyntaxError) Error() string {
+	return fmt.Sprintf("%s: %s at line %d, character %d", e.Field, e.Message, e.Line, e.CharacterPosition)
+}
+
+// Unwrap returns ErrTemplateSyntax
+func (e TemplateSyntaxError) Unwrap() error {
+	return ErrTemplateSyntax
+}
+
+// contentPath is the layout placeholder replaced by the rendered template content
+const contentPath = "@content"
+
+// mustachioKind is the kind of a parsed template node
+type mustachioKind int
+
+const (
+	mustachioText mustachioKind = iota
+	mustachioValue
+	mustachioRawValue
+	mustachioSection
+	mustachioInverted
+	mustachioEach
+)
+
+// mustachioNode is a parsed piece of a template
+type mustachioNode struct {
+	kind     mustachioKind
+	text     string // content of text nodes, path of the other nodes
+	children []mustachioNode
+}
+
+// mustachioParser turns a template into nodes
+type mustachioParser struct {
+	field  string
+	source string
+	pos    int
+}
+
+// parseMustachio parses the template of a field
+func parseMustachio(field, source string) ([]mustachioNode, error) {
+	p := &mustachioParser{field: field, source: source}
+	nodes, _, err := p.parse("")
+	return nodes, err
+}
+
+// parse reads nodes until the closing tag of the section, or the end of the template when section is empty.
+// It returns the name of the closing tag it stopped at.
+func (p *mustachioParser) parse(section string) ([]mustachioNode, string, error) {
+	var nodes []mustachioNode
+	for p.pos < len(p.source) {
+		start := strings.Index(p.source[p.pos:], "{{")
+		if start < 0 {
+			nodes = append(nodes, mustachioNode{kind: mustachioText, text: p.source[p.pos:]})
+			p.pos = len(p.source)
+			break
+		}
+		if start > 0 {
+			nodes = append(nodes, mustachioNode{kind: mustachioText, text: p.source[p.pos : p.pos+start]})
+		}
+
+		tagStart := p.pos + start
+		tag, err := p.readTag(tagStart)
+		if err != nil {
+			return nil, "", err
+		}
+		if tag == "" {
+			return nil, "", p.errorAt(tagStart, "empty tag")
+		}
+
+		kind, path := tag[:1], strings.TrimSpace(tag[1:])
+		switch kind {
+		case "!":
+			continue
+		case "{", "&", "/", "#", "^":
+		default:
+			kind, path = "", tag
+		}
+		if path == "" {
+			return nil, "", p.errorAt(tagStart, "empty tag")
+		}
+
+		switch kind {
+		case "{", "&":
+			nodes = append(nodes, mustachioNode{kind: mustachioRawValue, text: path})
+		case "/":
+			if path != section {
+				return nil, "", p.errorAt(tagStart, fmt.Sprintf("unexpected closing tag {{/%s}}", path))
+			}
+			return nodes, path, nil
+		case "#", "^":
+			node, err := p.parseSection(kind, path)
+			if err != nil {
+				return nil, "", err
+			}
+			nodes = append(nodes, node)
+		default:
+			nodes = append(nodes, mustachioNode{kind: mustachioValue, text: path})
+		}
+	}
+
+	if section != "" {
+		return nil, "", p.errorAt(len(p.source), fmt.Sprintf("section {{#%s}} is not closed", section))
+	}
+	return nodes, "", nil
+}
+
+// parseSection parses the children of a section opened with kind "#" or "^"
+func (p *mustachioParser) parseSection(kind, path string) (mustachioNode, error) {
+	node := mustachioNode{kind: mustachioSection, text: path}
+	closing := path
+	if kind == "^" {
+		node.kind = mustachioInverted
+	} else if list, ok := strings.CutPrefix(path, "each "); ok {
+		node.kind, node.text, closing = mustachioEach, strings.TrimSpace(list), "each"
+	}
+
+	children, _, err := p.parse(closing)
+	if err != nil {
+		return node, err
+	}
+	node.children = children
+	return node, nil
+}
+
+// readTag reads the tag starting at start and returns its content without the braces
+func (p *mustachioParser) readTag(start int) (string, error) {
+	open, closing := "{{", "}}"
+	if strings.HasPrefix(p.source[start:], "{{{") {
+		open, closing = "{{{", "}}}"
+	}
+
+	end := strings.Index(p.source[start+len(open):], closing)
+	if end < 0 {
+		return "", p.errorAt(start, "tag is not closed")
+	}
+	tag := strings.TrimSpace(p.source[start+len(open) : start+len(open)+end])
+	p.pos = start + len(open) + end + len(closing)
+
+	if open == "{{{" {
+		return "{" + tag, nil
+	}
+	return tag, nil
+}
+
+// errorAt returns a syntax error at an offset of the source
+func (p *mustachioParser) errorAt(offset int, message string) error {
+	line := 1 + strings.Count(p.source[:offset], "\n")
+	column := offset - strings.LastIndex(p.source[:offset], "\n")
+	return TemplateSyntaxError{
+		Field:           p.field,
+		ValidationError: ValidationError{Message: message, Line: line, CharacterPosition: column},
+	}
+}
+
+// mustachioScope is the value a path is resolved against, linked to its parent scope
+type mustachioScope struct {
+	value  interface{}
+	parent *mustachioScope
+}
+
+// renderMustachio renders nodes, escaping values when escape is set. content replaces {{{ @content }}} in layouts.
+func renderMustachio(buf *bytes.Buffer, nodes []mustachioNode, scope *mustachioScope, escape bool, content *string) {
+	for _, node := range nodes {
+		switch node.kind {
+		case mustachioText:
+			buf.WriteString(node.text)
+		case mustachioValue, mustachioRawValue:
+			if node.text == contentPath && content != nil {
+				buf.WriteString(*content)
+				continue
+			}
+			text := formatMustachioValue(scope.resolve(node.text))
+			if escape && node.kind == mustachioValue {
+				text = html.EscapeString(text)
+			}
+			buf.WriteString(text)
+		case mustachioSection:
+			value := scope.resolve(node.text)
+			if !truthy(value) {
+				continue
+			}
+			if list, ok := value.([]interface{}); ok {
+				for _, item := range list {
+					renderMustachio(buf, node.children, &mustachioScope{value: item, parent: scope}, escape, content)
+				}
+				continue
+			}
+			inner := scope
+			if _, ok := value.(map[string]interface{}); ok {
+				inner = &mustachioScope{value: value, parent: scope}
+			}
+			renderMustachio(buf, node.children, inner, escape, content)
+		case mustachioInverted:
+			if !truthy(scope.resolve(node.text)) {
+				renderMustachio(buf, node.children, scope, escape, content)
+			}
+		case mustachioEach:
+			for _, item := range eachItems(scope.resolve(node.text)) {
+				renderMustachio(buf, node.children, &mustachioScope{value: item, parent: scope}, escape, content)
+			}
+		}
+	}
+}
+
+// resolve returns the value of a path in the scope, nil if it does not exist
+func (s *mustachioScope) resolve(path string) interface{} {
+	scope := s
+	for path == ".." || strings.HasPrefix(path, "../") {
+		path = strings.TrimPrefix(strings.TrimPrefix(path, ".."), "/")
+		if scope.parent != nil {
+			scope = scope.parent
+		}
+	}
+	if path == "~" || strings.HasPrefix(path, "~.") {
+		for scope.parent != nil {
+			scope = scope.parent
+		}
+		path = strings.TrimPrefix(strings.TrimPrefix(path, "~"), ".")
+	}
+
+	value := scope.value
+	if path == "" || path == "." {
+		return value
+	}
+	for _, name := range strings.Split(path, ".") {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		if value, ok = object[name]; !ok {
+			return nil
+		}
+	}
+	return value
+}
+
+// truthy reports whether a section renders for the value
+func truthy(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case string:
+		return v != ""
+	case json.Number:
+		f, err := v.Float64()
+		return err != nil || f != 0
+	case []interface{}:
+		return len(v) > 0
+	case map[string]interface{}:
+		return true
+	}
+	return true
+}
+
+// eachItems returns the items of a list, or the values of an object
+func eachItems(value interface{}) []interface{} {
+	switch v := value.(type) {
+	case []interface{}:
+		return v
+	case map[string]interface{}:
+		items := make([]interface{}, 0, len(v))
+		for _, key := range slices.Sorted(maps.Keys(v)) {
+			items = append(items, v[key])
+		}
+		return items
+	}
+	return nil
+}
+
+// formatMustachioValue returns the text of a value, objects and lists have none
+func formatMustachioValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case bool:
+		if v {
+			return "true"
+		}
+		return "false"
+	}
+	return ""
+}
+
+// mustachioModel converts a model, a map or a struct, into the generic values used for rendering
+func mustachioModel(model interface{}) (interface{}, error) {
+	if model == nil {
+		return map[string]interface{}{}, nil
+	}
+
+	data, err := json.Marshal(model)
+	if err != nil {
+		return nil, fmt.Errorf("encode template model: %w", err)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err = decoder.Decode(&value); err != nil {
+		return nil, fmt.Errorf("decode template model: %w", err)
+	}
+	return value, nil
+}
@@ -0,0 +1,96 @@
+package postmark
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderMustachio(t *testing.T) {
+	model := map[string]interface{}{
+		"name":    "Jane <admin>",
+		"count":   0,
+		"price":   12.5,
+		"company": map[string]interface{}{"name": "Acme", "address": map[string]interface{}{"city": "Paris"}},
+		"items": []interface{}{
+			map[string]interface{}{"title": "Book", "qty": 2},
+			map[string]interface{}{"title": "Pen", "qty": 1},
+		},
+		"tags":    []string{"a", "b"},
+		"premium": true,
+		"empty":   []string{},
+	}
+
+	tests := []struct {
+		name     string
+		template string
+		escape   bool
+		want     string
+	}{
+		{name: "escaped value", template: "Hi {{ name }}!", escape: true, want: "Hi Jane &lt;admin&gt;!"},
+		{name: "not escaped outside html", template: "Hi {{name}}", want: "Hi Jane <admin>"},
+		{name: "raw value", template: "{{{ name }}} {{& name}}", escape: true, want: "Jane <admin> Jane <admin>"},
+		{name: "dotted path", template: "{{company.address.city}}", want: "Paris"},
+		{name: "number", template: "{{price}} {{count}}", want: "12.5 0"},
+		{name: "missing value", template: "[{{nope}}{{company.nope.deeper}}]", want: "[]"},
+		{name: "comment", template: "a{{! ignored }}b", want: "ab"},
+		{name: "object section", template: "{{#company}}{{name}} in {{address.city}}{{/company}}", want: "Acme in Paris"},
+		{name: "parent path", template: "{{#company}}{{../name}}{{/company}}", want: "Jane <admin>"},
+		{name: "root path", template: "{{#items}}{{~.premium}}{{/items}}", want: "truetrue"},
+		{name: "list section", template: "{{#items}}{{title}}x{{qty}} {{/items}}", want: "Bookx2 Penx1 "},
+		{name: "bool section", template: "{{#premium}}VIP {{name}}{{/premium}}", want: "VIP Jane <admin>"},
+		{name: "zero is falsy", template: "{{#count}}some{{/count}}{{^count}}none{{/count}}", want: "none"},
+		{name: "empty list", template: "{{#empty}}x{{/empty}}{{^empty}}no items{{/empty}}", want: "no items"},
+		{name: "inverted missing", template: "{{^nope}}fallback{{/nope}}", want: "fallback"},
+		{name: "each", template: "{{#each tags}}[{{.}}]{{/each}}", want: "[a][b]"},
+		{name: "each objects", template: "{{#each items}}{{title}};{{/each}}", want: "Book;Pen;"},
+		{name: "nested each", template: "{{#each items}}{{#each ../tags}}{{.}}{{/each}}{{/each}}", want: "abab"},
+		{name: "braces in text", template: "a { b } c", want: "a { b } c"},
+	}
+
+	root, err := mustachioModel(model)
+	require.NoError(t, err)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodes, err := parseMustachio("HTMLBody", tt.template)
+			require.NoError(t, err)
+
+			var buf bytes.Buffer
+			renderMustachio(&buf, nodes, &mustachioScope{value: root}, tt.escape, nil)
+			assert.Equal(t, tt.want, buf.String())
+		})
+	}
+}
+
+func TestParseMustachioErrors(t *testing.T) {
+	tests := []struct {
+		template string
+		message  string
+		line     int
+		position int
+	}{
+		{template: "Hi {{name", message: "tag is not closed", line: 1, position: 4},
+		{template: "a\n{{#items}}x", message: "section {{#items}} is not closed", line: 2, position: 12},
+		{template: "{{#a}}{{/b}}", message: "unexpected closing tag {{/b}}", line: 1, position: 7},
+		{template: "x\ny {{/a}}", message: "unexpected closing tag {{/a}}", line: 2, position: 3},
+		{template: "{{ }}", message: "empty tag", line: 1, position: 1},
+		{template: "{{#each items}}{{/items}}", message: "unexpected closing tag {{/items}}", line: 1, position: 16},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.template, func(t *testing.T) {
+			_, err := parseMustachio("TextBody", tt.template)
+			require.ErrorIs(t, err, ErrTemplateSyntax)
+
+			var syntaxErr TemplateSyntaxError
+			require.ErrorAs(t, err, &syntaxErr)
+			assert.Equal(t, "TextBody", syntaxErr.Field)
+			assert.Equal(t, tt.message, syntaxErr.Message)
+			assert.Equal(t, tt.line, syntaxErr.Line)
+			assert.Equal(t, tt.position, syntaxErr.CharacterPosition)
+		})
+	}
+}
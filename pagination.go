@@ -0,0 +1,136 @@
+package postmark
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"maps"
+	"time"
+)
+
+const (
+	// defaultPageSize is the largest page Postmark returns for list endpoints
+	defaultPageSize = 500
+
+	// maxSearchOffset is the highest count+offset Postmark allows when searching messages and bounces
+	maxSearchOffset = 10000
+
+	// searchDateFormat is the timestamp format accepted by the fromdate/todate search filters
+	searchDateFormat = "2006-01-02T15:04:05"
+)
+
+// ErrOffsetLimitReached is returned by iterators when Postmark's 10,000 record search limit
+// is reached and the results cannot be split into smaller date ranges
+var ErrOffsetLimitReached = errors.New("postmark search limit of 10,000 records reached, narrow the filter")
+
+// pager describes how to page through a count/offset list endpoint
+type pager[T any] struct {
+	// fetch returns one page of items along with the total number of matching items
+	fetch func(ctx context.Context, count, offset int64, options map[string]interface{}) ([]T, int64, error)
+	// limit is the highest count+offset the endpoint accepts, zero means no limit
+	limit int64
+	// timestamp returns when an item happened, used to split searches that hit the limit (optional)
+	timestamp func(item T) time.Time
+	// key uniquely identifies an item, used to skip duplicates when a search is split (optional)
+	key func(item T) string
+}
+
+// paginate returns an iterator over every item matched by the pager.
+//
+// Search endpoints return items newest first and stop at 10,000 records. When that limit is reached
+// and the pager knows the item timestamps, the search restarts with todate set to the oldest item
+// seen so far, skipping the items that were already yielded at that boundary.
+//
+// The iterator stops after yielding the first error, including context cancellation.
+func paginate[T any](ctx context.Context, p pager[T], options map[string]interface{}) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+
+		// Work on a copy, the Get methods add count and offset to the options they receive
+		opts := maps.Clone(options)
+		if opts == nil {
+			opts = make(map[string]interface{})
+		}
+
+		var (
+			offset       int64
+			windowEnd    time.Time                   // current todate when the search has been split
+			splitAt      time.Time                   // boundary second when the search was last split
+			boundary     time.Time                   // second of the oldest item yielded so far
+			boundaryKeys = make(map[string]struct{}) // keys of the items yielded within the boundary second
+		)
+
+		for {
+			if err := ctx.Err(); err != nil {
+				yield(zero, err)
+				return
+			}
+
+			count := int64(defaultPageSize)
+			if p.limit > 0 && offset+count > p.limit {
+				count = p.limit - offset
+			}
+
+			items, total, err := p.fetch(ctx, count, offset, maps.Clone(opts))
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+
+			for _, item := range items {
+				if !windowEnd.IsZero() && alreadyYielded(p, item, splitAt, boundaryKeys) {
+					continue
+				}
+
+				if !yield(item, nil) {
+					return
+				}
+
+				if p.timestamp != nil && p.key != nil {
+					second := p.timestamp(item).Truncate(time.Second)
+					if !second.Equal(boundary) {
+						boundary = second
+						boundaryKeys = make(map[string]struct{})
+					}
+					boundaryKeys[p.key(item)] = struct{}{}
+				}
+			}
+
+			offset += int64(len(items))
+			if len(items) == 0 || offset >= total {
+				return
+			}
+
+			if p.limit == 0 || offset < p.limit {
+				continue
+			}
+
+			// The limit was reached, restart the search just after the oldest second seen so far.
+			// Postmark filters by whole seconds, so items within that second are returned again and skipped.
+			nextEnd := boundary.Add(time.Second)
+			if p.timestamp == nil || p.key == nil || boundary.IsZero() || (!windowEnd.IsZero() && !nextEnd.Before(windowEnd)) {
+				yield(zero, ErrOffsetLimitReached)
+				return
+			}
+
+			windowEnd = nextEnd
+			splitAt = boundary
+			opts["todate"] = windowEnd.Format(searchDateFormat)
+			offset = 0
+		}
+	}
+}
+
+// alreadyYielded reports whether an item returned by a split search was yielded before the split.
+// Everything newer than the split second was, and so were the recorded items within that second.
+func alreadyYielded[T any](p pager[T], item T, splitAt time.Time, keys map[string]struct{}) bool {
+	second := p.timestamp(item).Truncate(time.Second)
+	if second.After(splitAt) {
+		return true
+	}
+	if !second.Equal(splitAt) {
+		return false
+	}
+	_, seen := keys[p.key(item)]
+	return seen
+}
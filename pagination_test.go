@@ -0,0 +1,273 @@
+package postmark
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// pagedItem is a minimal search result used to exercise the paginator
+type pagedItem struct {
+	ID   int
+	Time time.Time
+}
+
+// fakeSearch emulates a Postmark search endpoint over items sorted newest first, honoring todate
+type fakeSearch struct {
+	items []pagedItem
+	calls int
+	err   error
+}
+
+func (f *fakeSearch) fetch(_ context.Context, count, offset int64, options map[string]interface{}) ([]pagedItem, int64, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, 0, f.err
+	}
+	if offset+count > maxSearchOffset {
+		return nil, 0, APIError{ErrorCode: 300, Message: "offset + count exceeds 10000"}
+	}
+
+	matched := f.items
+	if todate, ok := options["todate"].(string); ok {
+		end, err := time.ParseInLocation(searchDateFormat, todate, time.UTC)
+		if err != nil {
+			return nil, 0, err
+		}
+		matched = nil
+		for _, item := range f.items {
+			if !item.Time.After(end) {
+				matched = append(matched, item)
+			}
+		}
+	}
+
+	total := int64(len(matched))
+	if offset >= total {
+		return nil, total, nil
+	}
+	return matched[offset:min(offset+count, total)], total, nil
+}
+
+// newFakeSearch builds n items, newest first, spaced by the given interval
+func newFakeSearch(n int, interval time.Duration) *fakeSearch {
+	start := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	items := make([]pagedItem, n)
+	for i := range items {
+		items[i] = pagedItem{ID: i, Time: start.Add(-time.Duration(i) * interval)}
+	}
+	return &fakeSearch{items: items}
+}
+
+func (f *fakeSearch) pager(splittable bool) pager[pagedItem] {
+	p := pager[pagedItem]{fetch: f.fetch, limit: maxSearchOffset}
+	if splittable {
+		p.timestamp = func(item pagedItem) time.Time { return item.Time }
+		p.key = func(item pagedItem) string { return strconv.Itoa(item.ID) }
+	}
+	return p
+}
+
+func (s *PostmarkTestSuite) TestPaginate() {
+	tests := []struct {
+		name       string
+		items      int
+		interval   time.Duration
+		splittable bool
+		wantCount  int
+		wantCalls  int
+		wantErr    error
+	}{
+		{name: "empty result", items: 0, interval: time.Second, wantCount: 0, wantCalls: 1},
+		{name: "single page", items: 120, interval: time.Second, wantCount: 120, wantCalls: 1},
+		{name: "several pages", items: 1200, interval: time.Second, wantCount: 1200, wantCalls: 3},
+		{name: "exactly at the limit", items: maxSearchOffset, interval: time.Second, wantCount: maxSearchOffset, wantCalls: 20},
+		{
+			name:      "limit reached without timestamps",
+			items:     maxSearchOffset + 1,
+			interval:  time.Second,
+			wantCount: maxSearchOffset,
+			wantCalls: 20,
+			wantErr:   ErrOffsetLimitReached,
+		},
+		{
+			name:       "limit reached splits on date",
+			items:      maxSearchOffset + 600,
+			interval:   100 * time.Millisecond,
+			splittable: true,
+			wantCount:  maxSearchOffset + 600,
+			wantCalls:  22,
+		},
+		{
+			name:       "limit reached within a single second",
+			items:      maxSearchOffset + 600,
+			interval:   time.Microsecond,
+			splittable: true,
+			wantCount:  maxSearchOffset,
+			wantCalls:  40,
+			wantErr:    ErrOffsetLimitReached,
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			search := newFakeSearch(tt.items, tt.interval)
+
+			seen := make(map[int]int)
+			var gotErr error
+			for item, err := range paginate(context.Background(), search.pager(tt.splittable), nil) {
+				if err != nil {
+					gotErr = err
+					break
+				}
+				seen[item.ID]++
+			}
+
+			if tt.wantErr != nil {
+				s.Require().ErrorIs(gotErr, tt.wantErr)
+			} else {
+				s.Require().NoError(gotErr)
+			}
+			s.Len(seen, tt.wantCount)
+			for id, count := range seen {
+				s.Equal(1, count, "item %d yielded more than once", id)
+			}
+			s.Equal(tt.wantCalls, search.calls)
+		})
+	}
+}
+
+func (s *PostmarkTestSuite) TestPaginateStopsEarly() {
+	search := newFakeSearch(1200, time.Second)
+
+	yielded := 0
+	for _, err := range paginate(context.Background(), search.pager(false), nil) {
+		s.Require().NoError(err)
+		yielded++
+		if yielded == 10 {
+			break
+		}
+	}
+
+	s.Equal(10, yielded)
+	s.Equal(1, search.calls, "no further pages should be fetched after break")
+}
+
+func (s *PostmarkTestSuite) TestPaginateFetchError() {
+	search := newFakeSearch(10, time.Second)
+	search.err = APIError{ErrorCode: 10, Message: "Bad or missing API token"}
+
+	var errs []error
+	for _, err := range paginate(context.Background(), search.pager(false), nil) {
+		errs = append(errs, err)
+	}
+
+	s.Require().Len(errs, 1)
+	s.Require().ErrorIs(errs[0], ErrInvalidAPIToken)
+}
+
+func (s *PostmarkTestSuite) TestPaginateContextCanceled() {
+	search := newFakeSearch(1200, time.Second)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var gotErr error
+	yielded := 0
+	for _, err := range paginate(ctx, search.pager(false), nil) {
+		if err != nil {
+			gotErr = err
+			break
+		}
+		yielded++
+		if yielded == defaultPageSize {
+			cancel()
+		}
+	}
+
+	s.Require().ErrorIs(gotErr, context.Canceled)
+	s.Equal(defaultPageSize, yielded)
+	s.Equal(1, search.calls)
+}
+
+func (s *PostmarkTestSuite) TestPaginateDoesNotModifyOptions() {
+	search := newFakeSearch(maxSearchOffset+600, 100*time.Millisecond)
+	options := map[string]interface{}{"tag": "welcome"}
+
+	for _, err := range paginate(context.Background(), search.pager(true), options) {
+		s.Require().NoError(err)
+	}
+
+	s.Equal(map[string]interface{}{"tag": "welcome"}, options)
+}
+
+func (s *PostmarkTestSuite) TestBouncesIterator() {
+	s.mux.Get("/bounces", func(w http.ResponseWriter, req *http.Request) {
+		s.Equal("HardBounce", req.URL.Query().Get("type"))
+
+		offset, err := strconv.Atoi(req.URL.Query().Get("offset"))
+		s.Require().NoError(err)
+
+		// Two pages, the second one shorter than a full page
+		ids := []int{offset + 1}
+		if offset == 0 {
+			ids = make([]int, defaultPageSize)
+			for i := range ids {
+				ids[i] = i + 1
+			}
+		}
+
+		bounces := ""
+		for i, id := range ids {
+			if i > 0 {
+				bounces += ","
+			}
+			bounces += fmt.Sprintf(`{"ID": %d, "Type": "HardBounce"}`, id)
+		}
+		_, _ = fmt.Fprintf(w, `{"TotalCount": %d, "Bounces": [%s]}`, defaultPageSize+1, bounces)
+	})
+
+	var ids []int64
+	for bounce, err := range s.client.Bounces(context.Background(), map[string]interface{}{"type": "HardBounce"}) {
+		s.Require().NoError(err)
+		ids = append(ids, bounce.ID)
+	}
+
+	s.Require().Len(ids, defaultPageSize+1)
+	s.Equal(int64(1), ids[0])
+	s.Equal(int64(defaultPageSize+1), ids[defaultPageSize])
+}
+
+func (s *PostmarkTestSuite) TestServersIterator() {
+	s.mux.Get("/servers", func(w http.ResponseWriter, req *http.Request) {
+		s.Equal("account-token", req.Header.Get("X-Postmark-Account-Token"))
+		s.Equal("Production", req.URL.Query().Get("name"))
+		_, _ = w.Write([]byte(`{"TotalCount": 2, "Servers": [{"ID": 1, "Name": "Production 1"}, {"ID": 2, "Name": "Production 2"}]}`))
+	})
+
+	var names []string
+	for server, err := range s.client.Servers(context.Background(), "Production") {
+		s.Require().NoError(err)
+		names = append(names, server.Name)
+	}
+
+	s.Equal([]string{"Production 1", "Production 2"}, names)
+}
+
+func (s *PostmarkTestSuite) TestTemplatesIteratorError() {
+	s.mux.Get("/templates", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"ErrorCode": 10, "Message": "Bad or missing API token"}`))
+	})
+
+	count := 0
+	var gotErr error
+	for _, err := range s.client.Templates(context.Background(), "", "") {
+		count++
+		gotErr = err
+	}
+
+	s.Equal(1, count)
+	s.Require().ErrorIs(gotErr, ErrInvalidAPIToken)
+}
@@ -7,7 +7,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
+	"sync"
+	"time"
 )
 
 const postmarkURL = `https://api.postmarkapp.com`
@@ -22,6 +25,23 @@ type Client struct {
 	AccountToken string
 	// BaseURL is the root API endpoint
 	BaseURL string
+	// Retry configures automatic retries of failed requests, nil disables retries (see DefaultRetryPolicy)
+	Retry *RetryPolicy
+	// RateLimit throttles requests on the client side, nil disables throttling
+	RateLimit *RateLimit
+	// Interceptors wrap every API call in order, the first one is the outermost (optional)
+	Interceptors []Interceptor
+	// ValidateEmails checks emails with Validate before sending them, invalid emails are not sent (optional)
+	ValidateEmails bool
+	// Logger logs every API call at debug level, with tokens, message bodies and attachments redacted (optional)
+	Logger *slog.Logger
+	// Dedupe remembers the idempotency keys of SendEmailIdempotent, the first idempotent send sets an in-memory LRU
+	// cache when it is nil (optional)
+	Dedupe DedupeCache
+	// DedupeLookup searches the outbound messages for an idempotency key missing from Dedupe before sending (optional)
+	DedupeLookup bool
+
+	dedupeOnce sync.Once
 }
 
 const (
@@ -41,44 +61,58 @@ func NewClient(serverToken, accountToken string) *Client {
 	}
 }
 
-func (client *Client) get(ctx context.Context, path string, dst interface{}) error {
-	return client.doRequest(ctx, http.MethodGet, path, nil, dst, serverToken)
+func (client *Client) get(ctx context.Context, name, path string, dst interface{}) error {
+	return client.doRequest(ctx, name, http.MethodGet, path, nil, dst, serverToken)
 }
 
-func (client *Client) getWithAccountToken(ctx context.Context, path string, dst interface{}) error {
-	return client.doRequest(ctx, http.MethodGet, path, nil, dst, accountToken)
+func (client *Client) getWithAccountToken(ctx context.Context, name, path string, dst interface{}) error {
+	return client.doRequest(ctx, name, http.MethodGet, path, nil, dst, accountToken)
 }
 
-func (client *Client) post(ctx context.Context, path string, payload, dst interface{}) error {
-	return client.doRequest(ctx, http.MethodPost, path, payload, dst, serverToken)
+func (client *Client) post(ctx context.Context, name, path string, payload, dst interface{}) error {
+	return client.doRequest(ctx, name, http.MethodPost, path, payload, dst, serverToken)
 }
 
-func (client *Client) postWithAccountToken(ctx context.Context, path string, payload, dst interface{}) error {
-	return client.doRequest(ctx, http.MethodPost, path, payload, dst, accountToken)
+func (client *Client) postWithAccountToken(ctx context.Context, name, path string, payload, dst interface{}) error {
+	return client.doRequest(ctx, name, http.MethodPost, path, payload, dst, accountToken)
 }
 
-func (client *Client) patch(ctx context.Context, path string, payload, dst interface{}) error {
-	return client.doRequest(ctx, http.MethodPatch, path, payload, dst, serverToken)
+func (client *Client) patch(ctx context.Context, name, path string, payload, dst interface{}) error {
+	return client.doRequest(ctx, name, http.MethodPatch, path, payload, dst, serverToken)
 }
 
-func (client *Client) put(ctx context.Context, path string, payload, dst interface{}) error {
-	return client.doRequest(ctx, http.MethodPut, path, payload, dst, serverToken)
+func (client *Client) put(ctx context.Context, name, path string, payload, dst interface{}) error {
+	return client.doRequest(ctx, name, http.MethodPut, path, payload, dst, serverToken)
 }
 
-func (client *Client) putWithAccountToken(ctx context.Context, path string, payload, dst interface{}) error {
-	return client.doRequest(ctx, http.MethodPut, path, payload, dst, accountToken)
+func (client *Client) putWithAccountToken(ctx context.Context, name, path string, payload, dst interface{}) error {
+	return client.doRequest(ctx, name, http.MethodPut, path, payload, dst, accountToken)
 }
 
-func (client *Client) delete(ctx context.Context, path string, dst interface{}) error {
-	return client.doRequest(ctx, http.MethodDelete, path, nil, dst, serverToken)
+func (client *Client) delete(ctx context.Context, name, path string, dst interface{}) error {
+	return client.doRequest(ctx, name, http.MethodDelete, path, nil, dst, serverToken)
 }
 
-func (client *Client) deleteWithAccountToken(ctx context.Context, path string, dst interface{}) error {
-	return client.doRequest(ctx, http.MethodDelete, path, nil, dst, accountToken)
+func (client *Client) deleteWithAccountToken(ctx context.Context, name, path string, dst interface{}) error {
+	return client.doRequest(ctx, name, http.MethodDelete, path, nil, dst, accountToken)
 }
 
-// doRequest performs the request to the Postmark API
-func (client *Client) doRequest(ctx context.Context, method, path string, payload, dst interface{}, tokenType string) (err error) {
+// doRequest performs the request to the Postmark API through the client's interceptors
+func (client *Client) doRequest(ctx context.Context, name, method, path string, payload, dst interface{}, tokenType string) error {
+	op := &Operation{
+		Name:      name,
+		Method:    method,
+		Path:      path,
+		TokenType: TokenType(tokenType),
+		Payload:   payload,
+		Header:    make(http.Header),
+	}
+	return chain(client.Interceptors, client.invoke)(ctx, op, dst)
+}
+
+// invoke performs the HTTP request(s) of an operation and decodes the response into dst
+func (client *Client) invoke(ctx context.Context, op *Operation, dst interface{}) (err error) {
+	method, path := op.Method, op.Path
 	url := fmt.Sprintf("%s/%s", client.BaseURL, path)
 
 	var req *http.Request
@@ -88,9 +122,9 @@ func (client *Client) doRequest(ctx context.Context, method, path string, payloa
 		return err
 	}
 
-	if payload != nil {
+	if op.Payload != nil {
 		var payloadData []byte
-		if payloadData, err = json.Marshal(payload); err != nil {
+		if payloadData, err = json.Marshal(op.Payload); err != nil {
 			return err
 		}
 		req.Body = io.NopCloser(bytes.NewBuffer(payloadData))
@@ -99,54 +133,110 @@ func (client *Client) doRequest(ctx context.Context, method, path string, payloa
 		}
 	}
 
+	start := time.Now()
+	defer func() {
+		client.logCall(ctx, op, req.Header, time.Since(start), err)
+	}()
+
 	req.Header.Add("Accept", "application/json")
 	req.Header.Add("Content-Type", "application/json")
 
-	switch tokenType {
+	switch op.TokenType {
 	case accountToken:
 		req.Header.Add("X-Postmark-Account-Token", client.AccountToken)
 	default:
 		req.Header.Add("X-Postmark-Server-Token", client.ServerToken)
 	}
 
+	for name, values := range op.Header {
+		for _, value := range values {
+			req.Header.Add(name, value)
+		}
+	}
+
+	var body []byte
+	attempts := client.Retry.attempts(method)
+	for attempt := 1; ; attempt++ {
+		if err = client.RateLimit.wait(ctx, op.TokenType); err != nil {
+			return err
+		}
+
+		var statusCode int
+		var header http.Header
+		op.Attempts = attempt
+		statusCode, header, body, err = client.send(req, path)
+		op.StatusCode = statusCode
+		if err == nil {
+			break
+		}
+
+		if attempt >= attempts || !client.Retry.shouldRetry(ctx, statusCode, err) {
+			return err
+		}
+
+		backoff := client.Retry.backoff(attempt, header)
+		client.logRetry(ctx, op, backoff, err)
+		if err = sleepContext(ctx, backoff); err != nil {
+			return err
+		}
+
+		// Each attempt needs a fresh request body, so rewind it through GetBody
+		req = req.Clone(ctx)
+		if req.GetBody != nil {
+			if req.Body, err = req.GetBody(); err != nil {
+				return err
+			}
+		}
+	}
+
+	if dst == nil {
+		return nil
+	}
+
+	if err = json.Unmarshal(body, dst); err != nil {
+		return err
+	}
+
+	// Some endpoints report failures in the body of a successful response
+	if coder, ok := dst.(errorCoder); ok {
+		if apiErr := coder.postmarkError(); apiErr.ErrorCode != 0 {
+			apiErr.StatusCode = http.StatusOK
+			apiErr.Method = method
+			apiErr.Path = path
+			apiErr.Body = string(body)
+			return apiErr
+		}
+	}
+
+	return nil
+}
+
+// send performs a single HTTP attempt and turns error responses into errors.
+// The status code is zero when no response was received.
+func (client *Client) send(req *http.Request, path string) (statusCode int, header http.Header, body []byte, err error) {
 	var res *http.Response
 	if res, err = client.HTTPClient.Do(req); err != nil {
-		return err
+		return 0, nil, nil, err
 	}
 
 	defer func() {
 		_ = res.Body.Close()
 	}()
-	var body []byte
 	if body, err = io.ReadAll(res.Body); err != nil {
-		return err
+		return res.StatusCode, res.Header, nil, err
 	}
 
 	if res.StatusCode >= http.StatusBadRequest {
 		// If the status code is not a success, attempt to unmarshall the body into the APIError struct.
+		// Bodies that are not JSON (e.g. from a proxy) still produce an APIError carrying the status and body.
 		var apiErr APIError
-		if err = json.Unmarshal(body, &apiErr); err != nil {
-			return fmt.Errorf("request failed with status %d: %w", res.StatusCode, err)
-		}
-		return apiErr
-	}
-
-	if dst == nil {
-		return nil
+		_ = json.Unmarshal(body, &apiErr)
+		apiErr.StatusCode = res.StatusCode
+		apiErr.Method = req.Method
+		apiErr.Path = path
+		apiErr.Body = string(body)
+		return res.StatusCode, res.Header, body, apiErr
 	}
 
-	return json.Unmarshal(body, dst)
-}
-
-// APIError represents errors returned by Postmark
-type APIError struct {
-	// ErrorCode: see error codes here (https://postmarkapp.com/developer/api/overview#error-codes)
-	ErrorCode int64 `json:"ErrorCode"`
-	// Message contains error details
-	Message string `json:"Message"`
-}
-
-// Error returns the error message details
-func (res APIError) Error() string {
-	return res.Message
+	return res.StatusCode, res.Header, body, nil
 }
@@ -43,6 +43,14 @@ func (s *PostmarkTestSuite) SetupSuite() {
 	s.client.BaseURL = s.server.URL
 }
 
+// newClient returns a new client of the test server, for tests that change its settings
+func (s *PostmarkTestSuite) newClient() *Client {
+	client := NewClient("server-token", "account-token")
+	client.HTTPClient = s.client.HTTPClient
+	client.BaseURL = s.client.BaseURL
+	return client
+}
+
 func (s *PostmarkTestSuite) TearDownSuite() {
 	if s.server != nil {
 		s.server.Close()
@@ -65,7 +73,7 @@ func (s *PostmarkTestSuite) TestDoRequestWithPayload() {
 	})
 
 	var result map[string]string
-	err := s.client.doRequest(context.Background(), http.MethodPost, "test", payload, &result, serverToken)
+	err := s.client.doRequest(context.Background(), "", http.MethodPost, "test", payload, &result, serverToken)
 
 	s.Require().NoError(err)
 	s.Equal("success", result["message"])
@@ -81,7 +89,7 @@ func (s *PostmarkTestSuite) TestDoRequestWithAccountToken() {
 	})
 
 	var result map[string]string
-	err := s.client.doRequest(context.Background(), http.MethodGet, "account-test", nil, &result, accountToken)
+	err := s.client.doRequest(context.Background(), "", http.MethodGet, "account-test", nil, &result, accountToken)
 
 	s.Require().NoError(err)
 	s.Equal("success", result["message"])
@@ -94,7 +102,7 @@ func (s *PostmarkTestSuite) TestDoRequestHTTPError() {
 	})
 
 	var result map[string]string
-	err := s.client.doRequest(context.Background(), http.MethodGet, "error-test", nil, &result, serverToken)
+	err := s.client.doRequest(context.Background(), "", http.MethodGet, "error-test", nil, &result, serverToken)
 
 	s.Require().Error(err)
 }
@@ -104,7 +112,7 @@ func (s *PostmarkTestSuite) TestDoRequestContextCancellation() {
 	cancel()
 
 	var result map[string]string
-	err := s.client.doRequest(ctx, http.MethodGet, "test", nil, &result, serverToken)
+	err := s.client.doRequest(ctx, "", http.MethodGet, "test", nil, &result, serverToken)
 
 	s.Require().Error(err)
 	s.Contains(err.Error(), "context canceled")
@@ -116,7 +124,7 @@ func (s *PostmarkTestSuite) TestDoRequestInvalidJSON() {
 	})
 
 	var result map[string]string
-	err := s.client.doRequest(context.Background(), http.MethodGet, "invalid-json", nil, &result, serverToken)
+	err := s.client.doRequest(context.Background(), "", http.MethodGet, "invalid-json", nil, &result, serverToken)
 
 	s.Require().Error(err)
 }
@@ -127,7 +135,7 @@ func (s *PostmarkTestSuite) TestDoRequestInvalidURL() {
 	client.BaseURL = "ht!tp://invalid url with spaces"
 
 	var result map[string]string
-	err := client.doRequest(context.Background(), http.MethodGet, "test", nil, &result, serverToken)
+	err := client.doRequest(context.Background(), "", http.MethodGet, "test", nil, &result, serverToken)
 
 	s.Require().Error(err, "Should fail with invalid URL")
 }
@@ -137,7 +145,7 @@ func (s *PostmarkTestSuite) TestDoRequestMarshalError() {
 	invalidPayload := make(chan int)
 
 	var result map[string]string
-	err := s.client.doRequest(context.Background(), http.MethodPost, "test", invalidPayload, &result, serverToken)
+	err := s.client.doRequest(context.Background(), "", http.MethodPost, "test", invalidPayload, &result, serverToken)
 
 	s.Require().Error(err, "Should fail when payload cannot be marshaled")
 	s.Contains(err.Error(), "json")
@@ -151,7 +159,7 @@ func (s *PostmarkTestSuite) TestDoRequestNilDestination() {
 	})
 
 	// Pass nil as destination - should not error, just skip unmarshaling
-	err := s.client.doRequest(context.Background(), http.MethodGet, "nil-dest", nil, nil, serverToken)
+	err := s.client.doRequest(context.Background(), "", http.MethodGet, "nil-dest", nil, nil, serverToken)
 
 	s.Require().NoError(err, "Should handle nil destination gracefully")
 }
@@ -164,7 +172,7 @@ func (s *PostmarkTestSuite) TestDoRequestHTTPErrorInvalidJSON() {
 	})
 
 	var result map[string]string
-	err := s.client.doRequest(context.Background(), http.MethodGet, "error-invalid-json", nil, &result, serverToken)
+	err := s.client.doRequest(context.Background(), "", http.MethodGet, "error-invalid-json", nil, &result, serverToken)
 
 	s.Require().Error(err)
 	s.Contains(err.Error(), "request failed with status")
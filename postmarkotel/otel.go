@@ -0,0 +1,225 @@
+// Package postmarkotel instruments a postmark.Client with OpenTelemetry tracing and metrics.
+//
+// It is a separate module, so the core postmark module does not depend on OpenTelemetry.
+//
+//	client := postmark.NewClient(serverToken, accountToken)
+//	if err := postmarkotel.Instrument(client); err != nil {
+//		return err
+//	}
+package postmarkotel
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/mrz1836/postmark"
+)
+
+// instrumentationName identifies the tracer and meter of this package
+const instrumentationName = "github.com/mrz1836/postmark/postmarkotel"
+
+// Attribute keys recorded on spans and metrics
+const (
+	OperationKey     = attribute.Key("postmark.operation")
+	ErrorCodeKey     = attribute.Key("postmark.error_code")
+	MessageStreamKey = attribute.Key("postmark.message_stream")
+	TagKey           = attribute.Key("postmark.tag")
+	BatchSizeKey     = attribute.Key("postmark.batch_size")
+	AttemptsKey      = attribute.Key("postmark.attempts")
+	OutcomeKey       = attribute.Key("postmark.outcome")
+
+	httpMethodKey     = attribute.Key("http.request.method")
+	httpStatusCodeKey = attribute.Key("http.response.status_code")
+)
+
+// Outcomes recorded with OutcomeKey
+const (
+	OutcomeSuccess = "success"
+	OutcomeError   = "error"
+	OutcomeSent    = "sent"
+	OutcomeFailed  = "failed"
+)
+
+// defaultMessageStream is the stream Postmark uses for emails without a MessageStream
+const defaultMessageStream = "outbound"
+
+// config holds the providers used by the instrumentation
+type config struct {
+	tracerProvider trace.TracerProvider
+	meterProvider  metric.MeterProvider
+}
+
+// Option configures the instrumentation
+type Option func(*config)
+
+// WithTracerProvider sets the tracer provider, the global provider is used by default
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(c *config) {
+		c.tracerProvider = provider
+	}
+}
+
+// WithMeterProvider sets the meter provider, the global provider is used by default
+func WithMeterProvider(provider metric.MeterProvider) Option {
+	return func(c *config) {
+		c.meterProvider = provider
+	}
+}
+
+// instrumentation records the spans and metrics of Postmark calls
+type instrumentation struct {
+	tracer   trace.Tracer
+	duration metric.Float64Histogram
+	retries  metric.Int64Counter
+	messages metric.Int64Counter
+}
+
+// Instrument adds the tracing and metrics interceptor to the client
+func Instrument(client *postmark.Client, opts ...Option) error {
+	interceptor, err := NewInterceptor(opts...)
+	if err != nil {
+		return err
+	}
+	client.Interceptors = append(client.Interceptors, interceptor)
+	return nil
+}
+
+// NewInterceptor returns a postmark.Interceptor that records a client span per Postmark call, along with:
+//
+//   - postmark.client.duration: histogram of call durations in seconds, including retries
+//   - postmark.client.retries: counter of retried HTTP requests
+//   - postmark.client.messages: counter of messages submitted by SendEmail, SendEmailBatch,
+//     SendTemplatedEmail and SendTemplatedEmailBatch, by outcome (sent or failed)
+func NewInterceptor(opts ...Option) (postmark.Interceptor, error) {
+	cfg := config{
+		tracerProvider: otel.GetTracerProvider(),
+		meterProvider:  otel.GetMeterProvider(),
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	meter := cfg.meterProvider.Meter(instrumentationName)
+	inst := &instrumentation{tracer: cfg.tracerProvider.Tracer(instrumentationName)}
+
+	var err, errs error
+	inst.duration, err = meter.Float64Histogram("postmark.client.duration",
+		metric.WithUnit("s"),
+		metric.WithDescription("Duration of Postmark API calls, including retries"))
+	errs = errors.Join(errs, err)
+
+	inst.retries, err = meter.Int64Counter("postmark.client.retries",
+		metric.WithUnit("{request}"),
+		metric.WithDescription("Postmark API requests that were retried"))
+	errs = errors.Join(errs, err)
+
+	inst.messages, err = meter.Int64Counter("postmark.client.messages",
+		metric.WithUnit("{message}"),
+		metric.WithDescription("Messages submitted to Postmark, by outcome"))
+	errs = errors.Join(errs, err)
+
+	if errs != nil {
+		return nil, errs
+	}
+	return inst.intercept, nil
+}
+
+// intercept wraps a single Postmark call in a span and records its metrics
+func (i *instrumentation) intercept(ctx context.Context, op *postmark.Operation, result interface{}, next postmark.Invoker) error {
+	name := op.Name
+	if name == "" {
+		name = op.Method + " " + op.Path
+	}
+
+	sent := payloadMessages(op.Payload)
+	attrs := []attribute.KeyValue{OperationKey.String(name), httpMethodKey.String(op.Method)}
+	attrs = append(attrs, messageAttributes(sent)...)
+	if isBatch(op.Payload) {
+		attrs = append(attrs, BatchSizeKey.Int(len(sent)))
+	}
+
+	ctx, span := i.tracer.Start(ctx, "postmark."+name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...))
+	defer span.End()
+
+	start := time.Now()
+	err := next(ctx, op, result)
+	elapsed := time.Since(start)
+
+	span.SetAttributes(AttemptsKey.Int(op.Attempts))
+	if op.StatusCode != 0 {
+		span.SetAttributes(httpStatusCodeKey.Int(op.StatusCode))
+	}
+	if code := postmark.ErrorCode(err); code != 0 {
+		span.SetAttributes(ErrorCodeKey.Int64(code))
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	outcome := OutcomeSuccess
+	if err != nil {
+		outcome = OutcomeError
+	}
+	i.duration.Record(ctx, elapsed.Seconds(), metric.WithAttributes(OperationKey.String(name), OutcomeKey.String(outcome)))
+	if op.Attempts > 1 {
+		i.retries.Add(ctx, int64(op.Attempts-1), metric.WithAttributes(OperationKey.String(name)))
+	}
+	if isSend(name) {
+		i.recordMessages(ctx, name, sent, result, err)
+	}
+
+	return err
+}
+
+// recordMessages counts the outcome of every message of a send call
+func (i *instrumentation) recordMessages(ctx context.Context, name string, sent []message, result interface{}, err error) {
+	responses := make([]postmark.EmailResponse, len(sent))
+	switch res := result.(type) {
+	case *postmark.EmailResponse:
+		if len(responses) == 1 {
+			responses[0] = *res
+		}
+	case *[]postmark.EmailResponse:
+		if len(*res) == len(responses) {
+			copy(responses, *res)
+		}
+	}
+
+	for n, msg := range sent {
+		code := responses[n].ErrorCode
+		if err != nil {
+			code = postmark.ErrorCode(err)
+		}
+
+		attrs := []attribute.KeyValue{
+			OperationKey.String(name),
+			MessageStreamKey.String(msg.stream),
+			OutcomeKey.String(OutcomeSent),
+		}
+		if err != nil || code != 0 {
+			attrs[2] = OutcomeKey.String(OutcomeFailed)
+			attrs = append(attrs, ErrorCodeKey.Int64(code))
+		}
+		i.messages.Add(ctx, 1, metric.WithAttributes(attrs...))
+	}
+}
+
+// isSend reports whether the operation sends email
+func isSend(name string) bool {
+	switch name {
+	case "SendEmail", "SendEmailBatch", "SendTemplatedEmail", "SendTemplatedEmailBatch":
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,177 @@
+package postmarkotel_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/mrz1836/postmark"
+	"github.com/mrz1836/postmark/postmarkotel"
+	"github.com/mrz1836/postmark/postmarktest"
+)
+
+// newInstrumentedClient returns a client of a fake server, instrumented with in-memory providers
+func newInstrumentedClient(t *testing.T) (*postmark.Client, *tracetest.SpanRecorder, *sdkmetric.ManualReader) {
+	t.Helper()
+
+	srv := postmarktest.NewServer()
+	t.Cleanup(srv.Close)
+
+	spans := tracetest.NewSpanRecorder()
+	reader := sdkmetric.NewManualReader()
+
+	client := srv.Client()
+	require.NoError(t, postmarkotel.Instrument(client,
+		postmarkotel.WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans))),
+		postmarkotel.WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))),
+	))
+	return client, spans, reader
+}
+
+// collect returns the metrics recorded so far, by name
+func collect(t *testing.T, reader *sdkmetric.ManualReader) map[string]metricdata.Aggregation {
+	t.Helper()
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+
+	metrics := make(map[string]metricdata.Aggregation)
+	for _, scope := range rm.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			metrics[m.Name] = m.Data
+		}
+	}
+	return metrics
+}
+
+// sum returns the value of the counter data point with the given attributes
+func sum(t *testing.T, data metricdata.Aggregation, attrs ...attribute.KeyValue) int64 {
+	t.Helper()
+
+	want := attribute.NewSet(attrs...)
+	for _, point := range data.(metricdata.Sum[int64]).DataPoints {
+		if point.Attributes.Equals(&want) {
+			return point.Value
+		}
+	}
+	return 0
+}
+
+func TestSendEmail(t *testing.T) {
+	client, spans, reader := newInstrumentedClient(t)
+
+	_, err := client.SendEmail(context.Background(), postmark.Email{
+		From:     "sender@example.com",
+		To:       "receiver@example.com",
+		TextBody: "Hello",
+		Tag:      "welcome",
+	})
+	require.NoError(t, err)
+
+	ended := spans.Ended()
+	require.Len(t, ended, 1)
+	span := ended[0]
+	assert.Equal(t, "postmark.SendEmail", span.Name())
+	assert.Equal(t, trace.SpanKindClient, span.SpanKind())
+	assert.Equal(t, codes.Unset, span.Status().Code)
+
+	attrs := attribute.NewSet(span.Attributes()...)
+	value, _ := attrs.Value(postmarkotel.OperationKey)
+	assert.Equal(t, "SendEmail", value.AsString())
+	value, _ = attrs.Value(postmarkotel.MessageStreamKey)
+	assert.Equal(t, "outbound", value.AsString())
+	value, _ = attrs.Value(postmarkotel.TagKey)
+	assert.Equal(t, "welcome", value.AsString())
+	value, _ = attrs.Value(postmarkotel.AttemptsKey)
+	assert.Equal(t, int64(1), value.AsInt64())
+	value, _ = attrs.Value("http.response.status_code")
+	assert.Equal(t, int64(200), value.AsInt64())
+	assert.False(t, attrs.HasValue(postmarkotel.BatchSizeKey))
+
+	metrics := collect(t, reader)
+	assert.Equal(t, int64(1), sum(t, metrics["postmark.client.messages"],
+		postmarkotel.OperationKey.String("SendEmail"),
+		postmarkotel.MessageStreamKey.String("outbound"),
+		postmarkotel.OutcomeKey.String(postmarkotel.OutcomeSent)))
+
+	duration := metrics["postmark.client.duration"].(metricdata.Histogram[float64])
+	require.Len(t, duration.DataPoints, 1)
+	assert.Equal(t, uint64(1), duration.DataPoints[0].Count)
+}
+
+func TestSendEmailError(t *testing.T) {
+	client, spans, reader := newInstrumentedClient(t)
+
+	_, err := client.SendEmail(context.Background(), postmark.Email{From: "sender@example.com", TextBody: "Hello"})
+	require.ErrorIs(t, err, postmark.ErrInvalidEmailRequest)
+
+	ended := spans.Ended()
+	require.Len(t, ended, 1)
+	assert.Equal(t, codes.Error, ended[0].Status().Code)
+	require.Len(t, ended[0].Events(), 1)
+	assert.Equal(t, "exception", ended[0].Events()[0].Name)
+
+	attrs := attribute.NewSet(ended[0].Attributes()...)
+	value, _ := attrs.Value(postmarkotel.ErrorCodeKey)
+	assert.Equal(t, postmark.ErrorCodeInvalidEmailRequest, value.AsInt64())
+
+	metrics := collect(t, reader)
+	assert.Equal(t, int64(1), sum(t, metrics["postmark.client.messages"],
+		postmarkotel.OperationKey.String("SendEmail"),
+		postmarkotel.MessageStreamKey.String("outbound"),
+		postmarkotel.OutcomeKey.String(postmarkotel.OutcomeFailed),
+		postmarkotel.ErrorCodeKey.Int64(postmark.ErrorCodeInvalidEmailRequest)))
+}
+
+func TestSendEmailBatch(t *testing.T) {
+	client, spans, reader := newInstrumentedClient(t)
+
+	_, err := client.SendEmailBatch(context.Background(), []postmark.Email{
+		{From: "sender@example.com", To: "one@example.com", TextBody: "Hello"},
+		{From: "sender@example.com", To: "two@example.com", TextBody: "Hello"},
+		{From: "sender@example.com", TextBody: "No recipients"},
+	})
+	require.NoError(t, err)
+
+	ended := spans.Ended()
+	require.Len(t, ended, 1)
+	attrs := attribute.NewSet(ended[0].Attributes()...)
+	value, _ := attrs.Value(postmarkotel.BatchSizeKey)
+	assert.Equal(t, int64(3), value.AsInt64())
+
+	metrics := collect(t, reader)
+	messages := metrics["postmark.client.messages"]
+	assert.Equal(t, int64(2), sum(t, messages,
+		postmarkotel.OperationKey.String("SendEmailBatch"),
+		postmarkotel.MessageStreamKey.String("outbound"),
+		postmarkotel.OutcomeKey.String(postmarkotel.OutcomeSent)))
+	assert.Equal(t, int64(1), sum(t, messages,
+		postmarkotel.OperationKey.String("SendEmailBatch"),
+		postmarkotel.MessageStreamKey.String("outbound"),
+		postmarkotel.OutcomeKey.String(postmarkotel.OutcomeFailed),
+		postmarkotel.ErrorCodeKey.Int64(postmark.ErrorCodeInvalidEmailRequest)))
+}
+
+func TestOtherOperations(t *testing.T) {
+	client, spans, reader := newInstrumentedClient(t)
+
+	_, err := client.GetBouncedTags(context.Background())
+	require.NoError(t, err)
+
+	ended := spans.Ended()
+	require.Len(t, ended, 1)
+	assert.Equal(t, "postmark.GetBouncedTags", ended[0].Name())
+
+	metrics := collect(t, reader)
+	assert.NotContains(t, metrics, "postmark.client.messages")
+	assert.Contains(t, metrics, "postmark.client.duration")
+}
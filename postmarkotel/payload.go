@@ -0,0 +1,83 @@
+package postmarkotel
+
+import (
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/mrz1836/postmark"
+)
+
+// message holds the attributes of one email in a send payload
+type message struct {
+	stream string
+	tag    string
+}
+
+// newMessage returns the attributes of an email, defaulting the stream like Postmark does
+func newMessage(stream, tag string) message {
+	if stream == "" {
+		stream = defaultMessageStream
+	}
+	return message{stream: stream, tag: tag}
+}
+
+// payloadMessages returns the emails of a send payload, nil for other payloads
+func payloadMessages(payload interface{}) []message {
+	switch p := payload.(type) {
+	case postmark.Email:
+		return []message{newMessage(p.MessageStream, p.Tag)}
+	case postmark.TemplatedEmail:
+		return []message{newMessage(p.MessageStream, p.Tag)}
+	case []postmark.Email:
+		messages := make([]message, 0, len(p))
+		for _, email := range p {
+			messages = append(messages, newMessage(email.MessageStream, email.Tag))
+		}
+		return messages
+	case []postmark.TemplatedEmail:
+		messages := make([]message, 0, len(p))
+		for _, email := range p {
+			messages = append(messages, newMessage(email.MessageStream, email.Tag))
+		}
+		return messages
+	case map[string]interface{}:
+		// SendTemplatedEmailBatch wraps its emails in a Messages field
+		return payloadMessages(p["Messages"])
+	default:
+		return nil
+	}
+}
+
+// isBatch reports whether the payload is a batch of emails
+func isBatch(payload interface{}) bool {
+	switch p := payload.(type) {
+	case []postmark.Email, []postmark.TemplatedEmail:
+		return true
+	case map[string]interface{}:
+		return isBatch(p["Messages"])
+	default:
+		return false
+	}
+}
+
+// messageAttributes returns the message stream and tag shared by all the messages, if any
+func messageAttributes(messages []message) []attribute.KeyValue {
+	if len(messages) == 0 {
+		return nil
+	}
+
+	first := messages[0]
+	sameStream, sameTag := true, true
+	for _, msg := range messages[1:] {
+		sameStream = sameStream && msg.stream == first.stream
+		sameTag = sameTag && msg.tag == first.tag
+	}
+
+	var attrs []attribute.KeyValue
+	if sameStream {
+		attrs = append(attrs, MessageStreamKey.String(first.stream))
+	}
+	if sameTag && first.tag != "" {
+		attrs = append(attrs, TagKey.String(first.tag))
+	}
+	return attrs
+}
@@ -0,0 +1,209 @@
+package postmarkoutbox
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// File extensions of a FileStore: message files, and files that cannot be decoded, moved aside by Due
+const (
+	fileExtension    = ".json"
+	corruptExtension = ".corrupt"
+)
+
+// errCorrupt is returned when a message file cannot be decoded
+var errCorrupt = errors.New("postmarkoutbox: cannot decode")
+
+// FileStore keeps every message in its own JSON file in a directory, so the queue survives restarts.
+// Files are replaced atomically, a crash never leaves a message half written. A file that cannot be decoded is
+// renamed with a .corrupt extension by Due, so it does not block the other messages.
+type FileStore struct {
+	// Logger reports the files that could not be decoded (optional)
+	Logger *slog.Logger
+
+	dir string
+	mu  sync.Mutex
+}
+
+// NewFileStore returns a FileStore in dir, creating the directory if needed
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("postmarkoutbox: create store directory: %w", err)
+	}
+	return &FileStore{dir: dir}, nil
+}
+
+// Add stores a new message
+func (s *FileStore) Add(_ context.Context, msg Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := os.Stat(s.path(msg.Key)); err == nil {
+		return ErrDuplicate
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return s.write(msg)
+}
+
+// Get returns the message with the key
+func (s *FileStore) Get(_ context.Context, key string) (Message, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.read(s.path(key))
+}
+
+// Update replaces the stored message with the same key
+func (s *FileStore) Update(_ context.Context, msg Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := os.Stat(s.path(msg.Key)); errors.Is(err, fs.ErrNotExist) {
+		return ErrNotFound
+	} else if err != nil {
+		return err
+	}
+	return s.write(msg)
+}
+
+// Due returns up to limit pending messages due at now
+func (s *FileStore) Due(ctx context.Context, now time.Time, limit int) ([]Message, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var due []Message
+	for _, entry := range entries {
+		if err = ctx.Err(); err != nil {
+			return nil, err
+		}
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), fileExtension) {
+			continue
+		}
+
+		path := filepath.Join(s.dir, entry.Name())
+		msg, err := s.read(path)
+		if errors.Is(err, errCorrupt) {
+			s.quarantine(ctx, path, err)
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		if isDue(msg, now) {
+			due = append(due, msg)
+		}
+	}
+	return earliest(due, limit), nil
+}
+
+// Prune deletes the sent and failed messages completed before the time
+func (s *FileStore) Prune(ctx context.Context, before time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return 0, err
+	}
+
+	pruned := 0
+	for _, entry := range entries {
+		if err = ctx.Err(); err != nil {
+			return pruned, err
+		}
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), fileExtension) {
+			continue
+		}
+
+		path := filepath.Join(s.dir, entry.Name())
+		msg, err := s.read(path)
+		if errors.Is(err, errCorrupt) || errors.Is(err, ErrNotFound) {
+			continue
+		} else if err != nil {
+			return pruned, err
+		}
+		if isPrunable(msg, before) {
+			if err = os.Remove(path); err != nil {
+				return pruned, err
+			}
+			pruned++
+		}
+	}
+	return pruned, nil
+}
+
+// quarantine renames a file that cannot be decoded, so Due skips it, and logs it
+func (s *FileStore) quarantine(ctx context.Context, path string, err error) {
+	renameErr := os.Rename(path, path+corruptExtension)
+	if s.Logger != nil {
+		s.Logger.LogAttrs(ctx, slog.LevelError, "postmark outbox message file moved aside",
+			slog.String("file", filepath.Base(path)+corruptExtension),
+			slog.Any("error", errors.Join(err, renameErr)),
+		)
+	}
+}
+
+// path returns the file of a key, hashed so that any key is a valid file name
+func (s *FileStore) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(s.dir, hex.EncodeToString(sum[:])+fileExtension)
+}
+
+// read decodes a message file
+func (s *FileStore) read(path string) (Message, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // path is built from the store directory
+	if errors.Is(err, fs.ErrNotExist) {
+		return Message{}, ErrNotFound
+	} else if err != nil {
+		return Message{}, err
+	}
+
+	var msg Message
+	if err = json.Unmarshal(data, &msg); err != nil {
+		return Message{}, fmt.Errorf("%w %s: %w", errCorrupt, filepath.Base(path), err)
+	}
+	return msg, nil
+}
+
+// write atomically replaces the file of a message
+func (s *FileStore) write(msg Message) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(s.dir, "tmp-*")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err = tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err = tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path(msg.Key))
+}
@@ -0,0 +1,180 @@
+// Package postmarkoutbox queues emails for later delivery through Postmark.
+//
+// Emails are enqueued with a send-at time and an idempotency key and persisted through a Store, so they survive
+// restarts. A worker (Outbox.Run) drains the due messages with SendEmailBatch and SendTemplatedEmailBatch, records
+// the Postmark MessageID of every sent message and schedules transient failures for another attempt. Every message
+// carries its key in its Metadata, with a Finder a message sent just before a crash is found instead of sent again.
+// Sent and failed messages are deleted after the Retention period.
+//
+//	outbox := &postmarkoutbox.Outbox{Store: store, Sender: client}
+//	_, err := outbox.Enqueue(ctx, "welcome-42", email, time.Now().Add(time.Hour))
+//	go outbox.Run(ctx)
+//
+// A Store must only be drained by one worker at a time.
+package postmarkoutbox
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/mrz1836/postmark"
+)
+
+// Defaults used by Outbox
+const (
+	defaultMaxAttempts  = 5
+	defaultBaseBackoff  = time.Minute
+	defaultMaxBackoff   = time.Hour
+	defaultPollInterval = 10 * time.Second
+)
+
+var (
+	// ErrDuplicate is returned by Store.Add when a message with the same key is already stored
+	ErrDuplicate = errors.New("postmarkoutbox: duplicate message key")
+
+	// ErrNotFound is returned by a Store when no message has the key
+	ErrNotFound = errors.New("postmarkoutbox: message not found")
+
+	// errNoEmail is recorded for stored messages that have neither an Email nor a TemplatedEmail
+	errNoEmail = errors.New("postmarkoutbox: message has no email")
+)
+
+// Status is the delivery status of a queued message
+type Status string
+
+const (
+	// StatusPending means the message waits for its send-at time or for another attempt
+	StatusPending Status = "Pending"
+
+	// StatusSent means Postmark accepted the message
+	StatusSent Status = "Sent"
+
+	// StatusFailed means the message was rejected or ran out of attempts
+	StatusFailed Status = "Failed"
+)
+
+// Message is an email queued in the outbox, exactly one of Email and TemplatedEmail is set
+type Message struct {
+	// Key is the idempotency key of the message, unique within a Store
+	Key string
+	// Email is the email to send, unless TemplatedEmail is set
+	Email *postmark.Email `json:",omitempty"`
+	// TemplatedEmail is the templated email to send, unless Email is set
+	TemplatedEmail *postmark.TemplatedEmail `json:",omitempty"`
+	// Status is the delivery status of the message
+	Status Status
+	// SendAt is when the message is due, it moves forward when an attempt fails
+	SendAt time.Time
+	// CreatedAt is when the message was enqueued
+	CreatedAt time.Time
+	// Attempts is the number of times the message was sent to Postmark
+	Attempts int
+	// MessageID is the Postmark ID of the message once sent
+	MessageID string `json:",omitempty"`
+	// SentAt is when Postmark accepted the message
+	SentAt time.Time
+	// CompletedAt is when the message was sent or failed for good, Prune deletes messages by this time
+	CompletedAt time.Time
+	// ErrorCode is the Postmark error code of the last failed attempt
+	ErrorCode int64 `json:",omitempty"`
+	// LastError is the error of the last failed attempt
+	LastError string `json:",omitempty"`
+}
+
+// Sender sends batches of emails, *postmark.Client implements it
+type Sender interface {
+	SendEmailBatch(ctx context.Context, emails []postmark.Email) ([]postmark.EmailResponse, error)
+	SendTemplatedEmailBatch(ctx context.Context, emails []postmark.TemplatedEmail) ([]postmark.EmailResponse, error)
+}
+
+// Finder searches the outbound messages, *postmark.Client implements it
+type Finder interface {
+	GetOutboundMessages(ctx context.Context, count, offset int64, options map[string]interface{}) ([]postmark.OutboundMessage, int64, error)
+}
+
+// Outbox queues emails in a Store and sends them once they are due
+type Outbox struct {
+	// Store persists the queued messages
+	Store Store
+	// Sender sends the due messages, usually a *postmark.Client
+	Sender Sender
+	// Finder looks up messages attempted before by their key, so a message sent but not recorded before a crash is
+	// not sent again. Usually the same *postmark.Client as Sender (optional)
+	Finder Finder
+	// BatchSize is the largest number of messages sent in one request, postmark.MaxBatchMessages by default
+	BatchSize int
+	// MaxAttempts is the number of attempts before a transient failure is recorded as failed, five by default
+	MaxAttempts int
+	// BaseBackoff is the delay before the second attempt, it doubles with every attempt, one minute by default
+	BaseBackoff time.Duration
+	// MaxBackoff caps the delay between attempts, one hour by default
+	MaxBackoff time.Duration
+	// PollInterval is how often Run looks for due messages, ten seconds by default
+	PollInterval time.Duration
+	// Retention is how long sent and failed messages are kept after they completed, Run deletes older ones.
+	// Zero keeps them forever. A key is forgotten once deleted, enqueueing it again sends a new message.
+	Retention time.Duration
+	// Now returns the current time, it defaults to time.Now
+	Now func() time.Time
+	// Logger logs the outcome of every attempt (optional)
+	Logger *slog.Logger
+}
+
+// Enqueue validates and stores an email to send at sendAt, a zero sendAt sends it as soon as possible.
+// An empty key is replaced by a random one. Enqueueing a key that is already stored returns the stored message
+// without adding the email again.
+func (o *Outbox) Enqueue(ctx context.Context, key string, email postmark.Email, sendAt time.Time) (Message, error) {
+	if err := email.Validate(); err != nil {
+		return Message{}, err
+	}
+	return o.enqueue(ctx, Message{Key: key, Email: &email, SendAt: sendAt})
+}
+
+// EnqueueTemplated validates and stores a templated email to send at sendAt, see Enqueue
+func (o *Outbox) EnqueueTemplated(ctx context.Context, key string, email postmark.TemplatedEmail, sendAt time.Time) (Message, error) {
+	if err := email.Validate(); err != nil {
+		return Message{}, err
+	}
+	return o.enqueue(ctx, Message{Key: key, TemplatedEmail: &email, SendAt: sendAt})
+}
+
+// enqueue completes and stores a new message
+func (o *Outbox) enqueue(ctx context.Context, msg Message) (Message, error) {
+	if msg.Key == "" {
+		msg.Key = newKey()
+	}
+	msg.Status = StatusPending
+	msg.CreatedAt = o.now()
+	if msg.SendAt.IsZero() {
+		msg.SendAt = msg.CreatedAt
+	}
+
+	err := o.Store.Add(ctx, msg)
+	if errors.Is(err, ErrDuplicate) {
+		return o.Store.Get(ctx, msg.Key)
+	}
+	if err != nil {
+		return Message{}, fmt.Errorf("postmarkoutbox: enqueue %q: %w", msg.Key, err)
+	}
+	return msg, nil
+}
+
+// now returns the current time
+func (o *Outbox) now() time.Time {
+	if o.Now == nil {
+		return time.Now()
+	}
+	return o.Now()
+}
+
+// newKey returns a random idempotency key
+func newKey() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,286 @@
+package postmarkoutbox_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/postmark"
+	"github.com/mrz1836/postmark/postmarkoutbox"
+	"github.com/mrz1836/postmark/postmarktest"
+)
+
+// clock is a settable time source
+type clock struct{ now time.Time }
+
+func (c *clock) Now() time.Time { return c.now }
+
+func newClock() *clock {
+	return &clock{now: time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)}
+}
+
+func TestOutboxSend(t *testing.T) {
+	ctx := context.Background()
+	pm := postmarktest.NewServer()
+	t.Cleanup(pm.Close)
+	_, err := pm.Client().CreateTemplate(ctx, postmark.Template{
+		Name: "Welcome", Alias: "welcome", Subject: "Hi {{name}}", TextBody: "Welcome {{name}}",
+	})
+	require.NoError(t, err)
+
+	now := newClock()
+	outbox := &postmarkoutbox.Outbox{Store: postmarkoutbox.NewMemoryStore(), Sender: pm.Client(), Now: now.Now}
+
+	email := postmark.Email{From: "app@example.com", To: "jane@example.com", TextBody: "Hi"}
+	msg, err := outbox.Enqueue(ctx, "reminder-1", email, now.now.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, postmarkoutbox.StatusPending, msg.Status)
+
+	again, err := outbox.Enqueue(ctx, "reminder-1", postmark.Email{From: "app@example.com", To: "john@example.com", TextBody: "Hi"}, time.Time{})
+	require.NoError(t, err)
+	assert.Equal(t, "jane@example.com", again.Email.To)
+
+	_, err = outbox.EnqueueTemplated(ctx, "welcome-1", postmark.TemplatedEmail{
+		TemplateAlias: "welcome", TemplateModel: map[string]interface{}{"name": "John"},
+		From: "app@example.com", To: "john@example.com",
+	}, time.Time{})
+	require.NoError(t, err)
+
+	_, err = outbox.Enqueue(ctx, "invalid", postmark.Email{To: "jane@example.com"}, time.Time{})
+	var validationErr postmark.EmailValidationError
+	require.ErrorAs(t, err, &validationErr)
+
+	attempted, err := outbox.Process(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, attempted)
+	require.Len(t, pm.Messages(), 1)
+	assert.Equal(t, "welcome", pm.Messages()[0].TemplateAlias)
+
+	welcome, err := outbox.Store.Get(ctx, "welcome-1")
+	require.NoError(t, err)
+	assert.Equal(t, postmarkoutbox.StatusSent, welcome.Status)
+	assert.Equal(t, pm.Messages()[0].MessageID, welcome.MessageID)
+
+	now.now = now.now.Add(time.Hour)
+	attempted, err = outbox.Process(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, attempted)
+	require.Len(t, pm.Messages(), 2)
+
+	reminder, err := outbox.Store.Get(ctx, "reminder-1")
+	require.NoError(t, err)
+	assert.Equal(t, postmarkoutbox.StatusSent, reminder.Status)
+	assert.Equal(t, pm.Messages()[1].MessageID, reminder.MessageID)
+	assert.Equal(t, 1, reminder.Attempts)
+	assert.Equal(t, "reminder-1", pm.Messages()[1].Email.Metadata[postmark.IdempotencyKeyMetadata])
+
+	outbox.Retention = time.Hour
+	now.now = now.now.Add(time.Minute)
+	pruned, err := outbox.Prune(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, pruned)
+	_, err = outbox.Store.Get(ctx, "welcome-1")
+	require.ErrorIs(t, err, postmarkoutbox.ErrNotFound)
+	_, err = outbox.Store.Get(ctx, "reminder-1")
+	require.NoError(t, err)
+}
+
+// crashingStore fails the first Update of a sent message, as if the process stopped between sending it and recording it
+type crashingStore struct {
+	*postmarkoutbox.MemoryStore
+	crashed bool
+}
+
+func (s *crashingStore) Update(ctx context.Context, msg postmarkoutbox.Message) error {
+	if msg.Status == postmarkoutbox.StatusSent && !s.crashed {
+		s.crashed = true
+		return errors.New("crash")
+	}
+	return s.MemoryStore.Update(ctx, msg)
+}
+
+// serverFinder searches the messages received by a test server by their idempotency key
+type serverFinder struct {
+	pm      *postmarktest.Server
+	lookups int
+}
+
+func (f *serverFinder) GetOutboundMessages(_ context.Context, _, _ int64, options map[string]interface{}) ([]postmark.OutboundMessage, int64, error) {
+	f.lookups++
+	var found []postmark.OutboundMessage
+	for _, msg := range f.pm.Messages() {
+		if options["metadata_"+postmark.IdempotencyKeyMetadata] == msg.Email.Metadata[postmark.IdempotencyKeyMetadata] {
+			found = append(found, postmark.OutboundMessage{MessageID: msg.MessageID, Recipients: []string{msg.Email.To}})
+		}
+	}
+	return found, int64(len(found)), nil
+}
+
+func TestOutboxDoesNotResendAfterCrash(t *testing.T) {
+	ctx := context.Background()
+	pm := postmarktest.NewServer()
+	t.Cleanup(pm.Close)
+	finder := &serverFinder{pm: pm}
+	outbox := &postmarkoutbox.Outbox{
+		Store:  &crashingStore{MemoryStore: postmarkoutbox.NewMemoryStore()},
+		Sender: pm.Client(),
+		Finder: finder,
+	}
+
+	_, err := outbox.Enqueue(ctx, "invoice-7", postmark.Email{From: "app@example.com", To: "jane@example.com", TextBody: "Hi"}, time.Time{})
+	require.NoError(t, err)
+
+	_, err = outbox.Process(ctx)
+	require.Error(t, err)
+	msg, err := outbox.Store.Get(ctx, "invoice-7")
+	require.NoError(t, err)
+	assert.Equal(t, postmarkoutbox.StatusPending, msg.Status)
+	assert.Equal(t, 1, msg.Attempts)
+	assert.Zero(t, finder.lookups)
+
+	_, err = outbox.Process(ctx)
+	require.NoError(t, err)
+	require.Len(t, pm.Messages(), 1)
+	assert.Equal(t, 1, finder.lookups)
+	msg, err = outbox.Store.Get(ctx, "invoice-7")
+	require.NoError(t, err)
+	assert.Equal(t, postmarkoutbox.StatusSent, msg.Status)
+	assert.Equal(t, pm.Messages()[0].MessageID, msg.MessageID)
+}
+
+// flakySender fails every batch with errs until they run out, then accepts them. gone@example.com is always inactive.
+type flakySender struct {
+	errs  []error
+	calls int
+	keys  []string
+}
+
+func (s *flakySender) SendEmailBatch(_ context.Context, emails []postmark.Email) ([]postmark.EmailResponse, error) {
+	s.calls++
+	for _, email := range emails {
+		s.keys = append(s.keys, email.Metadata[postmark.IdempotencyKeyMetadata])
+	}
+	if len(s.errs) > 0 {
+		err := s.errs[0]
+		s.errs = s.errs[1:]
+		return nil, err
+	}
+
+	res := make([]postmark.EmailResponse, len(emails))
+	for n, email := range emails {
+		res[n] = postmark.EmailResponse{To: email.To, MessageID: email.To + "-id"}
+		if email.To == "gone@example.com" {
+			res[n] = postmark.EmailResponse{To: email.To, ErrorCode: postmark.ErrorCodeInactiveRecipient, Message: "inactive"}
+		}
+	}
+	return res, nil
+}
+
+func (s *flakySender) SendTemplatedEmailBatch(context.Context, []postmark.TemplatedEmail) ([]postmark.EmailResponse, error) {
+	return nil, errors.New("not implemented")
+}
+
+func TestOutboxRetries(t *testing.T) {
+	ctx := context.Background()
+	now := newClock()
+	sender := &flakySender{errs: []error{
+		&net.OpError{Op: "read", Net: "tcp", Err: errors.New("connection reset")},
+		postmark.APIError{ErrorCode: postmark.ErrorCodeMaintenance, Message: "maintenance"},
+	}}
+	outbox := &postmarkoutbox.Outbox{
+		Store:       postmarkoutbox.NewMemoryStore(),
+		Sender:      sender,
+		BaseBackoff: time.Minute,
+		Now:         now.Now,
+	}
+
+	for _, to := range []string{"jane@example.com", "gone@example.com"} {
+		_, err := outbox.Enqueue(ctx, to, postmark.Email{From: "app@example.com", To: to, TextBody: "Hi"}, time.Time{})
+		require.NoError(t, err)
+	}
+
+	_, err := outbox.Process(ctx)
+	require.NoError(t, err)
+	jane, err := outbox.Store.Get(ctx, "jane@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, postmarkoutbox.StatusPending, jane.Status)
+	assert.Equal(t, "read tcp: connection reset", jane.LastError)
+	assert.Equal(t, now.now.Add(time.Minute), jane.SendAt)
+
+	// Not due yet
+	attempted, err := outbox.Process(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, attempted)
+
+	now.now = now.now.Add(time.Minute)
+	_, err = outbox.Process(ctx)
+	require.NoError(t, err)
+	jane, err = outbox.Store.Get(ctx, "jane@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, postmark.ErrorCodeMaintenance, jane.ErrorCode)
+	assert.Equal(t, now.now.Add(2*time.Minute), jane.SendAt)
+
+	now.now = now.now.Add(2 * time.Minute)
+	_, err = outbox.Process(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 3, sender.calls)
+	assert.ElementsMatch(t, []string{
+		"jane@example.com", "gone@example.com", "jane@example.com", "gone@example.com", "jane@example.com", "gone@example.com",
+	}, sender.keys)
+
+	jane, err = outbox.Store.Get(ctx, "jane@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, postmarkoutbox.StatusSent, jane.Status)
+	assert.Equal(t, "jane@example.com-id", jane.MessageID)
+	assert.Equal(t, 3, jane.Attempts)
+	assert.Empty(t, jane.LastError)
+
+	gone, err := outbox.Store.Get(ctx, "gone@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, postmarkoutbox.StatusFailed, gone.Status)
+	assert.Equal(t, postmark.ErrorCodeInactiveRecipient, gone.ErrorCode)
+}
+
+func TestOutboxMaxAttempts(t *testing.T) {
+	ctx := context.Background()
+	now := newClock()
+	timeout := &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("timeout")}
+	sender := &flakySender{errs: []error{timeout, timeout}}
+	outbox := &postmarkoutbox.Outbox{Store: postmarkoutbox.NewMemoryStore(), Sender: sender, MaxAttempts: 2, Now: now.Now}
+
+	_, err := outbox.Enqueue(ctx, "", postmark.Email{From: "app@example.com", To: "jane@example.com", TextBody: "Hi"}, time.Time{})
+	require.NoError(t, err)
+
+	for range 2 {
+		_, err = outbox.Process(ctx)
+		require.NoError(t, err)
+		now.now = now.now.Add(time.Hour)
+	}
+
+	due, err := outbox.Store.Due(ctx, now.now, 0)
+	require.NoError(t, err)
+	assert.Empty(t, due)
+	assert.Equal(t, 2, sender.calls)
+}
+
+func TestOutboxRun(t *testing.T) {
+	pm := postmarktest.NewServer()
+	t.Cleanup(pm.Close)
+	outbox := &postmarkoutbox.Outbox{Store: postmarkoutbox.NewMemoryStore(), Sender: pm.Client(), PollInterval: time.Millisecond}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- outbox.Run(ctx) }()
+
+	_, err := outbox.Enqueue(ctx, "", postmark.Email{From: "app@example.com", To: "jane@example.com", TextBody: "Hi"}, time.Time{})
+	require.NoError(t, err)
+	require.Eventually(t, func() bool { return len(pm.Messages()) == 1 }, time.Second, time.Millisecond)
+
+	cancel()
+	require.ErrorIs(t, <-done, context.Canceled)
+}
@@ -0,0 +1,123 @@
+package postmarkoutbox
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Store persists the messages of an Outbox
+type Store interface {
+	// Add stores a new message, it returns ErrDuplicate when its key is already stored
+	Add(ctx context.Context, msg Message) error
+	// Get returns the message with the key, or ErrNotFound
+	Get(ctx context.Context, key string) (Message, error)
+	// Update replaces the stored message with the same key, or returns ErrNotFound
+	Update(ctx context.Context, msg Message) error
+	// Due returns up to limit pending messages whose SendAt is not after now, the earliest first
+	Due(ctx context.Context, now time.Time, limit int) ([]Message, error)
+	// Prune deletes the sent and failed messages completed before the time and returns how many were deleted
+	Prune(ctx context.Context, before time.Time) (int, error)
+}
+
+// MemoryStore keeps messages in memory, it does not survive restarts and suits tests and short-lived processes
+type MemoryStore struct {
+	mu       sync.Mutex
+	messages map[string]Message
+}
+
+// NewMemoryStore returns an empty MemoryStore
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{messages: make(map[string]Message)}
+}
+
+// Add stores a new message
+func (s *MemoryStore) Add(_ context.Context, msg Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.messages[msg.Key]; ok {
+		return ErrDuplicate
+	}
+	s.messages[msg.Key] = msg
+	return nil
+}
+
+// Get returns the message with the key
+func (s *MemoryStore) Get(_ context.Context, key string) (Message, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	msg, ok := s.messages[key]
+	if !ok {
+		return Message{}, ErrNotFound
+	}
+	return msg, nil
+}
+
+// Update replaces the stored message with the same key
+func (s *MemoryStore) Update(_ context.Context, msg Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.messages[msg.Key]; !ok {
+		return ErrNotFound
+	}
+	s.messages[msg.Key] = msg
+	return nil
+}
+
+// Due returns up to limit pending messages due at now
+func (s *MemoryStore) Due(_ context.Context, now time.Time, limit int) ([]Message, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var due []Message
+	for _, msg := range s.messages {
+		if isDue(msg, now) {
+			due = append(due, msg)
+		}
+	}
+	return earliest(due, limit), nil
+}
+
+// Prune deletes the sent and failed messages completed before the time
+func (s *MemoryStore) Prune(_ context.Context, before time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pruned := 0
+	for key, msg := range s.messages {
+		if isPrunable(msg, before) {
+			delete(s.messages, key)
+			pruned++
+		}
+	}
+	return pruned, nil
+}
+
+// isDue reports whether a message should be sent at now
+func isDue(msg Message, now time.Time) bool {
+	return msg.Status == StatusPending && !msg.SendAt.After(now)
+}
+
+// isPrunable reports whether a message completed before the time
+func isPrunable(msg Message, before time.Time) bool {
+	return msg.Status != StatusPending && msg.CompletedAt.Before(before)
+}
+
+// earliest sorts the messages by SendAt, then key, and keeps the first limit ones
+func earliest(messages []Message, limit int) []Message {
+	slices.SortFunc(messages, func(a, b Message) int {
+		if c := a.SendAt.Compare(b.SendAt); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Key, b.Key)
+	})
+	if limit > 0 && len(messages) > limit {
+		messages = messages[:limit]
+	}
+	return messages
+}
@@ -0,0 +1,124 @@
+package postmarkoutbox_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/postmark"
+	"github.com/mrz1836/postmark/postmarkoutbox"
+)
+
+func TestStores(t *testing.T) {
+	stores := map[string]func(t *testing.T) postmarkoutbox.Store{
+		"memory": func(*testing.T) postmarkoutbox.Store { return postmarkoutbox.NewMemoryStore() },
+		"file": func(t *testing.T) postmarkoutbox.Store {
+			store, err := postmarkoutbox.NewFileStore(t.TempDir())
+			require.NoError(t, err)
+			return store
+		},
+	}
+
+	for name, newStore := range stores {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			store := newStore(t)
+			now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+			email := &postmark.Email{From: "app@example.com", To: "jane@example.com", TextBody: "Hi"}
+
+			later := postmarkoutbox.Message{Key: "later", Email: email, Status: postmarkoutbox.StatusPending, SendAt: now.Add(time.Hour)}
+			first := postmarkoutbox.Message{Key: "a/../first", Email: email, Status: postmarkoutbox.StatusPending, SendAt: now.Add(-time.Hour)}
+			second := postmarkoutbox.Message{Key: "second", Email: email, Status: postmarkoutbox.StatusPending, SendAt: now}
+			for _, msg := range []postmarkoutbox.Message{later, second, first} {
+				require.NoError(t, store.Add(ctx, msg))
+			}
+			require.ErrorIs(t, store.Add(ctx, first), postmarkoutbox.ErrDuplicate)
+
+			due, err := store.Due(ctx, now, 0)
+			require.NoError(t, err)
+			require.Len(t, due, 2)
+			assert.Equal(t, "a/../first", due[0].Key)
+			assert.Equal(t, "second", due[1].Key)
+			assert.Equal(t, email, due[0].Email)
+
+			due, err = store.Due(ctx, now, 1)
+			require.NoError(t, err)
+			assert.Len(t, due, 1)
+
+			first.Status = postmarkoutbox.StatusSent
+			first.MessageID = "message-1"
+			first.CompletedAt = now.Add(-time.Minute)
+			require.NoError(t, store.Update(ctx, first))
+			got, err := store.Get(ctx, first.Key)
+			require.NoError(t, err)
+			assert.Equal(t, "message-1", got.MessageID)
+
+			due, err = store.Due(ctx, now.Add(2*time.Hour), 0)
+			require.NoError(t, err)
+			require.Len(t, due, 2)
+			assert.Equal(t, "second", due[0].Key)
+			assert.Equal(t, "later", due[1].Key)
+
+			_, err = store.Get(ctx, "missing")
+			require.ErrorIs(t, err, postmarkoutbox.ErrNotFound)
+			require.ErrorIs(t, store.Update(ctx, postmarkoutbox.Message{Key: "missing"}), postmarkoutbox.ErrNotFound)
+
+			// Only sent and failed messages completed before the time are pruned
+			failed := postmarkoutbox.Message{Key: "failed", Email: email, Status: postmarkoutbox.StatusFailed, CompletedAt: now}
+			require.NoError(t, store.Add(ctx, failed))
+			pruned, err := store.Prune(ctx, now)
+			require.NoError(t, err)
+			assert.Equal(t, 1, pruned)
+			_, err = store.Get(ctx, first.Key)
+			require.ErrorIs(t, err, postmarkoutbox.ErrNotFound)
+			pruned, err = store.Prune(ctx, now.Add(time.Second))
+			require.NoError(t, err)
+			assert.Equal(t, 1, pruned)
+			due, err = store.Due(ctx, now.Add(2*time.Hour), 0)
+			require.NoError(t, err)
+			assert.Len(t, due, 2)
+		})
+	}
+}
+
+func TestFileStoreSurvivesRestart(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	store, err := postmarkoutbox.NewFileStore(dir)
+	require.NoError(t, err)
+	require.NoError(t, store.Add(ctx, postmarkoutbox.Message{Key: "welcome", Status: postmarkoutbox.StatusPending}))
+
+	reopened, err := postmarkoutbox.NewFileStore(dir)
+	require.NoError(t, err)
+	msg, err := reopened.Get(ctx, "welcome")
+	require.NoError(t, err)
+	assert.Equal(t, postmarkoutbox.StatusPending, msg.Status)
+}
+
+func TestFileStoreMovesCorruptFilesAside(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	store, err := postmarkoutbox.NewFileStore(dir)
+	require.NoError(t, err)
+	var logs bytes.Buffer
+	store.Logger = slog.New(slog.NewTextHandler(&logs, nil))
+
+	require.NoError(t, store.Add(ctx, postmarkoutbox.Message{Key: "welcome", Status: postmarkoutbox.StatusPending}))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "broken.json"), []byte("{"), 0o600))
+
+	due, err := store.Due(ctx, time.Now(), 0)
+	require.NoError(t, err)
+	require.Len(t, due, 1)
+	assert.Equal(t, "welcome", due[0].Key)
+	assert.Contains(t, logs.String(), "broken.json.corrupt")
+	assert.FileExists(t, filepath.Join(dir, "broken.json.corrupt"))
+	assert.NoFileExists(t, filepath.Join(dir, "broken.json"))
+}
@@ -0,0 +1,246 @@
+package postmarkoutbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/mrz1836/postmark"
+)
+
+// Run sends the due messages every PollInterval until the context is done, it returns the context error.
+// Messages older than Retention are pruned after every poll. Store errors are logged and retried at the next poll.
+func (o *Outbox) Run(ctx context.Context) error {
+	for {
+		if _, err := o.Process(ctx); err != nil && ctx.Err() == nil && o.Logger != nil {
+			o.Logger.LogAttrs(ctx, slog.LevelError, "postmark outbox processing failed", slog.Any("error", err))
+		}
+		if _, err := o.Prune(ctx); err != nil && ctx.Err() == nil && o.Logger != nil {
+			o.Logger.LogAttrs(ctx, slog.LevelError, "postmark outbox pruning failed", slog.Any("error", err))
+		}
+
+		timer := time.NewTimer(o.pollInterval())
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// Process sends every message due now, in batches, and returns how many messages were attempted
+func (o *Outbox) Process(ctx context.Context) (int, error) {
+	attempted := 0
+	for {
+		due, err := o.Store.Due(ctx, o.now(), o.batchSize())
+		if err != nil {
+			return attempted, err
+		}
+		if len(due) == 0 {
+			return attempted, nil
+		}
+
+		if err = o.send(ctx, due); err != nil {
+			return attempted, err
+		}
+		attempted += len(due)
+
+		// Messages that were not sent are rescheduled, stop if the context ended during the request
+		if err = ctx.Err(); err != nil {
+			return attempted, err
+		}
+	}
+}
+
+// Prune deletes the sent and failed messages completed more than Retention ago and returns how many were deleted,
+// it does nothing when Retention is zero
+func (o *Outbox) Prune(ctx context.Context) (int, error) {
+	if o.Retention <= 0 {
+		return 0, nil
+	}
+	return o.Store.Prune(ctx, o.now().Add(-o.Retention))
+}
+
+// send sends due messages, emails and templated emails in separate batches, and records the outcome of each one.
+// Every message carries its key in its Metadata, and a message attempted before is first looked up with the Finder
+// so that a message sent just before a crash is recorded instead of sent again.
+func (o *Outbox) send(ctx context.Context, due []Message) error {
+	var (
+		emails        []postmark.Email
+		emailMsgs     []Message
+		templated     []postmark.TemplatedEmail
+		templatedMsgs []Message
+		errs          []error
+	)
+
+	// The outcome is recorded even when the context ends during the request
+	recordCtx := context.WithoutCancel(ctx)
+	for _, msg := range due {
+		if msg.Email == nil && msg.TemplatedEmail == nil {
+			errs = append(errs, o.record(recordCtx, msg, postmark.EmailResponse{}, errNoEmail, false))
+			continue
+		}
+
+		if msg.Attempts > 0 && o.Finder != nil {
+			res, found, err := o.find(ctx, msg)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("looking up message %s: %w", msg.Key, err))
+				continue
+			} else if found {
+				errs = append(errs, o.record(recordCtx, msg, res, nil, false))
+				continue
+			}
+		}
+
+		// The attempt is stored before sending, so a crash during the request is known at the next attempt
+		msg.Attempts++
+		if err := o.Store.Update(ctx, msg); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		if msg.Email != nil {
+			email := *msg.Email
+			email.Metadata = withKey(email.Metadata, msg.Key)
+			emails = append(emails, email)
+			emailMsgs = append(emailMsgs, msg)
+		} else {
+			email := *msg.TemplatedEmail
+			email.Metadata = withKey(email.Metadata, msg.Key)
+			templated = append(templated, email)
+			templatedMsgs = append(templatedMsgs, msg)
+		}
+	}
+
+	if len(emails) > 0 {
+		responses, err := o.Sender.SendEmailBatch(ctx, emails)
+		for _, item := range postmark.NewBatchResult(emails, responses, err).Items {
+			errs = append(errs, o.record(recordCtx, emailMsgs[item.Index], item.Response, item.Err, item.Retryable()))
+		}
+	}
+	if len(templated) > 0 {
+		responses, err := o.Sender.SendTemplatedEmailBatch(ctx, templated)
+		for _, item := range postmark.NewBatchResult(templated, responses, err).Items {
+			errs = append(errs, o.record(recordCtx, templatedMsgs[item.Index], item.Response, item.Err, item.Retryable()))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// find looks up a message sent with the key of msg in the outbound messages
+func (o *Outbox) find(ctx context.Context, msg Message) (postmark.EmailResponse, bool, error) {
+	stream := ""
+	if msg.Email != nil {
+		stream = msg.Email.MessageStream
+	} else {
+		stream = msg.TemplatedEmail.MessageStream
+	}
+
+	filter := postmark.OutboundMessageFilter{
+		MessageStream: stream,
+		Metadata:      map[string]string{postmark.IdempotencyKeyMetadata: msg.Key},
+	}
+	messages, _, err := o.Finder.GetOutboundMessages(ctx, 1, 0, filter.Options())
+	if err != nil || len(messages) == 0 {
+		return postmark.EmailResponse{}, false, err
+	}
+	return postmark.EmailResponse{
+		To:          strings.Join(messages[0].Recipients, ", "),
+		SubmittedAt: messages[0].ReceivedAt,
+		MessageID:   messages[0].MessageID,
+		Message:     "OK",
+	}, true, nil
+}
+
+// withKey returns a copy of the metadata with the idempotency key of a message
+func withKey(metadata map[string]string, key string) map[string]string {
+	metadata = maps.Clone(metadata)
+	if metadata == nil {
+		metadata = make(map[string]string, 1)
+	}
+	metadata[postmark.IdempotencyKeyMetadata] = key
+	return metadata
+}
+
+// record stores the outcome of an attempt: sent, rescheduled after a transient failure, or failed
+func (o *Outbox) record(ctx context.Context, msg Message, res postmark.EmailResponse, err error, retryable bool) error {
+	now := o.now()
+
+	switch {
+	case err == nil:
+		msg.Status = StatusSent
+		msg.MessageID = res.MessageID
+		msg.SentAt = res.SubmittedAt
+		if msg.SentAt.IsZero() {
+			msg.SentAt = now
+		}
+		msg.ErrorCode, msg.LastError = 0, ""
+		msg.CompletedAt = now
+	case retryable && msg.Attempts < o.maxAttempts():
+		msg.SendAt = now.Add(o.backoff(msg.Attempts))
+		msg.ErrorCode, msg.LastError = res.ErrorCode, err.Error()
+	default:
+		msg.Status = StatusFailed
+		msg.ErrorCode, msg.LastError = res.ErrorCode, err.Error()
+		msg.CompletedAt = now
+	}
+
+	if o.Logger != nil {
+		o.Logger.LogAttrs(ctx, slog.LevelInfo, "postmark outbox attempt",
+			slog.String("key", msg.Key),
+			slog.String("status", string(msg.Status)),
+			slog.Int("attempts", msg.Attempts),
+			slog.String("message_id", msg.MessageID),
+			slog.Int64("error_code", msg.ErrorCode),
+			slog.String("error", msg.LastError),
+		)
+	}
+	return o.Store.Update(ctx, msg)
+}
+
+// backoff returns the delay after the given attempt (1 is the first attempt)
+func (o *Outbox) backoff(attempt int) time.Duration {
+	base, maxBackoff := o.BaseBackoff, o.MaxBackoff
+	if base <= 0 {
+		base = defaultBaseBackoff
+	}
+	if maxBackoff <= 0 {
+		maxBackoff = defaultMaxBackoff
+	}
+
+	delay := float64(base) * math.Pow(2, float64(attempt-1))
+	if delay > float64(maxBackoff) {
+		return maxBackoff
+	}
+	return time.Duration(delay)
+}
+
+// batchSize returns the largest number of messages sent in one request
+func (o *Outbox) batchSize() int {
+	if o.BatchSize <= 0 || o.BatchSize > postmark.MaxBatchMessages {
+		return postmark.MaxBatchMessages
+	}
+	return o.BatchSize
+}
+
+// maxAttempts returns the number of attempts of a message
+func (o *Outbox) maxAttempts() int {
+	if o.MaxAttempts <= 0 {
+		return defaultMaxAttempts
+	}
+	return o.MaxAttempts
+}
+
+// pollInterval returns how often Run looks for due messages
+func (o *Outbox) pollInterval() time.Duration {
+	if o.PollInterval <= 0 {
+		return defaultPollInterval
+	}
+	return o.PollInterval
+}
@@ -0,0 +1,207 @@
+// Package postmarksmtp relays mail received over SMTP to Postmark.
+//
+// It lets applications that can only speak SMTP send through the Postmark API: every message is parsed into a
+// postmark.Email, with its attachments and headers, and sent with SendEmail. The SMTP reply to DATA reflects the
+// result of the API call, so permanent Postmark errors are bounced and temporary ones are retried by the sender.
+//
+// The server has no authentication or TLS, it is meant to listen on a local or private address.
+package postmarksmtp
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/mrz1836/postmark"
+)
+
+// Defaults used by Server
+const (
+	defaultAddr            = "127.0.0.1:2525"
+	defaultHostname        = "localhost"
+	defaultMaxMessageBytes = 2 * postmark.MaxMessageSize // attachments grow by a third when base64 encoded
+	defaultReadTimeout     = 5 * time.Minute
+	defaultSendTimeout     = time.Minute
+)
+
+// ErrServerClosed is returned by Serve and ListenAndServe after Close
+var ErrServerClosed = errors.New("postmarksmtp: server closed")
+
+// Sender sends an email, *postmark.Client implements it
+type Sender interface {
+	SendEmail(ctx context.Context, email postmark.Email) (postmark.EmailResponse, error)
+}
+
+// Server is an SMTP server that forwards every message it receives to Postmark
+type Server struct {
+	// Addr is the TCP address to listen on, "127.0.0.1:2525" by default
+	Addr string
+	// Sender sends the received messages, usually a *postmark.Client
+	Sender Sender
+	// Hostname is announced in the greeting, "localhost" by default
+	Hostname string
+	// MaxMessageBytes is the largest raw message accepted, twice postmark.MaxMessageSize by default
+	MaxMessageBytes int64
+	// ReadTimeout is how long to wait for each command or message, five minutes by default
+	ReadTimeout time.Duration
+	// SendTimeout is how long to wait for Postmark for each message, one minute by default
+	SendTimeout time.Duration
+	// Logger logs every relayed message (optional)
+	Logger *slog.Logger
+
+	mu        sync.Mutex
+	listeners map[net.Listener]struct{}
+	conns     map[net.Conn]struct{}
+	closed    bool
+	wg        sync.WaitGroup
+}
+
+// ListenAndServe listens on Addr and serves SMTP connections until Close
+func (s *Server) ListenAndServe() error {
+	addr := s.Addr
+	if addr == "" {
+		addr = defaultAddr
+	}
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(listener)
+}
+
+// Serve accepts SMTP connections on the listener until Close, it always returns a non-nil error
+func (s *Server) Serve(listener net.Listener) error {
+	if !s.track(listener) {
+		_ = listener.Close()
+		return ErrServerClosed
+	}
+	defer s.untrack(listener)
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if s.isClosed() {
+				return ErrServerClosed
+			}
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				continue
+			}
+			return err
+		}
+
+		if !s.trackConn(conn) {
+			_ = conn.Close()
+			return ErrServerClosed
+		}
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			defer s.untrackConn(conn)
+			newSession(s, conn).serve()
+		}()
+	}
+}
+
+// Close stops the listeners, closes the open connections and waits for their sessions to end
+func (s *Server) Close() error {
+	s.mu.Lock()
+	s.closed = true
+	var errs []error
+	for listener := range s.listeners {
+		errs = append(errs, listener.Close())
+	}
+	for conn := range s.conns {
+		errs = append(errs, conn.Close())
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+	return errors.Join(errs...)
+}
+
+// track registers a listener, it returns false once the server is closed
+func (s *Server) track(listener net.Listener) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return false
+	}
+	if s.listeners == nil {
+		s.listeners = make(map[net.Listener]struct{})
+	}
+	s.listeners[listener] = struct{}{}
+	return true
+}
+
+// untrack removes a listener
+func (s *Server) untrack(listener net.Listener) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.listeners, listener)
+}
+
+// trackConn registers a connection, it returns false once the server is closed
+func (s *Server) trackConn(conn net.Conn) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return false
+	}
+	if s.conns == nil {
+		s.conns = make(map[net.Conn]struct{})
+	}
+	s.conns[conn] = struct{}{}
+	return true
+}
+
+// untrackConn closes and removes a connection
+func (s *Server) untrackConn(conn net.Conn) {
+	_ = conn.Close()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.conns, conn)
+}
+
+// isClosed reports whether Close was called
+func (s *Server) isClosed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.closed
+}
+
+// hostname returns the announced hostname
+func (s *Server) hostname() string {
+	if s.Hostname == "" {
+		return defaultHostname
+	}
+	return s.Hostname
+}
+
+// maxMessageBytes returns the largest raw message accepted
+func (s *Server) maxMessageBytes() int64 {
+	if s.MaxMessageBytes <= 0 {
+		return defaultMaxMessageBytes
+	}
+	return s.MaxMessageBytes
+}
+
+// readTimeout returns how long to wait for each command or message
+func (s *Server) readTimeout() time.Duration {
+	if s.ReadTimeout <= 0 {
+		return defaultReadTimeout
+	}
+	return s.ReadTimeout
+}
+
+// sendTimeout returns how long to wait for Postmark
+func (s *Server) sendTimeout() time.Duration {
+	if s.SendTimeout <= 0 {
+		return defaultSendTimeout
+	}
+	return s.SendTimeout
+}
@@ -0,0 +1,215 @@
+package postmarksmtp_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/postmark"
+	"github.com/mrz1836/postmark/postmarksmtp"
+	"github.com/mrz1836/postmark/postmarktest"
+)
+
+// rawMessage is a multipart message with Postmark headers and an attachment
+const rawMessage = "From: Sender <sender@example.com>\r\n" +
+	"To: jane@example.com\r\n" +
+	"Subject: Monthly report\r\n" +
+	"X-PM-Tag: reports\r\n" +
+	"X-PM-Message-Stream: outbound\r\n" +
+	"X-Campaign: march\r\n" +
+	"MIME-Version: 1.0\r\n" +
+	"Content-Type: multipart/mixed; boundary=b1\r\n" +
+	"\r\n" +
+	"--b1\r\n" +
+	"Content-Type: text/plain; charset=utf-8\r\n" +
+	"\r\n" +
+	"See the report\r\n" +
+	"--b1\r\n" +
+	"Content-Type: application/pdf\r\n" +
+	"Content-Disposition: attachment; filename=\"march.pdf\"\r\n" +
+	"Content-Transfer-Encoding: base64\r\n" +
+	"\r\n" +
+	"JVBERi0xLjQ=\r\n" +
+	"--b1--\r\n"
+
+// startServer serves SMTP with the sender on a random local port and returns its address
+func startServer(t *testing.T, sender postmarksmtp.Sender) (*postmarksmtp.Server, string) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	srv := &postmarksmtp.Server{Sender: sender}
+	done := make(chan error, 1)
+	go func() { done <- srv.Serve(listener) }()
+	t.Cleanup(func() {
+		_ = srv.Close()
+		require.ErrorIs(t, <-done, postmarksmtp.ErrServerClosed)
+	})
+	return srv, listener.Addr().String()
+}
+
+func TestServerRelay(t *testing.T) {
+	pm := postmarktest.NewServer()
+	t.Cleanup(pm.Close)
+	_, addr := startServer(t, pm.Client())
+
+	err := smtp.SendMail(addr, nil, "bounces@example.com",
+		[]string{"jane@example.com", "audit@example.com"}, []byte(rawMessage))
+	require.NoError(t, err)
+
+	messages := pm.Messages()
+	require.Len(t, messages, 1)
+	email := messages[0].Email
+	assert.Equal(t, `"Sender" <sender@example.com>`, email.From)
+	assert.Equal(t, "<jane@example.com>", email.To)
+	assert.Equal(t, "audit@example.com", email.Bcc)
+	assert.Equal(t, "Monthly report", email.Subject)
+	assert.Equal(t, "See the report", email.TextBody)
+	assert.Equal(t, "reports", email.Tag)
+	assert.Equal(t, "outbound", email.MessageStream)
+	assert.Equal(t, []postmark.Header{{Name: "X-Campaign", Value: "march"}}, email.Headers)
+	require.Len(t, email.Attachments, 1)
+	assert.Equal(t, postmark.Attachment{Name: "march.pdf", Content: "JVBERi0xLjQ=", ContentType: "application/pdf"}, email.Attachments[0])
+}
+
+func TestServerDeliversToEnvelopeRecipientsOnly(t *testing.T) {
+	pm := postmarktest.NewServer()
+	t.Cleanup(pm.Close)
+	_, addr := startServer(t, pm.Client())
+
+	raw := strings.Replace(rawMessage, "To: jane@example.com\r\n",
+		"To: Jane <jane@example.com>, boss@example.com\r\nCc: team@example.com\r\n", 1)
+	err := smtp.SendMail(addr, nil, "bounces@example.com", []string{"jane@example.com", "audit@example.com"}, []byte(raw))
+	require.NoError(t, err)
+
+	messages := pm.Messages()
+	require.Len(t, messages, 1)
+	email := messages[0].Email
+	assert.Equal(t, `"Jane" <jane@example.com>`, email.To)
+	assert.Empty(t, email.Cc)
+	assert.Equal(t, "audit@example.com", email.Bcc)
+}
+
+func TestServerRejectsLargeMessages(t *testing.T) {
+	pm := postmarktest.NewServer()
+	t.Cleanup(pm.Close)
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	srv := &postmarksmtp.Server{Sender: pm.Client(), MaxMessageBytes: 64}
+	go func() { _ = srv.Serve(listener) }()
+	t.Cleanup(func() { _ = srv.Close() })
+
+	conn, err := textproto.Dial("tcp", listener.Addr().String())
+	require.NoError(t, err)
+	defer func() { _ = conn.Close() }()
+
+	expect := func(code int, command string) {
+		t.Helper()
+		if command != "" {
+			require.NoError(t, conn.PrintfLine("%s", command))
+		}
+		_, _, err := conn.ReadResponse(code)
+		require.NoError(t, err, command)
+	}
+
+	expect(220, "")
+	expect(250, "HELO client.example.com")
+	expect(250, "MAIL FROM:<sender@example.com>")
+	expect(250, "RCPT TO:<jane@example.com>")
+	expect(354, "DATA")
+	require.NoError(t, conn.PrintfLine("Subject: Hi\r\n\r\n%s\r\n.", strings.Repeat("x", 100)))
+	expect(552, "")
+
+	// The session is still in sync after the rejected message
+	expect(250, "MAIL FROM:<sender@example.com>")
+	expect(250, "RCPT TO:<jane@example.com>")
+	expect(354, "DATA")
+	require.NoError(t, conn.PrintfLine("Subject: Hi\r\n\r\nHello\r\n."))
+	expect(250, "")
+	expect(221, "QUIT")
+	require.Len(t, pm.Messages(), 1)
+}
+
+func TestServerReplies(t *testing.T) {
+	pm := postmarktest.NewServer()
+	t.Cleanup(pm.Close)
+	pm.AddSuppression("outbound", postmark.Suppression{EmailAddress: "gone@example.com", SuppressionReason: postmark.HardBounceReason})
+	_, addr := startServer(t, pm.Client())
+
+	conn, err := textproto.Dial("tcp", addr)
+	require.NoError(t, err)
+	defer func() { _ = conn.Close() }()
+
+	expect := func(code int, command string) string {
+		t.Helper()
+		if command != "" {
+			require.NoError(t, conn.PrintfLine("%s", command))
+		}
+		_, message, err := conn.ReadResponse(code)
+		require.NoError(t, err, command)
+		return message
+	}
+
+	expect(220, "")
+	expect(503, "MAIL FROM:<sender@example.com>")
+	assert.Contains(t, expect(250, "EHLO client.example.com"), "SIZE ")
+	expect(552, "MAIL FROM:<sender@example.com> SIZE=999999999")
+	expect(250, "MAIL FROM:<sender@example.com>")
+	expect(553, "RCPT TO:<not an address>")
+	expect(250, "RCPT TO:<gone@example.com>")
+	expect(354, "DATA")
+	require.NoError(t, conn.PrintfLine("Subject: Hi\r\n\r\nHello\r\n."))
+	assert.Contains(t, expect(550, ""), "5.1.1")
+
+	expect(250, "RSET")
+	expect(502, "TURN")
+	expect(221, "QUIT")
+	assert.Empty(t, pm.Messages())
+}
+
+// senderFunc adapts a function to the Sender interface
+type senderFunc func(ctx context.Context, email postmark.Email) (postmark.EmailResponse, error)
+
+func (f senderFunc) SendEmail(ctx context.Context, email postmark.Email) (postmark.EmailResponse, error) {
+	return f(ctx, email)
+}
+
+func TestServerTemporaryFailure(t *testing.T) {
+	_, addr := startServer(t, senderFunc(func(context.Context, postmark.Email) (postmark.EmailResponse, error) {
+		return postmark.EmailResponse{}, errors.New("connection refused\nby peer")
+	}))
+
+	err := smtp.SendMail(addr, nil, "sender@example.com", []string{"jane@example.com"}, []byte("Subject: Hi\r\n\r\nHello\r\n"))
+	var protoErr *textproto.Error
+	require.ErrorAs(t, err, &protoErr)
+	assert.Equal(t, 451, protoErr.Code)
+	assert.Equal(t, "4.3.0 Temporary failure: connection refused by peer", protoErr.Msg)
+}
+
+func TestServerClose(t *testing.T) {
+	srv, addr := startServer(t, senderFunc(func(context.Context, postmark.Email) (postmark.EmailResponse, error) {
+		return postmark.EmailResponse{}, nil
+	}))
+
+	conn, err := textproto.Dial("tcp", addr)
+	require.NoError(t, err)
+	defer func() { _ = conn.Close() }()
+	_, _, err = conn.ReadResponse(220)
+	require.NoError(t, err)
+
+	require.NoError(t, srv.Close())
+	_, err = conn.ReadLine()
+	require.Error(t, err)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	require.ErrorIs(t, srv.Serve(listener), postmarksmtp.ErrServerClosed)
+}
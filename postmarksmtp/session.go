@@ -0,0 +1,316 @@
+package postmarksmtp
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/mail"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mrz1836/postmark"
+)
+
+// session is one SMTP connection
+type session struct {
+	server *Server
+	conn   net.Conn
+	text   *textproto.Conn
+
+	greeted    bool
+	from       string
+	recipients []string
+}
+
+// newSession returns a session for a connection
+func newSession(server *Server, conn net.Conn) *session {
+	return &session{server: server, conn: conn, text: textproto.NewConn(conn)}
+}
+
+// reply writes a single line response
+func (s *session) reply(code int, format string, args ...interface{}) error {
+	return s.text.PrintfLine("%d %s", code, fmt.Sprintf(format, args...))
+}
+
+// reset forgets the current transaction
+func (s *session) reset() {
+	s.from = ""
+	s.recipients = nil
+}
+
+// serve reads and answers commands until QUIT or an error
+func (s *session) serve() {
+	if err := s.reply(220, "%s ESMTP Postmark relay", s.server.hostname()); err != nil {
+		return
+	}
+
+	for {
+		_ = s.conn.SetReadDeadline(time.Now().Add(s.server.readTimeout()))
+		line, err := s.text.ReadLine()
+		if err != nil {
+			return
+		}
+
+		verb, arg, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(verb) {
+		case "HELO":
+			s.greeted = true
+			s.reset()
+			err = s.reply(250, "%s", s.server.hostname())
+		case "EHLO":
+			s.greeted = true
+			s.reset()
+			err = s.ehlo()
+		case "MAIL":
+			err = s.mail(arg)
+		case "RCPT":
+			err = s.rcpt(arg)
+		case "DATA":
+			err = s.data()
+		case "RSET":
+			s.reset()
+			err = s.reply(250, "2.0.0 OK")
+		case "NOOP":
+			err = s.reply(250, "2.0.0 OK")
+		case "VRFY":
+			err = s.reply(252, "2.5.0 Cannot verify the user, but will accept the message")
+		case "QUIT":
+			_ = s.reply(221, "2.0.0 Bye")
+			return
+		default:
+			err = s.reply(502, "5.5.2 Command not recognized")
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// ehlo answers EHLO with the supported extensions
+func (s *session) ehlo() error {
+	lines := []string{
+		s.server.hostname(),
+		"8BITMIME",
+		"ENHANCEDSTATUSCODES",
+		"SIZE " + strconv.FormatInt(s.server.maxMessageBytes(), 10),
+	}
+	for i, line := range lines {
+		separator := "-"
+		if i == len(lines)-1 {
+			separator = " "
+		}
+		if err := s.text.PrintfLine("250%s%s", separator, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parsePath returns the address of a MAIL FROM:<...> or RCPT TO:<...> argument, along with its parameters
+func parsePath(arg, prefix string) (address, params string, ok bool) {
+	if len(arg) < len(prefix) || !strings.EqualFold(arg[:len(prefix)], prefix) {
+		return "", "", false
+	}
+	rest := strings.TrimSpace(arg[len(prefix):])
+	if !strings.HasPrefix(rest, "<") {
+		return "", "", false
+	}
+	end := strings.Index(rest, ">")
+	if end < 0 {
+		return "", "", false
+	}
+	return rest[1:end], strings.TrimSpace(rest[end+1:]), true
+}
+
+// mail starts a transaction
+func (s *session) mail(arg string) error {
+	if !s.greeted {
+		return s.reply(503, "5.5.1 Send HELO or EHLO first")
+	}
+	if s.from != "" {
+		return s.reply(503, "5.5.1 Sender already specified")
+	}
+
+	from, params, ok := parsePath(arg, "FROM:")
+	if !ok {
+		return s.reply(501, "5.5.4 Syntax: MAIL FROM:<address>")
+	}
+	for _, param := range strings.Fields(params) {
+		if key, value, _ := strings.Cut(param, "="); strings.EqualFold(key, "SIZE") {
+			if size, err := strconv.ParseInt(value, 10, 64); err == nil && size > s.server.maxMessageBytes() {
+				return s.reply(552, "5.3.4 Message size exceeds the limit of %d bytes", s.server.maxMessageBytes())
+			}
+		}
+	}
+
+	// The null sender <> is used by bounces, Postmark needs a real sender in the From header anyway
+	s.from = from
+	if s.from == "" {
+		s.from = "<>"
+	}
+	return s.reply(250, "2.1.0 OK")
+}
+
+// rcpt adds a recipient to the transaction
+func (s *session) rcpt(arg string) error {
+	if s.from == "" {
+		return s.reply(503, "5.5.1 Send MAIL first")
+	}
+
+	to, _, ok := parsePath(arg, "TO:")
+	if !ok || to == "" {
+		return s.reply(501, "5.5.4 Syntax: RCPT TO:<address>")
+	}
+	if _, err := mail.ParseAddress(to); err != nil {
+		return s.reply(553, "5.1.3 Invalid recipient address")
+	}
+	if len(s.recipients) >= postmark.MaxRecipients {
+		return s.reply(452, "4.5.3 Too many recipients, the maximum is %d", postmark.MaxRecipients)
+	}
+
+	s.recipients = append(s.recipients, to)
+	return s.reply(250, "2.1.5 OK")
+}
+
+// data reads the message and forwards it to Postmark
+func (s *session) data() error {
+	if len(s.recipients) == 0 {
+		return s.reply(503, "5.5.1 Send RCPT first")
+	}
+	if err := s.reply(354, "Start mail input; end with <CRLF>.<CRLF>"); err != nil {
+		return err
+	}
+
+	limit := s.server.maxMessageBytes()
+	_ = s.conn.SetReadDeadline(time.Now().Add(s.server.readTimeout()))
+	r := s.text.DotReader()
+	raw, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return err
+	}
+	defer s.reset()
+
+	if int64(len(raw)) > limit {
+		// Drain the rest of the message from the same reader before answering
+		if _, err = io.Copy(io.Discard, r); err != nil {
+			return err
+		}
+		return s.reply(552, "5.3.4 Message size exceeds the limit of %d bytes", limit)
+	}
+
+	email, err := postmark.ParseMIME(bytes.NewReader(raw))
+	if err != nil {
+		return s.reply(554, "5.6.0 %s", oneLine(err.Error()))
+	}
+	applyEnvelope(&email, s.from, s.recipients)
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.server.sendTimeout())
+	defer cancel()
+	res, err := s.server.Sender.SendEmail(ctx, email)
+
+	code, status, message := result(res, err)
+	if s.server.Logger != nil {
+		s.server.Logger.LogAttrs(ctx, slog.LevelInfo, "postmark smtp relay",
+			slog.String("from", email.From),
+			slog.Int("recipients", len(s.recipients)),
+			slog.String("message_id", res.MessageID),
+			slog.Int("smtp_code", code),
+			slog.Int64("error_code", postmark.ErrorCode(err)),
+			slog.String("message", message),
+		)
+	}
+	return s.reply(code, "%s %s", status, oneLine(message))
+}
+
+// applyEnvelope completes an email with the SMTP envelope: the envelope sender is used when there is no From
+// header, and the email is delivered to the envelope recipients only. To and Cc keep the header addresses that are
+// envelope recipients, the other envelope recipients (e.g. blind copies) are sent as Bcc.
+func applyEnvelope(email *postmark.Email, from string, recipients []string) {
+	if email.From == "" && from != "<>" {
+		email.From = from
+	}
+
+	envelope := make(map[string]bool, len(recipients))
+	for _, recipient := range recipients {
+		envelope[strings.ToLower(recipient)] = true
+	}
+
+	visible := make(map[string]bool)
+	for _, list := range []*string{&email.To, &email.Cc} {
+		addresses, err := mail.ParseAddressList(*list)
+		if err != nil {
+			*list = ""
+			continue
+		}
+		var kept []string
+		for _, address := range addresses {
+			if envelope[strings.ToLower(address.Address)] {
+				visible[strings.ToLower(address.Address)] = true
+				kept = append(kept, address.String())
+			}
+		}
+		*list = strings.Join(kept, ", ")
+	}
+
+	var bcc []string
+	for _, recipient := range recipients {
+		if !visible[strings.ToLower(recipient)] {
+			visible[strings.ToLower(recipient)] = true
+			bcc = append(bcc, recipient)
+		}
+	}
+	email.Bcc = strings.Join(bcc, ", ")
+	if email.To == "" && email.Cc == "" && len(bcc) > 0 {
+		email.To, email.Bcc = bcc[0], strings.Join(bcc[1:], ", ")
+	}
+}
+
+// result returns the SMTP reply code, enhanced status code and message for the outcome of SendEmail
+func result(res postmark.EmailResponse, err error) (code int, status, message string) {
+	if err == nil {
+		return 250, "2.0.0", "OK queued as " + res.MessageID
+	}
+
+	var validationErr postmark.EmailValidationError
+	if errors.As(err, &validationErr) {
+		return 550, "5.6.0", validationErr.Error()
+	}
+
+	var apiErr postmark.APIError
+	if !errors.As(err, &apiErr) {
+		// The request never got a response, the sender should try again later
+		return 451, "4.3.0", "Temporary failure: " + err.Error()
+	}
+
+	switch apiErr.ErrorCode {
+	case postmark.ErrorCodeInactiveRecipient:
+		return 550, "5.1.1", apiErr.Message
+	case postmark.ErrorCodeSenderSignatureNotFound, postmark.ErrorCodeSenderSignatureNotConfirmed:
+		return 550, "5.7.1", apiErr.Message
+	case postmark.ErrorCodeInvalidEmailRequest, postmark.ErrorCodeInvalidJSON, postmark.ErrorCodeIncompatibleJSON,
+		postmark.ErrorCodeJSONRequired, postmark.ErrorCodeForbiddenAttachmentType:
+		return 554, "5.6.0", apiErr.Message
+	case postmark.ErrorCodeNotAllowedToSend, postmark.ErrorCodeAccountPending, postmark.ErrorCodeAccountMayNotSend:
+		return 554, "5.7.0", apiErr.Message
+	case postmark.ErrorCodeInvalidAPIToken:
+		return 451, "4.7.0", "Relay misconfigured: " + apiErr.Message
+	case postmark.ErrorCodeMaintenance:
+		return 451, "4.3.2", apiErr.Message
+	}
+	if apiErr.StatusCode == http.StatusTooManyRequests || apiErr.StatusCode >= http.StatusInternalServerError {
+		return 451, "4.3.0", "Temporary failure: " + apiErr.Error()
+	}
+	return 554, "5.0.0", apiErr.Error()
+}
+
+// oneLine replaces the line breaks of a message, which would break the SMTP reply
+func oneLine(message string) string {
+	return strings.Join(strings.Fields(message), " ")
+}
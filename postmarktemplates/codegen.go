@@ -0,0 +1,308 @@
+package postmarktemplates
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/format"
+	"maps"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/mrz1836/postmark"
+)
+
+// ErrInvalidTemplate is returned when Postmark reports a template content as invalid
+var ErrInvalidTemplate = errors.New("postmarktemplates: invalid template content")
+
+// Validator validates template content, *postmark.Client implements it
+type Validator interface {
+	ValidateTemplate(ctx context.Context, body postmark.ValidateTemplateBody) (postmark.ValidateTemplateResponse, error)
+}
+
+// TemplateModel is the model a template expects, shaped like the SuggestedTemplateModel of ValidateTemplate
+type TemplateModel struct {
+	// Alias of the template
+	Alias string
+	// Name of the template
+	Name string
+	// Model: suggested model, string values are placeholders
+	Model map[string]interface{}
+}
+
+// SuggestModels returns the model of each standard template with an alias, suggested by ValidateTemplate.
+// The fields used by the layout of a template are merged into its model when the layout is in templates.
+func SuggestModels(ctx context.Context, validator Validator, templates []postmark.Template) ([]TemplateModel, error) {
+	layouts := make(map[string]postmark.Template)
+	for _, template := range templates {
+		if template.TemplateType == TypeLayout {
+			layouts[template.Alias] = template
+		}
+	}
+
+	var models []TemplateModel
+	for _, template := range standardTemplates(templates) {
+		model, err := validate(ctx, validator, template)
+		if err != nil {
+			return nil, err
+		}
+		if layout, ok := layouts[template.LayoutTemplate]; ok {
+			layoutModel, err := validate(ctx, validator, layout)
+			if err != nil {
+				return nil, err
+			}
+			mergeModel(model, layoutModel)
+		}
+		models = append(models, TemplateModel{Alias: template.Alias, Name: template.Name, Model: model})
+	}
+	return models, nil
+}
+
+// validate returns the model suggested by ValidateTemplate for a template or layout
+func validate(ctx context.Context, validator Validator, template postmark.Template) (map[string]interface{}, error) {
+	res, err := validator.ValidateTemplate(ctx, postmark.ValidateTemplateBody{
+		Subject:  template.Subject,
+		HTMLBody: template.HTMLBody,
+		TextBody: template.TextBody,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("postmarktemplates: validate %q: %w", template.Alias, err)
+	}
+	if !res.AllContentIsValid {
+		fields := []struct {
+			name       string
+			validation postmark.Validation
+		}{{"Subject", res.Subject}, {"HTMLBody", res.HTMLBody}, {"TextBody", res.TextBody}}
+		for _, field := range fields {
+			if len(field.validation.ValidationErrors) > 0 {
+				first := field.validation.ValidationErrors[0]
+				return nil, fmt.Errorf("%w: %q %s: %s at line %d, character %d",
+					ErrInvalidTemplate, template.Alias, field.name, first.Message, first.Line, first.CharacterPosition)
+			}
+		}
+		return nil, fmt.Errorf("%w: %q", ErrInvalidTemplate, template.Alias)
+	}
+	if res.SuggestedTemplateModel == nil {
+		return map[string]interface{}{}, nil
+	}
+	return res.SuggestedTemplateModel, nil
+}
+
+// LocalModels returns the model of each standard template with an alias, derived from its Mustachio tags
+// without calling the API. The layouts of the templates must be in templates.
+func LocalModels(templates []postmark.Template) ([]TemplateModel, error) {
+	renderer := postmark.NewTemplateRenderer(templates...)
+
+	var models []TemplateModel
+	for _, template := range standardTemplates(templates) {
+		model, err := renderer.SuggestModel(template)
+		if err != nil {
+			return nil, fmt.Errorf("postmarktemplates: %q: %w", template.Alias, err)
+		}
+		models = append(models, TemplateModel{Alias: template.Alias, Name: template.Name, Model: model})
+	}
+	return models, nil
+}
+
+// standardTemplates returns the templates used to send email: standard templates with an alias
+func standardTemplates(templates []postmark.Template) []postmark.Template {
+	var standard []postmark.Template
+	for _, template := range templates {
+		if template.Alias != "" && template.TemplateType != TypeLayout {
+			standard = append(standard, template)
+		}
+	}
+	return standard
+}
+
+// mergeModel adds the fields of src missing from dst, objects present in both are merged
+func mergeModel(dst, src map[string]interface{}) {
+	for key, value := range src {
+		existing, ok := dst[key]
+		if !ok {
+			dst[key] = value
+			continue
+		}
+		dstObject, dstOK := existing.(map[string]interface{})
+		srcObject, srcOK := value.(map[string]interface{})
+		if dstOK && srcOK {
+			mergeModel(dstObject, srcObject)
+		}
+	}
+}
+
+// GenerateCode returns the Go source of a package with a struct per template model and a SendXxx function
+// per template that sends it with a typed model, so model mismatches are compile errors
+func GenerateCode(pkg string, models []TemplateModel) ([]byte, error) {
+	g := &generator{names: map[string]bool{"TemplateSender": true}}
+	for _, model := range models {
+		g.template(model)
+	}
+
+	var b strings.Builder
+	b.WriteString("// Code generated by postmark-templategen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "package %s\n\n", pkg)
+	b.WriteString(`import (
+	"context"
+	"encoding/json"
+
+	"github.com/mrz1836/postmark"
+)
+
+// TemplateSender sends templated email, *postmark.Client implements it
+type TemplateSender interface {
+	SendTemplatedEmail(ctx context.Context, email postmark.TemplatedEmail) (postmark.EmailResponse, error)
+}
+
+// toTemplateModel converts a typed model to the TemplateModel of a templated email
+func toTemplateModel(model interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(model)
+	if err != nil {
+		return nil, err
+	}
+	var templateModel map[string]interface{}
+	err = json.Unmarshal(data, &templateModel)
+	return templateModel, err
+}
+
+`)
+	for _, decl := range g.decls {
+		b.WriteString(decl)
+	}
+
+	source, err := format.Source([]byte(b.String()))
+	if err != nil {
+		return nil, fmt.Errorf("postmarktemplates: invalid generated code: %w", err)
+	}
+	return source, nil
+}
+
+// generator collects the declarations of the generated code
+type generator struct {
+	decls []string
+	names map[string]bool
+}
+
+// template declares the alias constant, model struct and send function of a template
+func (g *generator) template(model TemplateModel) {
+	base := goName(model.Alias)
+	for n := 2; g.names[base+"Alias"] || g.names[base+"Model"] || g.names["Send"+base]; n++ {
+		base = fmt.Sprintf("%s%d", goName(model.Alias), n)
+	}
+	g.names[base+"Alias"], g.names["Send"+base] = true, true
+
+	g.decls = append(g.decls, fmt.Sprintf("// %sAlias is the alias of the %q template\nconst %sAlias = %q\n\n",
+		base, model.Name, base, model.Alias))
+	modelType := g.declareStruct(base+"Model", fmt.Sprintf("is the model of the %q template", model.Name), model.Model)
+	g.decls = append(g.decls, fmt.Sprintf(`// Send%[1]s sends email with the %[2]q template and a typed model, replacing its template and model
+func Send%[1]s(ctx context.Context, client TemplateSender, email postmark.TemplatedEmail, model %[3]s) (postmark.EmailResponse, error) {
+	templateModel, err := toTemplateModel(model)
+	if err != nil {
+		return postmark.EmailResponse{}, err
+	}
+	email.TemplateID = 0
+	email.TemplateAlias = %[1]sAlias
+	email.TemplateModel = templateModel
+	return client.SendTemplatedEmail(ctx, email)
+}
+
+`, base, model.Name, modelType))
+}
+
+// declareStruct declares a struct for an object of the model and returns its name
+func (g *generator) declareStruct(name, doc string, object map[string]interface{}) string {
+	name = g.uniqueName(name)
+	index := len(g.decls)
+	g.decls = append(g.decls, "") // the struct is declared before the structs of its fields
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "// %s %s\ntype %s struct {\n", name, doc, name)
+	fields := make(map[string]bool, len(object))
+	for _, key := range slices.Sorted(maps.Keys(object)) {
+		field := goName(key)
+		for n := 2; fields[field]; n++ {
+			field = fmt.Sprintf("%s%d", goName(key), n)
+		}
+		fields[field] = true
+
+		fieldType := g.goType(name+field, fmt.Sprintf("is the %s of %s", key, name), object[key])
+		fmt.Fprintf(&b, "\t%s %s `json:%q`\n", field, fieldType, key)
+	}
+	b.WriteString("}\n\n")
+
+	g.decls[index] = b.String()
+	return name
+}
+
+// goType returns the Go type of a model value, declaring the structs it needs
+func (g *generator) goType(name, doc string, value interface{}) string {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return g.declareStruct(name, doc, v)
+	case []interface{}:
+		if len(v) == 0 {
+			return "[]interface{}"
+		}
+		return "[]" + g.goType(singular(name), "is an item of the "+strings.TrimPrefix(doc, "is the "), v[0])
+	case string:
+		return "string"
+	case bool:
+		return "bool"
+	case float64, json.Number:
+		return "float64"
+	}
+	return "interface{}"
+}
+
+// uniqueName returns name, with a number added if it is already declared
+func (g *generator) uniqueName(name string) string {
+	unique := name
+	for n := 2; g.names[unique]; n++ {
+		unique = fmt.Sprintf("%s%d", name, n)
+	}
+	g.names[unique] = true
+	return unique
+}
+
+// isInitialism reports whether a word is written in upper case in Go names
+func isInitialism(word string) bool {
+	switch strings.ToLower(word) {
+	case "api", "css", "html", "http", "https", "id", "ip", "json", "sql", "uri", "url", "uuid":
+		return true
+	}
+	return false
+}
+
+// goName returns an exported Go name for a model key or alias: "order_id" is OrderID, "firstName" is FirstName
+func goName(key string) string {
+	words := strings.FieldsFunc(key, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) })
+
+	var b strings.Builder
+	for _, word := range words {
+		if isInitialism(word) {
+			b.WriteString(strings.ToUpper(word))
+			continue
+		}
+		runes := []rune(word)
+		b.WriteString(string(unicode.ToUpper(runes[0])) + string(runes[1:]))
+	}
+
+	name := b.String()
+	switch {
+	case name == "":
+		return "Field"
+	case unicode.IsDigit([]rune(name)[0]):
+		return "X" + name
+	}
+	return name
+}
+
+// singular returns the name of the items of a list: Items holds Item
+func singular(name string) string {
+	if len(name) > 1 && strings.HasSuffix(name, "s") && !strings.HasSuffix(name, "ss") {
+		return strings.TrimSuffix(name, "s")
+	}
+	return name + "Item"
+}
@@ -0,0 +1,81 @@
+package postmarktemplates_test
+
+import (
+	"context"
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/postmark"
+	"github.com/mrz1836/postmark/postmarktemplates"
+	"github.com/mrz1836/postmark/postmarktest"
+)
+
+var _ postmarktemplates.Validator = (*postmark.Client)(nil)
+
+func TestLocalModels(t *testing.T) {
+	templates, err := postmarktemplates.Load(templateDir())
+	require.NoError(t, err)
+	templates[0].HTMLBody = "<p>Hello {{name}}</p>{{#company}}{{name}}{{/company}}"
+	templates[1].HTMLBody = "<html>{{ company.url }}{{{ @content }}}</html>"
+
+	models, err := postmarktemplates.LocalModels(templates)
+	require.NoError(t, err)
+	assert.Equal(t, []postmarktemplates.TemplateModel{{
+		Alias: "welcome",
+		Name:  "Welcome",
+		Model: map[string]interface{}{
+			"name":    "name_Value",
+			"company": map[string]interface{}{"name": "name_Value", "url": "url_Value"},
+		},
+	}}, models)
+}
+
+func TestSuggestModels(t *testing.T) {
+	pm := postmarktest.NewServer()
+	t.Cleanup(pm.Close)
+	templates, err := postmarktemplates.Load(templateDir())
+	require.NoError(t, err)
+	templates[1].HTMLBody = "<html>{{ footer }}{{{ @content }}}</html>"
+
+	models, err := postmarktemplates.SuggestModels(context.Background(), pm.Client(), templates)
+	require.NoError(t, err)
+	require.Len(t, models, 1)
+	assert.Equal(t, map[string]interface{}{"name": "name_Value", "footer": "footer_Value"}, models[0].Model)
+}
+
+func TestGenerateCode(t *testing.T) {
+	source, err := postmarktemplates.GenerateCode("emails", []postmarktemplates.TemplateModel{
+		{Alias: "welcome", Name: "Welcome", Model: map[string]interface{}{
+			"name":     "name_Value",
+			"order_id": "order_id_Value",
+			"company":  map[string]interface{}{"name": "name_Value"},
+			"items":    []interface{}{map[string]interface{}{"title": "title_Value", "qty": 1.0}},
+			"tags":     []interface{}{"tags_Value"},
+			"premium":  true,
+		}},
+		{Alias: "password-reset", Name: "Password reset", Model: map[string]interface{}{"url": "url_Value"}},
+	})
+	require.NoError(t, err)
+
+	_, err = parser.ParseFile(token.NewFileSet(), "emails.go", source, parser.AllErrors)
+	require.NoError(t, err)
+
+	code := string(source)
+	assert.Contains(t, code, "// Code generated by postmark-templategen. DO NOT EDIT.\n\npackage emails\n")
+	assert.Contains(t, code, "const WelcomeAlias = \"welcome\"")
+	assert.Contains(t, code, "type WelcomeModel struct {\n"+
+		"\tCompany WelcomeModelCompany `json:\"company\"`\n"+
+		"\tItems   []WelcomeModelItem  `json:\"items\"`\n"+
+		"\tName    string              `json:\"name\"`\n"+
+		"\tOrderID string              `json:\"order_id\"`\n"+
+		"\tPremium bool                `json:\"premium\"`\n"+
+		"\tTags    []string            `json:\"tags\"`\n}")
+	assert.Contains(t, code, "type WelcomeModelItem struct {\n\tQty   float64 `json:\"qty\"`\n\tTitle string  `json:\"title\"`\n}")
+	assert.Contains(t, code, "func SendWelcome(ctx context.Context, client TemplateSender, email postmark.TemplatedEmail, model WelcomeModel) (postmark.EmailResponse, error) {")
+	assert.Contains(t, code, "type PasswordResetModel struct {\n\tURL string `json:\"url\"`\n}")
+	assert.Contains(t, code, "email.TemplateAlias = PasswordResetAlias")
+}
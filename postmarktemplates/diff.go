@@ -0,0 +1,188 @@
+package postmarktemplates
+
+import (
+	"strings"
+
+	"github.com/mrz1836/postmark"
+)
+
+const (
+	// diffContext is the number of unchanged lines shown around changes by FieldDiff.Unified
+	diffContext = 2
+
+	// maxDiffCells caps the lines of old times the lines of new compared by DiffLines, about 8 MB of memory
+	maxDiffCells = 1 << 20
+)
+
+// DiffOp is the operation of a line in a diff
+type DiffOp string
+
+const (
+	// DiffEqual is a line present in both versions
+	DiffEqual DiffOp = " "
+	// DiffDelete is a line only present in the old version
+	DiffDelete DiffOp = "-"
+	// DiffInsert is a line only present in the new version
+	DiffInsert DiffOp = "+"
+)
+
+// DiffLine is a line of a diff
+type DiffLine struct {
+	// Op tells whether the line is kept, deleted or inserted
+	Op DiffOp
+	// Text is the content of the line
+	Text string
+}
+
+// FieldDiff is the difference between two versions of a template field
+type FieldDiff struct {
+	// Field: Name, Subject, HTMLBody, TextBody or LayoutTemplate
+	Field string
+	// Old is the current value
+	Old string
+	// New is the desired value
+	New string
+	// Lines is the line-level diff from Old to New
+	Lines []DiffLine
+}
+
+// Unified returns the changed lines prefixed with "-" or "+", with a few unchanged lines of context
+func (d FieldDiff) Unified() string {
+	var b strings.Builder
+	skipped := false
+	for n, line := range d.Lines {
+		if line.Op == DiffEqual && !nearChange(d.Lines, n) {
+			skipped = true
+			continue
+		}
+		if skipped {
+			b.WriteString("  ...\n")
+			skipped = false
+		}
+		b.WriteString(string(line.Op) + " " + line.Text + "\n")
+	}
+	return b.String()
+}
+
+// writeDiffs writes the unified diff of each field, indented under the field name
+func writeDiffs(b *strings.Builder, diffs []FieldDiff) {
+	for _, diff := range diffs {
+		b.WriteString("  " + diff.Field + ":\n")
+		for _, line := range strings.SplitAfter(strings.TrimSuffix(diff.Unified(), "\n"), "\n") {
+			b.WriteString("    " + line)
+		}
+		b.WriteString("\n")
+	}
+}
+
+// nearChange reports whether a line is within diffContext lines of a deleted or inserted line
+func nearChange(lines []DiffLine, n int) bool {
+	for i := max(0, n-diffContext); i <= min(len(lines)-1, n+diffContext); i++ {
+		if lines[i].Op != DiffEqual {
+			return true
+		}
+	}
+	return false
+}
+
+// DiffTemplates returns the fields that differ between the current and the desired version of a template
+func DiffTemplates(current, desired postmark.Template) []FieldDiff {
+	fields := []struct {
+		name     string
+		old, new string
+	}{
+		{"Name", current.Name, desired.Name},
+		{"Subject", current.Subject, desired.Subject},
+		{"HTMLBody", current.HTMLBody, desired.HTMLBody},
+		{"TextBody", current.TextBody, desired.TextBody},
+		{"LayoutTemplate", current.LayoutTemplate, desired.LayoutTemplate},
+	}
+
+	var diffs []FieldDiff
+	for _, field := range fields {
+		if field.old != field.new {
+			diffs = append(diffs, FieldDiff{Field: field.name, Old: field.old, New: field.new, Lines: DiffLines(field.old, field.new)})
+		}
+	}
+	return diffs
+}
+
+// DiffLines returns the line-level diff from old to new, based on their longest common subsequence of lines.
+// The lines shared at the start and end are kept as is, when the remaining lines are too many to compare
+// (more than maxDiffCells pairs) they are all deleted then inserted.
+func DiffLines(old, new string) []DiffLine {
+	a, b := splitLines(old), splitLines(new)
+
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	lines := make([]DiffLine, 0, max(len(a), len(b)))
+	for _, text := range a[:prefix] {
+		lines = append(lines, DiffLine{Op: DiffEqual, Text: text})
+	}
+	lines = append(lines, diffMiddle(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, text := range a[len(a)-suffix:] {
+		lines = append(lines, DiffLine{Op: DiffEqual, Text: text})
+	}
+	return lines
+}
+
+// diffMiddle diffs lines with the longest common subsequence table, or as a whole when the table is too large
+func diffMiddle(a, b []string) []DiffLine {
+	lines := make([]DiffLine, 0, max(len(a), len(b)))
+	if len(a)*len(b) > maxDiffCells {
+		for _, text := range a {
+			lines = append(lines, DiffLine{Op: DiffDelete, Text: text})
+		}
+		for _, text := range b {
+			lines = append(lines, DiffLine{Op: DiffInsert, Text: text})
+		}
+		return lines
+	}
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			lines = append(lines, DiffLine{Op: DiffEqual, Text: a[i]})
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			lines = append(lines, DiffLine{Op: DiffDelete, Text: a[i]})
+			i++
+		default:
+			lines = append(lines, DiffLine{Op: DiffInsert, Text: b[j]})
+			j++
+		}
+	}
+	return lines
+}
+
+// splitLines splits a value into lines, an empty value has none
+func splitLines(value string) []string {
+	if value == "" {
+		return nil
+	}
+	return strings.Split(strings.ReplaceAll(value, "\r\n", "\n"), "\n")
+}
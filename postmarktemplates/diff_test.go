@@ -0,0 +1,58 @@
+package postmarktemplates_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/postmark"
+	"github.com/mrz1836/postmark/postmarktemplates"
+)
+
+func TestDiffLines(t *testing.T) {
+	assert.Equal(t, []postmarktemplates.DiffLine{
+		{Op: postmarktemplates.DiffEqual, Text: "a"},
+		{Op: postmarktemplates.DiffDelete, Text: "b"},
+		{Op: postmarktemplates.DiffInsert, Text: "B"},
+		{Op: postmarktemplates.DiffEqual, Text: "c"},
+		{Op: postmarktemplates.DiffInsert, Text: "d"},
+	}, postmarktemplates.DiffLines("a\nb\nc", "a\nB\nc\nd"))
+
+	assert.Empty(t, postmarktemplates.DiffLines("", ""))
+	assert.Equal(t, []postmarktemplates.DiffLine{{Op: postmarktemplates.DiffInsert, Text: "x"}}, postmarktemplates.DiffLines("", "x"))
+}
+
+func TestDiffLinesLargeValues(t *testing.T) {
+	old, new := []string{"<html>"}, []string{"<html>"}
+	for n := range 2000 {
+		old = append(old, fmt.Sprintf("old %d", n))
+		new = append(new, fmt.Sprintf("new %d", n))
+	}
+	old, new = append(old, "</html>"), append(new, "</html>")
+
+	lines := postmarktemplates.DiffLines(strings.Join(old, "\n"), strings.Join(new, "\n"))
+	require.Len(t, lines, 4002)
+	assert.Equal(t, postmarktemplates.DiffLine{Op: postmarktemplates.DiffEqual, Text: "<html>"}, lines[0])
+	assert.Equal(t, postmarktemplates.DiffLine{Op: postmarktemplates.DiffDelete, Text: "old 0"}, lines[1])
+	assert.Equal(t, postmarktemplates.DiffLine{Op: postmarktemplates.DiffInsert, Text: "new 0"}, lines[2001])
+	assert.Equal(t, postmarktemplates.DiffLine{Op: postmarktemplates.DiffEqual, Text: "</html>"}, lines[4001])
+}
+
+func TestDiffTemplates(t *testing.T) {
+	current := postmark.Template{Name: "Welcome", Subject: "Hi", HTMLBody: "1\n2\n3\n4\n5\n6\n7\n8", TextBody: "Hello"}
+	desired := current
+	assert.Empty(t, postmarktemplates.DiffTemplates(current, desired))
+
+	desired.Subject = "Hello"
+	desired.HTMLBody = "1\n2\n3\n4\n5\n6\n7\neight"
+	diffs := postmarktemplates.DiffTemplates(current, desired)
+	if assert.Len(t, diffs, 2) {
+		assert.Equal(t, "Subject", diffs[0].Field)
+		assert.Equal(t, "- Hi\n+ Hello\n", diffs[0].Unified())
+		assert.Equal(t, "HTMLBody", diffs[1].Field)
+		assert.Equal(t, "  ...\n  6\n  7\n- 8\n+ eight\n", diffs[1].Unified())
+	}
+}
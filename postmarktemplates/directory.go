@@ -0,0 +1,216 @@
+// Package postmarktemplates manages Postmark templates as code.
+//
+// Templates and layouts live in a directory, typically checked into git, described by a manifest:
+//
+//	templates.json        {"Templates": [{"Alias": "basic", "TemplateType": "Layout"}, {"Alias": "welcome", "Name": "Welcome", "LayoutTemplate": "basic"}]}
+//	basic/content.html    layout HTML body, with {{{ @content }}}
+//	basic/content.txt     layout text body
+//	welcome/subject.txt   subject
+//	welcome/content.html  HTML body
+//	welcome/content.txt   text body
+//
+// Instead of a manifest, each alias directory can hold its entry in a template.json file, as written by Export:
+//
+//	welcome/template.json {"Alias": "welcome", "Name": "Welcome", "TemplateType": "Standard", "LayoutTemplate": "basic"}
+//
+// Sync compares the directory with a server and creates, updates or deletes templates to match it,
+// Export writes the templates of a server to a directory and Restore recreates them on any server.
+// PreviewPush reports the content a PushTemplates between two servers would change.
+// GenerateCode writes typed model structs and send functions for templates, used by cmd/postmark-templategen.
+package postmarktemplates
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+
+	"github.com/mrz1836/postmark"
+)
+
+// Files of a template directory
+const (
+	// ManifestFile lists the templates of a directory
+	ManifestFile = "templates.json"
+	// MetadataFile holds the ManifestEntry of a template in its directory, read when there is no ManifestFile
+	MetadataFile = "template.json"
+	// SubjectFile holds the subject of a template, in the directory named after its alias
+	SubjectFile = "subject.txt"
+	// HTMLFile holds the HTML body of a template
+	HTMLFile = "content.html"
+	// TextFile holds the text body of a template
+	TextFile = "content.txt"
+)
+
+// Template types
+const (
+	// TypeStandard is the type of templates used to send email
+	TypeStandard = "Standard"
+	// TypeLayout is the type of templates that wrap standard templates
+	TypeLayout = "Layout"
+)
+
+// ErrInvalidManifest is returned when the manifest or the files of a template directory are invalid
+var ErrInvalidManifest = errors.New("postmarktemplates: invalid template directory")
+
+// Manifest lists the templates of a directory
+type Manifest struct {
+	// Templates: templates and layouts, in any order
+	Templates []ManifestEntry
+}
+
+// ManifestEntry describes a template, its content is read from the directory named after its alias
+type ManifestEntry struct {
+	// Alias: REQUIRED alias of the template, unique within the manifest
+	Alias string
+	// Name: name of the template, the alias by default
+	Name string `json:",omitempty"`
+	// TemplateType: Standard (default) or Layout
+	TemplateType string `json:",omitempty"`
+	// LayoutTemplate: alias of the layout of a standard template
+	LayoutTemplate string `json:",omitempty"`
+}
+
+// Load reads the manifest and the templates of a directory, in manifest order.
+// Without a manifest, the entries are read from the MetadataFile of each alias directory, ordered by alias.
+func Load(fsys fs.FS) ([]postmark.Template, error) {
+	manifest, err := readManifest(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	templates := make([]postmark.Template, 0, len(manifest.Templates))
+	types := make(map[string]string, len(manifest.Templates))
+	for n, entry := range manifest.Templates {
+		template, err := loadTemplate(fsys, entry)
+		if err != nil {
+			return nil, fmt.Errorf("%w: template %d: %w", ErrInvalidManifest, n, err)
+		}
+		if _, ok := types[template.Alias]; ok {
+			return nil, fmt.Errorf("%w: duplicate alias %q", ErrInvalidManifest, template.Alias)
+		}
+		types[template.Alias] = template.TemplateType
+		templates = append(templates, template)
+	}
+
+	for _, template := range templates {
+		if layoutType, ok := types[template.LayoutTemplate]; ok && layoutType != TypeLayout {
+			return nil, fmt.Errorf("%w: %q uses %q as layout, which is not a layout",
+				ErrInvalidManifest, template.Alias, template.LayoutTemplate)
+		}
+	}
+	return templates, nil
+}
+
+// readManifest reads the ManifestFile of a directory, or else the MetadataFile of its alias directories
+func readManifest(fsys fs.FS) (Manifest, error) {
+	data, err := fs.ReadFile(fsys, ManifestFile)
+	if errors.Is(err, fs.ErrNotExist) {
+		return readMetadata(fsys)
+	} else if err != nil {
+		return Manifest{}, fmt.Errorf("%w: %w", ErrInvalidManifest, err)
+	}
+
+	var manifest Manifest
+	if err = json.Unmarshal(data, &manifest); err != nil {
+		return Manifest{}, fmt.Errorf("%w: %s: %w", ErrInvalidManifest, ManifestFile, err)
+	}
+	return manifest, nil
+}
+
+// readMetadata reads the MetadataFile of every alias directory, ordered by alias
+func readMetadata(fsys fs.FS) (Manifest, error) {
+	files, err := fs.Glob(fsys, path.Join("*", MetadataFile))
+	if err != nil {
+		return Manifest{}, fmt.Errorf("%w: %w", ErrInvalidManifest, err)
+	}
+	if len(files) == 0 {
+		return Manifest{}, fmt.Errorf("%w: no %s or %s", ErrInvalidManifest, ManifestFile, path.Join("*", MetadataFile))
+	}
+
+	var manifest Manifest
+	for _, file := range files {
+		data, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return Manifest{}, fmt.Errorf("%w: %w", ErrInvalidManifest, err)
+		}
+		var entry ManifestEntry
+		if err = json.Unmarshal(data, &entry); err != nil {
+			return Manifest{}, fmt.Errorf("%w: %s: %w", ErrInvalidManifest, file, err)
+		}
+		if entry.Alias != path.Dir(file) {
+			return Manifest{}, fmt.Errorf("%w: %s has the alias %q", ErrInvalidManifest, file, entry.Alias)
+		}
+		manifest.Templates = append(manifest.Templates, entry)
+	}
+	return manifest, nil
+}
+
+// loadTemplate checks a manifest entry and reads its content
+func loadTemplate(fsys fs.FS, entry ManifestEntry) (postmark.Template, error) {
+	template := postmark.Template{
+		Name:           entry.Name,
+		Alias:          entry.Alias,
+		TemplateType:   entry.TemplateType,
+		LayoutTemplate: entry.LayoutTemplate,
+	}
+	if !validAlias(template.Alias) {
+		return template, fmt.Errorf("alias %q is not a valid directory name", template.Alias)
+	}
+	if template.Name == "" {
+		template.Name = template.Alias
+	}
+	if template.TemplateType == "" {
+		template.TemplateType = TypeStandard
+	}
+
+	var err error
+	if template.Subject, err = readContent(fsys, template.Alias, SubjectFile); err != nil {
+		return template, err
+	}
+	template.Subject = strings.TrimSpace(template.Subject)
+	if template.HTMLBody, err = readContent(fsys, template.Alias, HTMLFile); err != nil {
+		return template, err
+	}
+	if template.TextBody, err = readContent(fsys, template.Alias, TextFile); err != nil {
+		return template, err
+	}
+
+	switch template.TemplateType {
+	case TypeStandard:
+		if template.Subject == "" {
+			return template, fmt.Errorf("%q has no %s", template.Alias, SubjectFile)
+		}
+	case TypeLayout:
+		if template.Subject != "" || template.LayoutTemplate != "" {
+			return template, fmt.Errorf("layout %q cannot have a subject or a layout", template.Alias)
+		}
+	default:
+		return template, fmt.Errorf("%q has an unknown TemplateType %q", template.Alias, template.TemplateType)
+	}
+	if template.HTMLBody == "" && template.TextBody == "" {
+		return template, fmt.Errorf("%q has neither %s nor %s", template.Alias, HTMLFile, TextFile)
+	}
+	return template, nil
+}
+
+// validAlias reports whether an alias can be used as the name of a template directory
+func validAlias(alias string) bool {
+	return alias != "" && alias != "." && fs.ValidPath(alias) && !strings.ContainsAny(alias, `/\`)
+}
+
+// readContent reads a content file of a template, a missing file is empty.
+// The final line break that editors add is not part of the content.
+func readContent(fsys fs.FS, alias, name string) (string, error) {
+	data, err := fs.ReadFile(fsys, path.Join(alias, name))
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+
+	content := strings.TrimSuffix(string(data), "\n")
+	return strings.TrimSuffix(content, "\r"), nil
+}
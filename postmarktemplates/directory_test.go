@@ -0,0 +1,114 @@
+package postmarktemplates_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/postmark"
+	"github.com/mrz1836/postmark/postmarktemplates"
+)
+
+// templateDir returns a directory with a layout and a template using it
+func templateDir() fstest.MapFS {
+	return fstest.MapFS{
+		"templates.json": {Data: []byte(`{"Templates": [
+			{"Alias": "welcome", "Name": "Welcome", "LayoutTemplate": "basic"},
+			{"Alias": "basic", "TemplateType": "Layout"}
+		]}`)},
+		"basic/content.html":   {Data: []byte("<html>{{{ @content }}}</html>\n")},
+		"welcome/subject.txt":  {Data: []byte("Welcome {{name}}\n")},
+		"welcome/content.html": {Data: []byte("<p>Hello {{name}}</p>\n")},
+		"welcome/content.txt":  {Data: []byte("Hello {{name}}\r\n")},
+	}
+}
+
+func TestLoad(t *testing.T) {
+	templates, err := postmarktemplates.Load(templateDir())
+	require.NoError(t, err)
+	assert.Equal(t, []postmark.Template{
+		{
+			Name:           "Welcome",
+			Alias:          "welcome",
+			Subject:        "Welcome {{name}}",
+			HTMLBody:       "<p>Hello {{name}}</p>",
+			TextBody:       "Hello {{name}}",
+			TemplateType:   "Standard",
+			LayoutTemplate: "basic",
+		},
+		{Name: "basic", Alias: "basic", HTMLBody: "<html>{{{ @content }}}</html>", TemplateType: "Layout"},
+	}, templates)
+}
+
+func TestLoadMetadata(t *testing.T) {
+	fsys := fstest.MapFS{
+		"welcome/template.json": {Data: []byte(`{"Alias": "welcome", "Name": "Welcome", "LayoutTemplate": "basic"}`)},
+		"welcome/subject.txt":   {Data: []byte("Welcome {{name}}\n")},
+		"welcome/content.txt":   {Data: []byte("Hello {{name}}\n")},
+		"basic/template.json":   {Data: []byte(`{"Alias": "basic", "TemplateType": "Layout"}`)},
+		"basic/content.html":    {Data: []byte("<html>{{{ @content }}}</html>\n")},
+		"notes/README.md":       {Data: []byte("not a template")},
+	}
+
+	templates, err := postmarktemplates.Load(fsys)
+	require.NoError(t, err)
+	assert.Equal(t, []postmark.Template{
+		{Name: "basic", Alias: "basic", HTMLBody: "<html>{{{ @content }}}</html>", TemplateType: "Layout"},
+		{
+			Name:           "Welcome",
+			Alias:          "welcome",
+			Subject:        "Welcome {{name}}",
+			TextBody:       "Hello {{name}}",
+			TemplateType:   "Standard",
+			LayoutTemplate: "basic",
+		},
+	}, templates)
+}
+
+func TestLoadInvalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest string
+		files    fstest.MapFS
+	}{
+		{name: "missing manifest"},
+		{name: "bad json", manifest: `{`},
+		{name: "no alias", manifest: `{"Templates": [{"Name": "x"}]}`},
+		{name: "alias with slash", manifest: `{"Templates": [{"Alias": "a/b"}]}`},
+		{name: "no subject", manifest: `{"Templates": [{"Alias": "welcome"}]}`},
+		{name: "no body", manifest: `{"Templates": [{"Alias": "welcome"}]}`,
+			files: fstest.MapFS{"welcome/subject.txt": {Data: []byte("Hi")}}},
+		{name: "unknown type", manifest: `{"Templates": [{"Alias": "basic", "TemplateType": "Other"}]}`,
+			files: fstest.MapFS{"basic/content.txt": {Data: []byte("x")}}},
+		{name: "layout with subject", manifest: `{"Templates": [{"Alias": "basic", "TemplateType": "Layout"}]}`,
+			files: fstest.MapFS{"basic/subject.txt": {Data: []byte("Hi")}, "basic/content.txt": {Data: []byte("x")}}},
+		{name: "duplicate alias", manifest: `{"Templates": [{"Alias": "basic", "TemplateType": "Layout"}, {"Alias": "basic", "TemplateType": "Layout"}]}`,
+			files: fstest.MapFS{"basic/content.txt": {Data: []byte("x")}}},
+		{name: "layout is a standard template", manifest: `{"Templates": [{"Alias": "a", "LayoutTemplate": "b"}, {"Alias": "b"}]}`,
+			files: fstest.MapFS{
+				"a/subject.txt": {Data: []byte("A")}, "a/content.txt": {Data: []byte("a")},
+				"b/subject.txt": {Data: []byte("B")}, "b/content.txt": {Data: []byte("b")},
+			}},
+		{name: "metadata of another alias", files: fstest.MapFS{
+			"a/template.json": {Data: []byte(`{"Alias": "b"}`)}, "a/subject.txt": {Data: []byte("A")}, "a/content.txt": {Data: []byte("a")},
+		}},
+		{name: "bad metadata json", files: fstest.MapFS{"a/template.json": {Data: []byte(`{`)}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fsys := fstest.MapFS{}
+			for name, file := range tt.files {
+				fsys[name] = file
+			}
+			if tt.manifest != "" {
+				fsys[postmarktemplates.ManifestFile] = &fstest.MapFile{Data: []byte(tt.manifest)}
+			}
+
+			_, err := postmarktemplates.Load(fsys)
+			require.ErrorIs(t, err, postmarktemplates.ErrInvalidManifest)
+		})
+	}
+}
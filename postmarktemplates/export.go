@@ -0,0 +1,110 @@
+package postmarktemplates
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/mrz1836/postmark"
+)
+
+// Permissions of the files and directories written by Export
+const (
+	dirPerm  = 0o755
+	filePerm = 0o644
+)
+
+// Export writes every template of a server that has an alias to a directory, one directory per alias with its
+// content and metadata files in the format read by Load, and returns the exported templates. Templates without an alias cannot be restored by alias and are skipped.
+func Export(ctx context.Context, api API, dir string) ([]postmark.Template, error) {
+	templates, err := Fetch(ctx, api)
+	if err != nil {
+		return nil, err
+	}
+	return templates, WriteDir(dir, templates)
+}
+
+// Restore recreates the templates of a directory written by Export on a server: templates are matched by alias,
+// missing ones are created and changed ones edited, layouts first so their templates can use them.
+// Templates of the server missing from the directory are left alone.
+func Restore(ctx context.Context, api API, fsys fs.FS) (Plan, error) {
+	return Sync(ctx, api, fsys, SyncOptions{})
+}
+
+// WriteDir writes each template to the directory named after its alias, with its ManifestEntry in a MetadataFile,
+// creating the directory if needed. Alias directories of a previous export that are not in templates are removed.
+// A directory with a ManifestFile is managed by hand and is refused.
+func WriteDir(dir string, templates []postmark.Template) error {
+	if _, err := os.Stat(filepath.Join(dir, ManifestFile)); err == nil {
+		return fmt.Errorf("%w: %s has a %s, export to another directory", ErrInvalidManifest, dir, ManifestFile)
+	}
+
+	exported := make(map[string]bool, len(templates))
+	for _, template := range templates {
+		if !validAlias(template.Alias) {
+			return fmt.Errorf("%w: alias %q is not a valid directory name", ErrInvalidManifest, template.Alias)
+		}
+		exported[template.Alias] = true
+
+		if err := writeTemplate(filepath.Join(dir, template.Alias), template); err != nil {
+			return err
+		}
+	}
+
+	previous, err := filepath.Glob(filepath.Join(dir, "*", MetadataFile))
+	if err != nil {
+		return err
+	}
+	for _, file := range previous {
+		if alias := filepath.Base(filepath.Dir(file)); !exported[alias] {
+			if err = os.RemoveAll(filepath.Dir(file)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// writeTemplate writes the metadata and content files of a template, replacing those of a previous export
+func writeTemplate(dir string, template postmark.Template) error {
+	if err := os.MkdirAll(dir, dirPerm); err != nil {
+		return err
+	}
+
+	metadata, err := json.MarshalIndent(ManifestEntry{
+		Alias:          template.Alias,
+		Name:           template.Name,
+		TemplateType:   template.TemplateType,
+		LayoutTemplate: template.LayoutTemplate,
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err = os.WriteFile(filepath.Join(dir, MetadataFile), append(metadata, '\n'), filePerm); err != nil {
+		return err
+	}
+
+	files := []struct{ name, content string }{
+		{SubjectFile, template.Subject},
+		{HTMLFile, template.HTMLBody},
+		{TextFile, template.TextBody},
+	}
+	for _, file := range files {
+		name := filepath.Join(dir, file.name)
+		if file.content == "" {
+			if err = os.Remove(name); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return err
+			}
+			continue
+		}
+		// readContent drops the final line break, so one is added to read the content back unchanged
+		if err = os.WriteFile(name, []byte(file.content+"\n"), filePerm); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package postmarktemplates_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/postmark"
+	"github.com/mrz1836/postmark/postmarktemplates"
+	"github.com/mrz1836/postmark/postmarktest"
+)
+
+func TestExportRestore(t *testing.T) {
+	ctx := context.Background()
+	source := postmarktest.NewServer()
+	t.Cleanup(source.Close)
+	source.AddTemplate(postmark.Template{Name: "Basic", Alias: "basic", TemplateType: "Layout", HTMLBody: "<html>{{{ @content }}}</html>\n"})
+	source.AddTemplate(postmark.Template{
+		Name: "Welcome", Alias: "welcome", Subject: "Welcome {{name}}", TextBody: "Hello\n{{name}}", LayoutTemplate: "basic",
+	})
+	source.AddTemplate(postmark.Template{Name: "No alias", Subject: "x", TextBody: "x"})
+
+	dir := t.TempDir()
+	exported, err := postmarktemplates.Export(ctx, source.Client(), dir)
+	require.NoError(t, err)
+	require.Len(t, exported, 2)
+
+	data, err := os.ReadFile(filepath.Join(dir, "welcome", "content.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "Hello\n{{name}}\n", string(data))
+	assert.NoFileExists(t, filepath.Join(dir, "welcome", "content.html"))
+	data, err = os.ReadFile(filepath.Join(dir, "welcome", "template.json"))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"Alias": "welcome", "Name": "Welcome", "TemplateType": "Standard", "LayoutTemplate": "basic"}`, string(data))
+	assert.NoFileExists(t, filepath.Join(dir, "templates.json"))
+
+	destination := postmarktest.NewServer()
+	t.Cleanup(destination.Close)
+	plan, err := postmarktemplates.Restore(ctx, destination.Client(), os.DirFS(dir))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Create basic", "Create welcome"}, actions(plan))
+
+	restored, err := postmarktemplates.Fetch(ctx, destination.Client())
+	require.NoError(t, err)
+	require.Len(t, restored, 2)
+	for n, template := range restored {
+		assert.Empty(t, postmarktemplates.DiffTemplates(exported[n], template), template.Alias)
+	}
+
+	plan, err = postmarktemplates.Restore(ctx, destination.Client(), os.DirFS(dir))
+	require.NoError(t, err)
+	assert.False(t, plan.HasChanges(), "restoring twice changes nothing")
+}
+
+func TestWriteDirInvalidAlias(t *testing.T) {
+	err := postmarktemplates.WriteDir(t.TempDir(), []postmark.Template{{Alias: "../escape", Subject: "x", TextBody: "x"}})
+	require.ErrorIs(t, err, postmarktemplates.ErrInvalidManifest)
+}
+
+func TestWriteDirRemovesPreviousAliases(t *testing.T) {
+	dir := t.TempDir()
+	welcome := postmark.Template{Name: "Welcome", Alias: "welcome", TemplateType: "Standard", Subject: "Hi", TextBody: "Hello"}
+	legacy := postmark.Template{Name: "Legacy", Alias: "legacy", TemplateType: "Standard", Subject: "Old", TextBody: "Old"}
+	require.NoError(t, postmarktemplates.WriteDir(dir, []postmark.Template{welcome, legacy}))
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "notes"), 0o755))
+
+	require.NoError(t, postmarktemplates.WriteDir(dir, []postmark.Template{welcome}))
+	assert.NoDirExists(t, filepath.Join(dir, "legacy"))
+	assert.DirExists(t, filepath.Join(dir, "notes"), "directories without metadata are left alone")
+
+	loaded, err := postmarktemplates.Load(os.DirFS(dir))
+	require.NoError(t, err)
+	assert.Equal(t, []postmark.Template{welcome}, loaded)
+}
+
+func TestWriteDirRefusesManifestDirectory(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "templates.json"), []byte(`{"Templates": []}`), 0o644))
+
+	err := postmarktemplates.WriteDir(dir, []postmark.Template{{Alias: "welcome", Subject: "x", TextBody: "x"}})
+	require.ErrorIs(t, err, postmarktemplates.ErrInvalidManifest)
+}
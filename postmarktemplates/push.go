@@ -0,0 +1,87 @@
+package postmarktemplates
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/mrz1836/postmark"
+)
+
+// Pusher pushes templates between servers, *postmark.Client with an account token implements it
+type Pusher interface {
+	PushTemplates(ctx context.Context, request postmark.PushTemplatesRequest) (postmark.PushTemplatesResponse, error)
+}
+
+// TemplateGetter gets a template by ID or alias, *postmark.Client with a server token implements it
+type TemplateGetter interface {
+	GetTemplate(ctx context.Context, templateID string) (postmark.Template, error)
+}
+
+// PushChange is what a push would do to one template of the destination server
+type PushChange struct {
+	// Pushed is the row returned by Postmark for the template, with its Action
+	Pushed postmark.PushedTemplate
+	// Source is the template on the source server
+	Source postmark.Template
+	// Destination is the template on the destination server, zero if the push creates it
+	Destination postmark.Template
+	// Diffs are the fields that the push changes on the destination server
+	Diffs []FieldDiff
+}
+
+// PushPreview is the result of a push dry run with the content that would change
+type PushPreview struct {
+	// Changes: one per template Postmark would push
+	Changes []PushChange
+}
+
+// String describes the preview for review, with the line diffs of each pushed template
+func (p PushPreview) String() string {
+	var b strings.Builder
+	for _, change := range p.Changes {
+		fmt.Fprintf(&b, "%s %q (%s)\n", change.Pushed.Action, change.Pushed.Alias, change.Source.Name)
+		writeDiffs(&b, change.Diffs)
+	}
+	fmt.Fprintf(&b, "%d templates to push\n", len(p.Changes))
+	return b.String()
+}
+
+// PreviewPush runs PushTemplates without performing changes, then fetches the source and destination version
+// of each pushed template to report the content the push would change.
+// The account client runs the push, source and destination are clients with the token of each server.
+func PreviewPush(ctx context.Context, account Pusher, source, destination TemplateGetter,
+	request postmark.PushTemplatesRequest,
+) (PushPreview, error) {
+	request.PerformChanges = false
+	res, err := account.PushTemplates(ctx, request)
+	if err != nil {
+		return PushPreview{}, err
+	}
+
+	preview := PushPreview{Changes: make([]PushChange, 0, len(res.Templates))}
+	for _, pushed := range res.Templates {
+		ref := pushed.Alias
+		if ref == "" {
+			ref = strconv.FormatInt(pushed.TemplateID, 10)
+		}
+
+		change := PushChange{Pushed: pushed}
+		if change.Source, err = source.GetTemplate(ctx, ref); err != nil {
+			return PushPreview{}, fmt.Errorf("postmarktemplates: get source %q: %w", ref, err)
+		}
+		// templates are pushed by alias, a template created by the push does not exist on the destination yet
+		if pushed.Alias != "" {
+			change.Destination, err = destination.GetTemplate(ctx, pushed.Alias)
+			if err != nil && !errors.Is(err, postmark.ErrTemplateNotFound) {
+				return PushPreview{}, fmt.Errorf("postmarktemplates: get destination %q: %w", pushed.Alias, err)
+			}
+		}
+
+		change.Diffs = DiffTemplates(change.Destination, change.Source)
+		preview.Changes = append(preview.Changes, change)
+	}
+	return preview, nil
+}
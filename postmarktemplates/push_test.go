@@ -0,0 +1,63 @@
+package postmarktemplates_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/postmark"
+	"github.com/mrz1836/postmark/postmarktemplates"
+	"github.com/mrz1836/postmark/postmarktest"
+)
+
+var _ postmarktemplates.Pusher = (*postmark.Client)(nil)
+
+// fakePusher returns a fixed push response and records the request
+type fakePusher struct {
+	request  postmark.PushTemplatesRequest
+	response postmark.PushTemplatesResponse
+}
+
+func (p *fakePusher) PushTemplates(_ context.Context, request postmark.PushTemplatesRequest) (postmark.PushTemplatesResponse, error) {
+	p.request = request
+	return p.response, nil
+}
+
+func TestPreviewPush(t *testing.T) {
+	ctx := context.Background()
+	staging := postmarktest.NewServer()
+	t.Cleanup(staging.Close)
+	production := postmarktest.NewServer()
+	t.Cleanup(production.Close)
+
+	welcome := staging.AddTemplate(postmark.Template{Name: "Welcome", Alias: "welcome", Subject: "Welcome {{name}}", TextBody: "Hi\nthere"})
+	receipt := staging.AddTemplate(postmark.Template{Name: "Receipt", Alias: "receipt", Subject: "Receipt", TextBody: "Paid"})
+	production.AddTemplate(postmark.Template{Name: "Welcome", Alias: "welcome", Subject: "Welcome", TextBody: "Hi\nthere"})
+
+	pusher := &fakePusher{response: postmark.PushTemplatesResponse{TotalCount: 2, Templates: []postmark.PushedTemplate{
+		{TemplateID: welcome.TemplateID, Name: "Welcome", Alias: "welcome", Action: "Edit"},
+		{TemplateID: receipt.TemplateID, Name: "Receipt", Alias: "receipt", Action: "Create"},
+	}}}
+	preview, err := postmarktemplates.PreviewPush(ctx, pusher, staging.Client(), production.Client(), postmark.PushTemplatesRequest{
+		SourceServerID: 1, DestinationServerID: 2, PerformChanges: true,
+	})
+	require.NoError(t, err)
+	assert.False(t, pusher.request.PerformChanges, "a preview never performs changes")
+
+	require.Len(t, preview.Changes, 2)
+	edit := preview.Changes[0]
+	assert.Equal(t, "Welcome {{name}}", edit.Source.Subject)
+	assert.Equal(t, "Welcome", edit.Destination.Subject)
+	if assert.Len(t, edit.Diffs, 1) {
+		assert.Equal(t, "Subject", edit.Diffs[0].Field)
+	}
+
+	create := preview.Changes[1]
+	assert.Zero(t, create.Destination)
+	assert.Len(t, create.Diffs, 3, "Name, Subject and TextBody are new")
+
+	assert.Contains(t, preview.String(), "Edit \"welcome\" (Welcome)\n  Subject:\n    - Welcome\n    + Welcome {{name}}\n")
+	assert.Contains(t, preview.String(), "2 templates to push\n")
+}
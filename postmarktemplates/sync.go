@@ -0,0 +1,241 @@
+package postmarktemplates
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"iter"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/mrz1836/postmark"
+)
+
+var (
+	// ErrTemplateTypeChanged is returned when a template would change between Standard and Layout, which Postmark refuses
+	ErrTemplateTypeChanged = errors.New("postmarktemplates: template type cannot be changed")
+
+	// ErrUnknownLayout is returned when a template uses a layout that is neither desired nor kept on the server
+	ErrUnknownLayout = errors.New("postmarktemplates: unknown layout")
+)
+
+// API is the part of the Postmark client used to manage templates, *postmark.Client implements it
+type API interface {
+	Templates(ctx context.Context, templateType, layoutTemplate string) iter.Seq2[postmark.TemplateInfo, error]
+	GetTemplate(ctx context.Context, templateID string) (postmark.Template, error)
+	CreateTemplate(ctx context.Context, template postmark.Template) (postmark.TemplateInfo, error)
+	EditTemplate(ctx context.Context, templateID string, template postmark.Template) (postmark.TemplateInfo, error)
+	DeleteTemplate(ctx context.Context, templateID string) error
+}
+
+// Action is what a sync does to a template
+type Action string
+
+const (
+	// ActionCreate creates a template missing from the server
+	ActionCreate Action = "Create"
+	// ActionUpdate edits a template whose content differs
+	ActionUpdate Action = "Update"
+	// ActionDelete deletes a template missing from the directory
+	ActionDelete Action = "Delete"
+	// ActionUnchanged leaves a template that already matches
+	ActionUnchanged Action = "Unchanged"
+)
+
+// Change is the planned action for one template
+type Change struct {
+	// Action to take
+	Action Action
+	// Alias of the template
+	Alias string
+	// TemplateType: Standard or Layout
+	TemplateType string
+	// Current is the template on the server, zero for created templates
+	Current postmark.Template
+	// Desired is the template from the directory, zero for deleted templates
+	Desired postmark.Template
+	// Diffs are the changed fields of updated templates
+	Diffs []FieldDiff
+}
+
+// Plan is the list of changes that make a server match a directory, in the order they are applied:
+// layouts are created and updated before the standard templates using them, and deleted after them.
+type Plan struct {
+	Changes []Change
+}
+
+// HasChanges reports whether applying the plan changes anything
+func (p Plan) HasChanges() bool {
+	return slices.ContainsFunc(p.Changes, func(change Change) bool { return change.Action != ActionUnchanged })
+}
+
+// Count returns the number of changes with the action
+func (p Plan) Count(action Action) int {
+	count := 0
+	for _, change := range p.Changes {
+		if change.Action == action {
+			count++
+		}
+	}
+	return count
+}
+
+// String describes the plan for review, with the line diffs of updated templates
+func (p Plan) String() string {
+	var b strings.Builder
+	for _, change := range p.Changes {
+		switch change.Action {
+		case ActionCreate:
+			fmt.Fprintf(&b, "+ create %s %q\n", strings.ToLower(change.TemplateType), change.Alias)
+		case ActionDelete:
+			fmt.Fprintf(&b, "- delete %s %q\n", strings.ToLower(change.TemplateType), change.Alias)
+		case ActionUpdate:
+			fmt.Fprintf(&b, "~ update %s %q\n", strings.ToLower(change.TemplateType), change.Alias)
+			writeDiffs(&b, change.Diffs)
+		case ActionUnchanged:
+		}
+	}
+	fmt.Fprintf(&b, "%d to create, %d to update, %d to delete, %d unchanged\n",
+		p.Count(ActionCreate), p.Count(ActionUpdate), p.Count(ActionDelete), p.Count(ActionUnchanged))
+	return b.String()
+}
+
+// SyncOptions configures Sync
+type SyncOptions struct {
+	// DryRun computes the plan without applying it
+	DryRun bool
+	// Delete removes the templates of the server that are not in the directory,
+	// by default they are left alone. Templates without an alias are never deleted.
+	Delete bool
+}
+
+// Sync makes the templates of a server match a template directory and returns the plan it applied,
+// or would apply with DryRun
+func Sync(ctx context.Context, api API, fsys fs.FS, opts SyncOptions) (Plan, error) {
+	desired, err := Load(fsys)
+	if err != nil {
+		return Plan{}, err
+	}
+
+	plan, err := PlanSync(ctx, api, desired, opts.Delete)
+	if err != nil || opts.DryRun {
+		return plan, err
+	}
+	return plan, Apply(ctx, api, plan)
+}
+
+// PlanSync compares the desired templates with the templates of the server, matched by alias.
+// Every layout used by a desired template must be desired too, or stay on the server.
+func PlanSync(ctx context.Context, api API, desired []postmark.Template, deleteMissing bool) (Plan, error) {
+	current, err := Fetch(ctx, api)
+	if err != nil {
+		return Plan{}, err
+	}
+	byAlias := make(map[string]postmark.Template, len(current))
+	for _, template := range current {
+		byAlias[template.Alias] = template
+	}
+
+	var changes []Change
+	wanted := make(map[string]bool, len(desired))
+	for _, template := range desired {
+		wanted[template.Alias] = true
+		change := Change{Action: ActionCreate, Alias: template.Alias, TemplateType: template.TemplateType, Desired: template}
+
+		if existing, ok := byAlias[template.Alias]; ok {
+			if existing.TemplateType != template.TemplateType {
+				return Plan{}, fmt.Errorf("%w: %q is a %s on the server", ErrTemplateTypeChanged, template.Alias, existing.TemplateType)
+			}
+			change.Current = existing
+			change.Diffs = DiffTemplates(existing, template)
+			change.Action = ActionUpdate
+			if len(change.Diffs) == 0 {
+				change.Action = ActionUnchanged
+			}
+		}
+		changes = append(changes, change)
+	}
+
+	layouts := make(map[string]bool)
+	for _, template := range desired {
+		if template.TemplateType == TypeLayout {
+			layouts[template.Alias] = true
+		}
+	}
+	for _, template := range current {
+		if template.TemplateType == TypeLayout && !deleteMissing {
+			layouts[template.Alias] = true
+		}
+		if deleteMissing && !wanted[template.Alias] {
+			changes = append(changes, Change{Action: ActionDelete, Alias: template.Alias, TemplateType: template.TemplateType, Current: template})
+		}
+	}
+	for _, template := range desired {
+		if template.LayoutTemplate != "" && !layouts[template.LayoutTemplate] {
+			return Plan{}, fmt.Errorf("%w: %q uses layout %q", ErrUnknownLayout, template.Alias, template.LayoutTemplate)
+		}
+	}
+
+	slices.SortStableFunc(changes, func(a, b Change) int { return cmp.Compare(applyOrder(a), applyOrder(b)) })
+	return Plan{Changes: changes}, nil
+}
+
+// applyOrder ranks a change: layouts are written first and deleted last
+func applyOrder(change Change) int {
+	layout := change.TemplateType == TypeLayout
+	switch {
+	case change.Action != ActionDelete && layout:
+		return 0
+	case change.Action != ActionDelete:
+		return 1
+	case !layout:
+		return 2
+	default:
+		return 3
+	}
+}
+
+// Apply creates, updates and deletes templates in plan order, it stops at the first error
+func Apply(ctx context.Context, api API, plan Plan) error {
+	for _, change := range plan.Changes {
+		var err error
+		switch change.Action {
+		case ActionCreate:
+			_, err = api.CreateTemplate(ctx, change.Desired)
+		case ActionUpdate:
+			_, err = api.EditTemplate(ctx, change.Alias, change.Desired)
+		case ActionDelete:
+			err = api.DeleteTemplate(ctx, change.Alias)
+		case ActionUnchanged:
+		}
+		if err != nil {
+			return fmt.Errorf("postmarktemplates: %s %q: %w", strings.ToLower(string(change.Action)), change.Alias, err)
+		}
+	}
+	return nil
+}
+
+// Fetch returns every template of the server that has an alias, with its content, ordered by alias
+func Fetch(ctx context.Context, api API) ([]postmark.Template, error) {
+	var templates []postmark.Template
+	for info, err := range api.Templates(ctx, "", "") {
+		if err != nil {
+			return nil, err
+		}
+		if info.Alias == "" {
+			continue
+		}
+
+		template, err := api.GetTemplate(ctx, strconv.FormatInt(info.TemplateID, 10))
+		if err != nil {
+			return nil, fmt.Errorf("postmarktemplates: get %q: %w", info.Alias, err)
+		}
+		templates = append(templates, template)
+	}
+
+	slices.SortFunc(templates, func(a, b postmark.Template) int { return strings.Compare(a.Alias, b.Alias) })
+	return templates, nil
+}
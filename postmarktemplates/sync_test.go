@@ -0,0 +1,115 @@
+package postmarktemplates_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/postmark"
+	"github.com/mrz1836/postmark/postmarktemplates"
+	"github.com/mrz1836/postmark/postmarktest"
+)
+
+var _ postmarktemplates.API = (*postmark.Client)(nil)
+
+// actions returns the action of each change by alias, in plan order
+func actions(plan postmarktemplates.Plan) []string {
+	var list []string
+	for _, change := range plan.Changes {
+		list = append(list, string(change.Action)+" "+change.Alias)
+	}
+	return list
+}
+
+func TestSync(t *testing.T) {
+	ctx := context.Background()
+	pm := postmarktest.NewServer()
+	t.Cleanup(pm.Close)
+	client := pm.Client()
+	fsys := templateDir()
+
+	plan, err := postmarktemplates.Sync(ctx, client, fsys, postmarktemplates.SyncOptions{DryRun: true})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Create basic", "Create welcome"}, actions(plan))
+	assert.True(t, plan.HasChanges())
+	templates, err := postmarktemplates.Fetch(ctx, client)
+	require.NoError(t, err)
+	assert.Empty(t, templates, "dry run changes nothing")
+
+	_, err = postmarktemplates.Sync(ctx, client, fsys, postmarktemplates.SyncOptions{})
+	require.NoError(t, err)
+	welcome, err := client.GetTemplate(ctx, "welcome")
+	require.NoError(t, err)
+	assert.Equal(t, "basic", welcome.LayoutTemplate)
+	assert.Equal(t, "Welcome {{name}}", welcome.Subject)
+
+	plan, err = postmarktemplates.Sync(ctx, client, fsys, postmarktemplates.SyncOptions{})
+	require.NoError(t, err)
+	assert.False(t, plan.HasChanges())
+	assert.Equal(t, "0 to create, 0 to update, 0 to delete, 2 unchanged\n", plan.String())
+
+	fsys["welcome/subject.txt"].Data = []byte("Welcome aboard {{name}}")
+	plan, err = postmarktemplates.Sync(ctx, client, fsys, postmarktemplates.SyncOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Unchanged basic", "Update welcome"}, actions(plan))
+	assert.Contains(t, plan.String(), "~ update standard \"welcome\"\n  Subject:\n    - Welcome {{name}}\n    + Welcome aboard {{name}}\n")
+	welcome, err = client.GetTemplate(ctx, "welcome")
+	require.NoError(t, err)
+	assert.Equal(t, "Welcome aboard {{name}}", welcome.Subject)
+}
+
+func TestSyncDelete(t *testing.T) {
+	ctx := context.Background()
+	pm := postmarktest.NewServer()
+	t.Cleanup(pm.Close)
+	client := pm.Client()
+
+	pm.AddTemplate(postmark.Template{Name: "Old layout", Alias: "old", TemplateType: "Layout", HTMLBody: "{{{@content}}}"})
+	pm.AddTemplate(postmark.Template{Name: "Old", Alias: "legacy", Subject: "x", TextBody: "x", LayoutTemplate: "old"})
+	pm.AddTemplate(postmark.Template{Name: "No alias", Subject: "x", TextBody: "x"})
+
+	plan, err := postmarktemplates.Sync(ctx, client, templateDir(), postmarktemplates.SyncOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Create basic", "Create welcome"}, actions(plan), "templates are kept by default")
+
+	plan, err = postmarktemplates.Sync(ctx, client, templateDir(), postmarktemplates.SyncOptions{Delete: true})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Unchanged basic", "Unchanged welcome", "Delete legacy", "Delete old"}, actions(plan))
+
+	_, total, err := client.GetTemplates(ctx, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), total, "templates without an alias are kept")
+}
+
+func TestSyncTemplateTypeChanged(t *testing.T) {
+	ctx := context.Background()
+	pm := postmarktest.NewServer()
+	t.Cleanup(pm.Close)
+	pm.AddTemplate(postmark.Template{Name: "basic", Alias: "basic", Subject: "x", TextBody: "x"})
+
+	_, err := postmarktemplates.Sync(ctx, pm.Client(), templateDir(), postmarktemplates.SyncOptions{})
+	require.ErrorIs(t, err, postmarktemplates.ErrTemplateTypeChanged)
+}
+
+func TestPlanSyncUnknownLayout(t *testing.T) {
+	ctx := context.Background()
+	pm := postmarktest.NewServer()
+	t.Cleanup(pm.Close)
+	client := pm.Client()
+	pm.AddTemplate(postmark.Template{Name: "Server layout", Alias: "server", TemplateType: "Layout", HTMLBody: "{{{@content}}}"})
+
+	welcome := postmark.Template{Name: "Welcome", Alias: "welcome", Subject: "x", TextBody: "x", LayoutTemplate: "missing"}
+	_, err := postmarktemplates.PlanSync(ctx, client, []postmark.Template{welcome}, false)
+	require.ErrorIs(t, err, postmarktemplates.ErrUnknownLayout)
+
+	welcome.LayoutTemplate = "server"
+	plan, err := postmarktemplates.PlanSync(ctx, client, []postmark.Template{welcome}, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Create welcome"}, actions(plan))
+
+	// The layout would be deleted by the same sync
+	_, err = postmarktemplates.PlanSync(ctx, client, []postmark.Template{welcome}, true)
+	require.ErrorIs(t, err, postmarktemplates.ErrUnknownLayout)
+}
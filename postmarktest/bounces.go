@@ -0,0 +1,184 @@
+package postmarktest
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/mrz1836/postmark"
+)
+
+// hardBounceType is the bounce type that deactivates the recipient
+const hardBounceType = "HardBounce"
+
+// AddBounce records a bounce, as if a message to bounce.Email bounced, and returns it with its ID set.
+// Inactive bounces suppress the address on the bounce's message stream.
+func (s *Server) AddBounce(bounce postmark.Bounce) postmark.Bounce {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	bounce.ID = s.newID()
+	bounce.RecordType = postmark.RecordTypeBounce
+	if bounce.Type == "" {
+		bounce.Type = hardBounceType
+		bounce.Inactive = true
+	}
+	if bounce.MessageStream == "" {
+		bounce.MessageStream = defaultMessageStream
+	}
+	if bounce.BouncedAt.IsZero() {
+		bounce.BouncedAt = s.Now()
+	}
+	bounce.CanActivate = bounce.Inactive && bounce.Type != "SpamComplaint"
+	s.bounces[bounce.ID] = &bounce
+
+	if bounce.Inactive {
+		reason := postmark.HardBounceReason
+		if bounce.Type == "SpamComplaint" {
+			reason = postmark.SpamComplaintReason
+		}
+		s.suppress(bounce.MessageStream, postmark.Suppression{
+			EmailAddress:      bounce.Email,
+			SuppressionReason: reason,
+			Origin:            postmark.RecipientOrigin,
+			CreatedAt:         bounce.BouncedAt,
+		})
+	}
+	return bounce
+}
+
+// sortedBounces returns the bounces newest first, like the bounces search
+func (s *Server) sortedBounces() []*postmark.Bounce {
+	return slices.SortedFunc(maps.Values(s.bounces), func(a, b *postmark.Bounce) int {
+		if c := b.BouncedAt.Compare(a.BouncedAt); c != 0 {
+			return c
+		}
+		return cmp.Compare(b.ID, a.ID)
+	})
+}
+
+// bounce returns the bounce with the ID in the path, answering with an error when there is none
+func (s *Server) bounce(w http.ResponseWriter, r *http.Request) (*postmark.Bounce, bool) {
+	id, ok := pathID(w, r, "bounce", "Bounce")
+	if !ok {
+		return nil, false
+	}
+	bounce, ok := s.bounces[id]
+	if !ok {
+		writeNotFound(w, fmt.Sprintf("Bounce '%d' was not found.", id))
+		return nil, false
+	}
+	return bounce, true
+}
+
+// deliveryStats handles GET /deliverystats
+func (s *Server) deliveryStats(w http.ResponseWriter, _ *http.Request) {
+	counts := make(map[string]int64)
+	var inactive int64
+	for _, bounce := range s.bounces {
+		counts[bounce.Type]++
+		if bounce.Inactive {
+			inactive++
+		}
+	}
+
+	stats := postmark.DeliveryStats{InactiveMails: inactive, Bounces: []postmark.BounceType{}}
+	for _, bounceType := range slices.Sorted(maps.Keys(counts)) {
+		stats.Bounces = append(stats.Bounces, postmark.BounceType{Type: bounceType, Name: bounceType, Count: counts[bounceType]})
+	}
+	writeJSON(w, http.StatusOK, stats)
+}
+
+// listBounces handles GET /bounces
+func (s *Server) listBounces(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	matched := make([]postmark.Bounce, 0)
+	for _, bounce := range s.sortedBounces() {
+		if value := query.Get("type"); value != "" && value != bounce.Type {
+			continue
+		}
+		if value := query.Get("inactive"); value != "" && value != fmt.Sprint(bounce.Inactive) {
+			continue
+		}
+		if value := query.Get("emailFilter"); value != "" && !strings.Contains(strings.ToLower(bounce.Email), strings.ToLower(value)) {
+			continue
+		}
+		if value := query.Get("tag"); value != "" && value != bounce.Tag {
+			continue
+		}
+		if value := query.Get("messageID"); value != "" && value != bounce.MessageID {
+			continue
+		}
+		if value := query.Get("messagestream"); value != "" && value != bounce.MessageStream {
+			continue
+		}
+		if !inDateRange(bounce.BouncedAt, query.Get("fromdate"), query.Get("todate")) {
+			continue
+		}
+		matched = append(matched, *bounce)
+	}
+
+	count, offset := page(r)
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"TotalCount": len(matched),
+		"Bounces":    paginate(matched, count, offset),
+	})
+}
+
+// bouncedTags handles GET /bounces/tags
+func (s *Server) bouncedTags(w http.ResponseWriter, _ *http.Request) {
+	tags := make([]string, 0)
+	for _, bounce := range s.bounces {
+		if bounce.Tag != "" && !slices.Contains(tags, bounce.Tag) {
+			tags = append(tags, bounce.Tag)
+		}
+	}
+	slices.Sort(tags)
+	writeJSON(w, http.StatusOK, tags)
+}
+
+// getBounce handles GET /bounces/{bounce}
+func (s *Server) getBounce(w http.ResponseWriter, r *http.Request) {
+	if bounce, ok := s.bounce(w, r); ok {
+		writeJSON(w, http.StatusOK, bounce)
+	}
+}
+
+// getBounceDump handles GET /bounces/{bounce}/dump
+func (s *Server) getBounceDump(w http.ResponseWriter, r *http.Request) {
+	bounce, ok := s.bounce(w, r)
+	if !ok {
+		return
+	}
+
+	body := ""
+	if bounce.DumpAvailable {
+		body = bounce.Content
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"Body": body})
+}
+
+// activateBounce handles PUT /bounces/{bounce}/activate
+func (s *Server) activateBounce(w http.ResponseWriter, r *http.Request) {
+	bounce, ok := s.bounce(w, r)
+	if !ok {
+		return
+	}
+	if bounce.Inactive && !bounce.CanActivate {
+		writeInvalid(w, fmt.Sprintf("Bounce '%d' cannot be activated.", bounce.ID))
+		return
+	}
+
+	bounce.Inactive = false
+	bounce.CanActivate = false
+	delete(s.suppressions[bounce.MessageStream], strings.ToLower(bounce.Email))
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"Message": "OK",
+		"Bounce":  bounce,
+	})
+}
@@ -0,0 +1,89 @@
+package postmarktest_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/postmark"
+)
+
+func TestBounces(t *testing.T) {
+	srv, client := newTestServer(t)
+	ctx := context.Background()
+
+	hard := srv.AddBounce(postmark.Bounce{
+		Email:         "gone@example.com",
+		Tag:           "welcome",
+		BouncedAt:     time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC),
+		DumpAvailable: true,
+		Content:       "Return-Path: <>",
+	})
+	soft := srv.AddBounce(postmark.Bounce{
+		Type:      "SoftBounce",
+		Email:     "full@example.com",
+		Tag:       "digest",
+		BouncedAt: time.Date(2024, 3, 2, 10, 0, 0, 0, time.UTC),
+	})
+
+	bounces, total, err := client.GetBounces(ctx, 10, 0, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.Equal(t, soft.ID, bounces[0].ID, "newest first")
+
+	inactive := true
+	bounces, total, err = client.GetBouncesFiltered(ctx, 10, 0, postmark.BounceFilter{
+		Inactive: &inactive,
+		FromDate: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		ToDate:   time.Date(2024, 3, 1, 23, 59, 59, 0, time.UTC),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, hard.ID, bounces[0].ID)
+
+	stats, err := client.GetDeliveryStats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), stats.InactiveMails)
+	assert.Len(t, stats.Bounces, 2)
+
+	tags, err := client.GetBouncedTags(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"digest", "welcome"}, tags)
+
+	dump, err := client.GetBounceDump(ctx, hard.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Return-Path: <>", dump)
+
+	_, err = client.SendEmail(ctx, postmark.Email{From: "a@example.com", To: "gone@example.com", TextBody: "Hi"})
+	require.ErrorIs(t, err, postmark.ErrInactiveRecipient)
+
+	activated, message, err := client.ActivateBounce(ctx, hard.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "OK", message)
+	assert.False(t, activated.Inactive)
+
+	_, err = client.SendEmail(ctx, postmark.Email{From: "a@example.com", To: "gone@example.com", TextBody: "Hi"})
+	require.NoError(t, err)
+
+	_, err = client.GetBounce(ctx, 12345)
+	require.Error(t, err)
+}
+
+func TestBouncesIterator(t *testing.T) {
+	srv, client := newTestServer(t)
+
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	for i := range 1200 {
+		srv.AddBounce(postmark.Bounce{Type: "SoftBounce", Email: "x@example.com", BouncedAt: start.Add(time.Duration(i) * time.Minute)})
+	}
+
+	count := 0
+	for _, err := range client.Bounces(context.Background(), nil) {
+		require.NoError(t, err)
+		count++
+	}
+	assert.Equal(t, 1200, count)
+}
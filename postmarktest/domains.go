@@ -0,0 +1,192 @@
+package postmarktest
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/mrz1836/postmark"
+)
+
+// VerifyDomain marks the SPF, DKIM and Return-Path DNS records of a domain as verified,
+// as if its DNS had been set up. It reports whether the domain exists.
+func (s *Server) VerifyDomain(name string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, domain := range s.domains {
+		if strings.EqualFold(domain.Name, name) {
+			verifyDomainDetails(domain)
+			return true
+		}
+	}
+	return false
+}
+
+// verifyDomainDetails marks the DNS records of a domain as verified and activates pending DKIM keys
+func verifyDomainDetails(domain *postmark.DomainDetails) {
+	domain.SPFVerified = true
+	domain.ReturnPathDomainVerified = domain.ReturnPathDomain != ""
+	if domain.DKIMPendingHost != "" {
+		domain.DKIMRevokedHost, domain.DKIMRevokedTextValue = domain.DKIMHost, domain.DKIMTextValue
+		domain.DKIMHost, domain.DKIMTextValue = domain.DKIMPendingHost, domain.DKIMPendingTextValue
+		domain.DKIMPendingHost, domain.DKIMPendingTextValue = "", ""
+		domain.SafeToRemoveRevokedKeyFromDNS = domain.DKIMRevokedHost != ""
+	}
+	domain.DKIMVerified = true
+	domain.DKIMUpdateStatus = "Verified"
+}
+
+// domainSummary returns the subset of the domain returned by list endpoints
+func domainSummary(domain *postmark.DomainDetails) postmark.Domain {
+	return postmark.Domain{
+		Name:                     domain.Name,
+		SPFVerified:              domain.SPFVerified,
+		DKIMVerified:             domain.DKIMVerified,
+		WeakDKIM:                 domain.WeakDKIM,
+		ReturnPathDomainVerified: domain.ReturnPathDomainVerified,
+		ID:                       domain.ID,
+	}
+}
+
+// pendingDKIM sets a new pending DKIM key on the domain
+func pendingDKIM(domain *postmark.DomainDetails, id int64) {
+	domain.DKIMPendingHost = fmt.Sprintf("%dpm._domainkey.%s", id, domain.Name)
+	domain.DKIMPendingTextValue = fmt.Sprintf("k=rsa;p=POSTMARKTEST%d", id)
+	domain.DKIMUpdateStatus = "Pending"
+}
+
+// checkReturnPath validates a custom Return-Path domain, returning a message describing the problem
+func checkReturnPath(domain, returnPath string) string {
+	if returnPath != "" && !strings.HasSuffix(strings.ToLower(returnPath), "."+strings.ToLower(domain)) {
+		return fmt.Sprintf("The 'ReturnPathDomain' '%s' must be a subdomain of '%s'.", returnPath, domain)
+	}
+	return ""
+}
+
+// domain returns the domain with the ID in the path, answering with an error when there is none
+func (s *Server) domain(w http.ResponseWriter, r *http.Request) (*postmark.DomainDetails, bool) {
+	id, ok := pathID(w, r, "domain", "Domain")
+	if !ok {
+		return nil, false
+	}
+	domain, ok := s.domains[id]
+	if !ok {
+		writeNotFound(w, fmt.Sprintf("Domain '%d' was not found.", id))
+		return nil, false
+	}
+	return domain, true
+}
+
+// listDomains handles GET /domains
+func (s *Server) listDomains(w http.ResponseWriter, r *http.Request) {
+	domains := make([]postmark.Domain, 0, len(s.domains))
+	for _, domain := range slices.SortedFunc(maps.Values(s.domains), func(a, b *postmark.DomainDetails) int {
+		return cmp.Compare(a.ID, b.ID)
+	}) {
+		domains = append(domains, domainSummary(domain))
+	}
+
+	count, offset := page(r)
+	writeJSON(w, http.StatusOK, postmark.DomainsList{
+		TotalCount: len(domains),
+		Domains:    paginate(domains, count, offset),
+	})
+}
+
+// getDomain handles GET /domains/{domain} along with the DKIM and Return-Path verification endpoints,
+// which report the current state (see VerifyDomain)
+func (s *Server) getDomain(w http.ResponseWriter, r *http.Request) {
+	if domain, ok := s.domain(w, r); ok {
+		writeJSON(w, http.StatusOK, domain)
+	}
+}
+
+// createDomain handles POST /domains
+func (s *Server) createDomain(w http.ResponseWriter, r *http.Request) {
+	var req postmark.DomainCreateRequest
+	if !decode(w, r, &req) {
+		return
+	}
+
+	name := strings.ToLower(strings.TrimSpace(req.Name))
+	if name == "" || !strings.Contains(name, ".") || strings.ContainsAny(name, "@ /") {
+		writeInvalid(w, fmt.Sprintf("The 'Name' '%s' is not a valid domain.", req.Name))
+		return
+	}
+	for _, domain := range s.domains {
+		if domain.Name == name {
+			writeInvalid(w, fmt.Sprintf("The domain '%s' already exists.", name))
+			return
+		}
+	}
+	if message := checkReturnPath(name, req.ReturnPathDomain); message != "" {
+		writeInvalid(w, message)
+		return
+	}
+
+	domain := &postmark.DomainDetails{
+		ID:                         s.newID(),
+		Name:                       name,
+		SPFHost:                    name,
+		SPFTextValue:               "v=spf1 a mx include:spf.mtasv.net ~all",
+		ReturnPathDomain:           req.ReturnPathDomain,
+		ReturnPathDomainCNAMEValue: "pm.mtasv.net",
+	}
+	pendingDKIM(domain, domain.ID)
+
+	s.domains[domain.ID] = domain
+	writeJSON(w, http.StatusOK, domain)
+}
+
+// editDomain handles PUT /domains/{domain}
+func (s *Server) editDomain(w http.ResponseWriter, r *http.Request) {
+	domain, ok := s.domain(w, r)
+	if !ok {
+		return
+	}
+
+	var req postmark.DomainEditRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	if message := checkReturnPath(domain.Name, req.ReturnPathDomain); message != "" {
+		writeInvalid(w, message)
+		return
+	}
+
+	if req.ReturnPathDomain != domain.ReturnPathDomain {
+		domain.ReturnPathDomain = req.ReturnPathDomain
+		domain.ReturnPathDomainVerified = false
+	}
+	writeJSON(w, http.StatusOK, domain)
+}
+
+// deleteDomain handles DELETE /domains/{domain}
+func (s *Server) deleteDomain(w http.ResponseWriter, r *http.Request) {
+	domain, ok := s.domain(w, r)
+	if !ok {
+		return
+	}
+
+	delete(s.domains, domain.ID)
+	writeOK(w, fmt.Sprintf("Domain %s removed.", domain.Name))
+}
+
+// rotateDKIM handles POST /domains/{domain}/rotatedkim
+func (s *Server) rotateDKIM(w http.ResponseWriter, r *http.Request) {
+	domain, ok := s.domain(w, r)
+	if !ok {
+		return
+	}
+	if domain.DKIMPendingHost != "" {
+		writeInvalid(w, fmt.Sprintf("The domain '%s' already has a pending DKIM key.", domain.Name))
+		return
+	}
+
+	pendingDKIM(domain, s.newID())
+	writeJSON(w, http.StatusOK, domain)
+}
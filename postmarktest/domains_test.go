@@ -0,0 +1,107 @@
+package postmarktest_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/postmark"
+)
+
+func TestDomains(t *testing.T) {
+	srv, client := newTestServer(t)
+	ctx := context.Background()
+
+	domain, err := client.CreateDomain(ctx, postmark.DomainCreateRequest{Name: "Example.com", ReturnPathDomain: "pm-bounces.example.com"})
+	require.NoError(t, err)
+	assert.Equal(t, "example.com", domain.Name)
+	assert.NotEmpty(t, domain.DKIMPendingHost)
+	assert.False(t, domain.DKIMVerified)
+
+	_, err = client.CreateDomain(ctx, postmark.DomainCreateRequest{Name: "example.com"})
+	require.Error(t, err)
+	_, err = client.CreateDomain(ctx, postmark.DomainCreateRequest{Name: "example.net", ReturnPathDomain: "bounces.example.com"})
+	require.Error(t, err)
+
+	require.True(t, srv.VerifyDomain("example.com"))
+	verified, err := client.VerifyDKIMStatus(ctx, domain.ID)
+	require.NoError(t, err)
+	assert.True(t, verified.DKIMVerified)
+	assert.True(t, verified.ReturnPathDomainVerified)
+	assert.Empty(t, verified.DKIMPendingHost)
+
+	rotated, err := client.RotateDKIM(ctx, domain.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Pending", rotated.DKIMUpdateStatus)
+	_, err = client.RotateDKIM(ctx, domain.ID)
+	require.Error(t, err)
+
+	edited, err := client.EditDomain(ctx, domain.ID, postmark.DomainEditRequest{ReturnPathDomain: "rp.example.com"})
+	require.NoError(t, err)
+	assert.False(t, edited.ReturnPathDomainVerified)
+
+	list, err := client.GetDomains(ctx, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 1, list.TotalCount)
+
+	require.NoError(t, client.DeleteDomain(ctx, domain.ID))
+	_, err = client.GetDomain(ctx, domain.ID)
+	require.Error(t, err)
+}
+
+func TestSenderSignatures(t *testing.T) {
+	_, client := newTestServer(t)
+	ctx := context.Background()
+
+	sender, err := client.CreateSenderSignature(ctx, postmark.SenderSignatureCreateRequest{FromEmail: "Jane <jane@example.com>", Name: "Jane"})
+	require.NoError(t, err)
+	assert.Equal(t, "jane@example.com", sender.FromEmail)
+	assert.Equal(t, "example.com", sender.Domain)
+	assert.False(t, sender.Confirmed)
+
+	_, err = client.CreateSenderSignature(ctx, postmark.SenderSignatureCreateRequest{FromEmail: "jane@example.com", Name: "Jane"})
+	require.Error(t, err)
+
+	require.NoError(t, client.ResendSenderSignatureConfirmation(ctx, sender.ID))
+
+	edited, err := client.EditSenderSignature(ctx, sender.ID, postmark.SenderSignatureEditRequest{Name: "Jane Doe"})
+	require.NoError(t, err)
+	assert.Equal(t, "Jane Doe", edited.Name)
+
+	list, err := client.GetSenderSignatures(ctx, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 1, list.TotalCount)
+
+	require.NoError(t, client.DeleteSenderSignature(ctx, sender.ID))
+	_, err = client.GetSenderSignature(ctx, sender.ID)
+	require.ErrorIs(t, err, postmark.ErrSenderSignatureNotFound)
+}
+
+func TestServers(t *testing.T) {
+	_, client := newTestServer(t)
+	ctx := context.Background()
+
+	server, err := client.CreateServer(ctx, postmark.ServerCreateRequest{Name: "Staging", Color: "Red"})
+	require.NoError(t, err)
+	assert.NotEmpty(t, server.APITokens)
+	assert.NotEmpty(t, server.InboundAddress)
+
+	_, err = client.CreateServer(ctx, postmark.ServerCreateRequest{Name: "staging"})
+	require.Error(t, err)
+
+	edited, err := client.EditServer(ctx, server.ID, postmark.ServerEditRequest{Name: "Staging 2", TrackOpens: true})
+	require.NoError(t, err)
+	assert.Equal(t, "Red", edited.Color)
+	assert.True(t, edited.TrackOpens)
+
+	staging, err := client.GetServers(ctx, 10, 0, "stag")
+	require.NoError(t, err)
+	require.Len(t, staging.Servers, 1)
+	assert.Equal(t, server.ID, staging.Servers[0].ID)
+
+	require.NoError(t, client.DeleteServer(ctx, server.ID))
+	_, err = client.GetServer(ctx, server.ID)
+	require.Error(t, err)
+}
@@ -0,0 +1,282 @@
+package postmarktest
+
+import (
+	"fmt"
+	"net/http"
+	"net/mail"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mrz1836/postmark"
+)
+
+// defaultMessageStream is used for emails that do not set a MessageStream
+const defaultMessageStream = "outbound"
+
+// Message is an email accepted by the fake
+type Message struct {
+	// MessageID: ID returned to the client
+	MessageID string
+	// SubmittedAt: when the message was accepted
+	SubmittedAt time.Time
+	// Email: the message as sent, for templated emails the Subject and bodies are the unrendered template content
+	Email postmark.Email
+	// TemplateID: ID of the template used, if any
+	TemplateID int64
+	// TemplateAlias: alias of the template used, if any
+	TemplateAlias string
+	// TemplateModel: model sent with a templated email
+	TemplateModel map[string]interface{}
+}
+
+// forbiddenAttachmentExtensions are file extensions Postmark refuses as attachments
+func forbiddenAttachmentExtensions() []string {
+	return []string{".bat", ".cmd", ".com", ".cpl", ".exe", ".js", ".jse", ".msi", ".pif", ".scr", ".vbe", ".vbs", ".wsf"}
+}
+
+// Messages returns every message accepted so far, oldest first
+func (s *Server) Messages() []Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.messages)
+}
+
+// sendEmail handles POST /email
+func (s *Server) sendEmail(w http.ResponseWriter, r *http.Request) {
+	var email postmark.Email
+	if !decode(w, r, &email) {
+		return
+	}
+
+	res := s.acceptEmail(email, nil)
+	if res.ErrorCode != 0 {
+		writeError(w, http.StatusUnprocessableEntity, res.ErrorCode, res.Message)
+		return
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+// sendEmailBatch handles POST /email/batch
+func (s *Server) sendEmailBatch(w http.ResponseWriter, r *http.Request) {
+	var emails []postmark.Email
+	if !decode(w, r, &emails) {
+		return
+	}
+	if len(emails) > postmark.MaxBatchMessages {
+		writeError(w, http.StatusUnprocessableEntity, postmark.ErrorCodeTooManyBatchMessages,
+			fmt.Sprintf("Too many batch messages. You may send a maximum of %d messages per batch.", postmark.MaxBatchMessages))
+		return
+	}
+
+	res := make([]postmark.EmailResponse, 0, len(emails))
+	for _, email := range emails {
+		res = append(res, s.acceptEmail(email, nil))
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+// sendTemplatedEmail handles POST /email/withTemplate
+func (s *Server) sendTemplatedEmail(w http.ResponseWriter, r *http.Request) {
+	var email postmark.TemplatedEmail
+	if !decode(w, r, &email) {
+		return
+	}
+
+	res := s.acceptTemplatedEmail(email)
+	if res.ErrorCode != 0 {
+		writeError(w, http.StatusUnprocessableEntity, res.ErrorCode, res.Message)
+		return
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+// sendTemplatedEmailBatch handles POST /email/batchWithTemplates
+func (s *Server) sendTemplatedEmailBatch(w http.ResponseWriter, r *http.Request) {
+	var batch struct {
+		Messages []postmark.TemplatedEmail
+	}
+	if !decode(w, r, &batch) {
+		return
+	}
+	if len(batch.Messages) > postmark.MaxBatchMessages {
+		writeError(w, http.StatusUnprocessableEntity, postmark.ErrorCodeTooManyBatchMessages,
+			fmt.Sprintf("Too many batch messages. You may send a maximum of %d messages per batch.", postmark.MaxBatchMessages))
+		return
+	}
+
+	res := make([]postmark.EmailResponse, 0, len(batch.Messages))
+	for _, email := range batch.Messages {
+		res = append(res, s.acceptTemplatedEmail(email))
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+// acceptTemplatedEmail resolves the template of the email and accepts it
+func (s *Server) acceptTemplatedEmail(email postmark.TemplatedEmail) postmark.EmailResponse {
+	var template *postmark.Template
+	switch {
+	case email.TemplateID != 0:
+		template = s.templates[email.TemplateID]
+	case email.TemplateAlias != "":
+		template = s.findTemplate(email.TemplateAlias)
+	default:
+		return emailError(email.To, postmark.ErrorCodeInvalidEmailRequest, "Either TemplateId or TemplateAlias must be specified.")
+	}
+
+	if template == nil || template.TemplateType == templateTypeLayout {
+		return emailError(email.To, postmark.ErrorCodeTemplateNotFound, "The template specified was not found.")
+	}
+	if !template.Active {
+		return emailError(email.To, postmark.ErrorCodeInvalidEmailRequest, "The template specified is not active.")
+	}
+
+	return s.acceptEmail(postmark.Email{
+		From:          email.From,
+		To:            email.To,
+		Cc:            email.Cc,
+		Bcc:           email.Bcc,
+		Subject:       template.Subject,
+		Tag:           email.Tag,
+		HTMLBody:      template.HTMLBody,
+		TextBody:      template.TextBody,
+		ReplyTo:       email.ReplyTo,
+		Headers:       email.Headers,
+		TrackOpens:    email.TrackOpens,
+		TrackLinks:    email.TrackLinks,
+		Attachments:   email.Attachments,
+		Metadata:      email.Metadata,
+		MessageStream: email.MessageStream,
+		InlineCSS:     email.InlineCSS,
+	}, &Message{
+		TemplateID:    template.TemplateID,
+		TemplateAlias: template.Alias,
+		TemplateModel: email.TemplateModel,
+	})
+}
+
+// acceptEmail validates the email and records it, template carries the template details of templated emails
+func (s *Server) acceptEmail(email postmark.Email, template *Message) postmark.EmailResponse {
+	if email.MessageStream == "" {
+		email.MessageStream = defaultMessageStream
+	}
+
+	if code, message := s.validateEmail(email); code != 0 {
+		return emailError(email.To, code, message)
+	}
+
+	msg := Message{
+		MessageID:   newMessageID(),
+		SubmittedAt: s.Now(),
+		Email:       email,
+	}
+	if template != nil {
+		msg.TemplateID = template.TemplateID
+		msg.TemplateAlias = template.TemplateAlias
+		msg.TemplateModel = template.TemplateModel
+	}
+	s.messages = append(s.messages, msg)
+
+	return postmark.EmailResponse{
+		To:          email.To,
+		SubmittedAt: msg.SubmittedAt,
+		MessageID:   msg.MessageID,
+		Message:     "OK",
+	}
+}
+
+// validateEmail checks an email like Postmark does, returning the error code and message of the first problem
+func (s *Server) validateEmail(email postmark.Email) (int64, string) {
+	from, err := mail.ParseAddress(email.From)
+	if err != nil {
+		return postmark.ErrorCodeInvalidEmailRequest, fmt.Sprintf("Invalid 'From' address: '%s'.", email.From)
+	}
+
+	var recipients []string
+	for _, field := range []struct{ name, value string }{{"To", email.To}, {"Cc", email.Cc}, {"Bcc", email.Bcc}} {
+		if strings.TrimSpace(field.value) == "" {
+			continue
+		}
+		addresses, err := mail.ParseAddressList(field.value)
+		if err != nil {
+			return postmark.ErrorCodeInvalidEmailRequest, fmt.Sprintf("Error parsing '%s': Illegal email address '%s'.", field.name, field.value)
+		}
+		for _, address := range addresses {
+			recipients = append(recipients, address.Address)
+		}
+	}
+	if len(recipients) == 0 {
+		return postmark.ErrorCodeInvalidEmailRequest, "Zero recipients specified"
+	}
+	if len(recipients) > postmark.MaxRecipients {
+		return postmark.ErrorCodeInvalidEmailRequest, fmt.Sprintf("Too many recipients specified, the maximum is %d.", postmark.MaxRecipients)
+	}
+
+	if email.HTMLBody == "" && email.TextBody == "" {
+		return postmark.ErrorCodeInvalidEmailRequest, "Provide either email TextBody or HtmlBody or both."
+	}
+
+	stream, ok := s.streams[email.MessageStream]
+	if !ok || stream.MessageStreamType == postmark.InboundMessageStreamType || stream.ArchivedAt != nil {
+		return postmark.ErrorCodeInvalidEmailRequest, fmt.Sprintf("The 'MessageStream' provided does not exist on this server: '%s'.", email.MessageStream)
+	}
+
+	for _, attachment := range email.Attachments {
+		if slices.Contains(forbiddenAttachmentExtensions(), strings.ToLower(path.Ext(attachment.Name))) {
+			return postmark.ErrorCodeForbiddenAttachmentType, fmt.Sprintf("Forbidden attachment type: '%s'.", attachment.Name)
+		}
+	}
+
+	if s.RequireSenderSignature {
+		if code, message := s.checkSender(from.Address); code != 0 {
+			return code, message
+		}
+	}
+
+	suppressed := s.suppressions[email.MessageStream]
+	var inactive []string
+	for _, recipient := range recipients {
+		if _, ok := suppressed[strings.ToLower(recipient)]; ok {
+			inactive = append(inactive, recipient)
+		}
+	}
+	if len(inactive) == len(recipients) {
+		return postmark.ErrorCodeInactiveRecipient, fmt.Sprintf(
+			"You tried to send to recipient(s) that have been marked as inactive. Found inactive addresses: %s. "+
+				"Inactive recipients are ones that have generated a hard bounce, a spam complaint, or a manual suppression.",
+			strings.Join(inactive, ", "))
+	}
+
+	return 0, ""
+}
+
+// checkSender verifies the From address has a confirmed sender signature or a verified domain
+func (s *Server) checkSender(from string) (int64, string) {
+	for _, sender := range s.senders {
+		if strings.EqualFold(sender.FromEmail, from) {
+			if !sender.Confirmed {
+				return postmark.ErrorCodeSenderSignatureNotConfirmed, fmt.Sprintf(
+					"The 'From' address you supplied (%s) does not have a confirmed Sender Signature.", from)
+			}
+			return 0, ""
+		}
+	}
+
+	if at := strings.LastIndex(from, "@"); at >= 0 {
+		for _, domain := range s.domains {
+			if strings.EqualFold(domain.Name, from[at+1:]) && domain.DKIMVerified {
+				return 0, ""
+			}
+		}
+	}
+
+	return postmark.ErrorCodeSenderSignatureNotFound, fmt.Sprintf(
+		"The 'From' address you supplied (%s) is not a Sender Signature on your account. "+
+			"Please add and confirm this address in order to be able to use it in the 'From' field of your messages.", from)
+}
+
+// emailError returns the response for an email that was not accepted
+func emailError(to string, code int64, message string) postmark.EmailResponse {
+	return postmark.EmailResponse{To: to, ErrorCode: code, Message: message}
+}
@@ -0,0 +1,211 @@
+package postmarktest_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/postmark"
+	"github.com/mrz1836/postmark/postmarktest"
+)
+
+func TestSendEmail(t *testing.T) {
+	srv, client := newTestServer(t)
+
+	res, err := client.SendEmail(context.Background(), postmark.Email{
+		From:     "sender@example.com",
+		To:       "Jane <jane@example.com>, john@example.com",
+		Subject:  "Hello",
+		TextBody: "Hi there",
+		Tag:      "welcome",
+		Metadata: map[string]string{"order": "42"},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "OK", res.Message)
+	assert.NotEmpty(t, res.MessageID)
+
+	messages := srv.Messages()
+	require.Len(t, messages, 1)
+	assert.Equal(t, res.MessageID, messages[0].MessageID)
+	assert.Equal(t, "Hello", messages[0].Email.Subject)
+	assert.Equal(t, "outbound", messages[0].Email.MessageStream)
+	assert.Equal(t, "42", messages[0].Email.Metadata["order"])
+}
+
+func TestSendEmailValidation(t *testing.T) {
+	srv, client := newTestServer(t)
+	srv.AddSuppression("outbound", postmark.Suppression{EmailAddress: "gone@example.com", SuppressionReason: postmark.HardBounceReason})
+
+	valid := postmark.Email{From: "sender@example.com", To: "jane@example.com", TextBody: "Hi"}
+
+	tests := []struct {
+		name     string
+		change   func(email *postmark.Email)
+		wantErr  error
+		wantCode int64
+	}{
+		{
+			name:     "missing from",
+			change:   func(email *postmark.Email) { email.From = "" },
+			wantErr:  postmark.ErrInvalidEmailRequest,
+			wantCode: postmark.ErrorCodeInvalidEmailRequest,
+		},
+		{
+			name:     "no recipients",
+			change:   func(email *postmark.Email) { email.To = "" },
+			wantErr:  postmark.ErrInvalidEmailRequest,
+			wantCode: postmark.ErrorCodeInvalidEmailRequest,
+		},
+		{
+			name:     "invalid recipient",
+			change:   func(email *postmark.Email) { email.Cc = "not an address" },
+			wantErr:  postmark.ErrInvalidEmailRequest,
+			wantCode: postmark.ErrorCodeInvalidEmailRequest,
+		},
+		{
+			name:     "no body",
+			change:   func(email *postmark.Email) { email.TextBody = "" },
+			wantErr:  postmark.ErrInvalidEmailRequest,
+			wantCode: postmark.ErrorCodeInvalidEmailRequest,
+		},
+		{
+			name:     "unknown stream",
+			change:   func(email *postmark.Email) { email.MessageStream = "missing" },
+			wantErr:  postmark.ErrInvalidEmailRequest,
+			wantCode: postmark.ErrorCodeInvalidEmailRequest,
+		},
+		{
+			name: "forbidden attachment",
+			change: func(email *postmark.Email) {
+				email.Attachments = []postmark.Attachment{{Name: "setup.EXE", Content: "AA==", ContentType: "application/octet-stream"}}
+			},
+			wantErr:  postmark.ErrForbiddenAttachmentType,
+			wantCode: postmark.ErrorCodeForbiddenAttachmentType,
+		},
+		{
+			name:     "suppressed recipient",
+			change:   func(email *postmark.Email) { email.To = "Gone@example.com" },
+			wantErr:  postmark.ErrInactiveRecipient,
+			wantCode: postmark.ErrorCodeInactiveRecipient,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			email := valid
+			tt.change(&email)
+
+			_, err := client.SendEmail(context.Background(), email)
+
+			require.ErrorIs(t, err, postmark.ErrEmailFailed)
+			require.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.wantCode, postmark.ErrorCode(err))
+		})
+	}
+
+	assert.Empty(t, srv.Messages())
+}
+
+func TestSendEmailRequireSenderSignature(t *testing.T) {
+	srv := newStrictServer(t)
+	client := srv.Client()
+	ctx := context.Background()
+	email := postmark.Email{From: "sender@example.com", To: "jane@example.com", TextBody: "Hi"}
+
+	_, err := client.SendEmail(ctx, email)
+	require.ErrorIs(t, err, postmark.ErrSenderSignatureNotFound)
+
+	_, err = client.CreateSenderSignature(ctx, postmark.SenderSignatureCreateRequest{FromEmail: "sender@example.com", Name: "Sender"})
+	require.NoError(t, err)
+	_, err = client.SendEmail(ctx, email)
+	require.ErrorIs(t, err, postmark.ErrSenderSignatureNotConfirmed)
+
+	require.True(t, srv.ConfirmSenderSignature("SENDER@example.com"))
+	_, err = client.SendEmail(ctx, email)
+	require.NoError(t, err)
+
+	_, err = client.CreateDomain(ctx, postmark.DomainCreateRequest{Name: "example.org"})
+	require.NoError(t, err)
+	email.From = "anyone@example.org"
+	_, err = client.SendEmail(ctx, email)
+	require.ErrorIs(t, err, postmark.ErrSenderSignatureNotFound)
+
+	require.True(t, srv.VerifyDomain("example.org"))
+	_, err = client.SendEmail(ctx, email)
+	require.NoError(t, err)
+}
+
+func newStrictServer(t *testing.T) *postmarktest.Server {
+	t.Helper()
+
+	srv := postmarktest.NewUnstartedServer()
+	srv.RequireSenderSignature = true
+	srv.Start()
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestSendEmailBatch(t *testing.T) {
+	srv, client := newTestServer(t)
+
+	res, err := client.SendEmailBatch(context.Background(), []postmark.Email{
+		{From: "sender@example.com", To: "jane@example.com", TextBody: "Hi"},
+		{From: "sender@example.com", TextBody: "Hi"},
+	})
+
+	require.NoError(t, err)
+	require.Len(t, res, 2)
+	assert.Equal(t, int64(0), res[0].ErrorCode)
+	assert.Equal(t, postmark.ErrorCodeInvalidEmailRequest, res[1].ErrorCode)
+	assert.Len(t, srv.Messages(), 1)
+
+	batch := make([]postmark.Email, 501)
+	_, err = client.SendEmailBatch(context.Background(), batch)
+	require.ErrorIs(t, err, postmark.ErrTooManyBatchMessages)
+}
+
+func TestSendTemplatedEmail(t *testing.T) {
+	srv, client := newTestServer(t)
+	template := srv.AddTemplate(postmark.Template{
+		Name:     "Welcome",
+		Alias:    "welcome",
+		Subject:  "Welcome {{name}}",
+		TextBody: "Hi {{name}}",
+	})
+
+	res, err := client.SendTemplatedEmail(context.Background(), postmark.TemplatedEmail{
+		TemplateAlias: "welcome",
+		TemplateModel: map[string]interface{}{"name": "Jane"},
+		From:          "sender@example.com",
+		To:            "jane@example.com",
+	})
+	require.NoError(t, err)
+	assert.NotEmpty(t, res.MessageID)
+
+	messages := srv.Messages()
+	require.Len(t, messages, 1)
+	assert.Equal(t, template.TemplateID, messages[0].TemplateID)
+	assert.Equal(t, "welcome", messages[0].TemplateAlias)
+	assert.Equal(t, "Jane", messages[0].TemplateModel["name"])
+	assert.Equal(t, "Welcome {{name}}", messages[0].Email.Subject)
+
+	_, err = client.SendTemplatedEmail(context.Background(), postmark.TemplatedEmail{
+		TemplateID: 999,
+		From:       "sender@example.com",
+		To:         "jane@example.com",
+	})
+	require.ErrorIs(t, err, postmark.ErrTemplateNotFound)
+
+	batch, err := client.SendTemplatedEmailBatch(context.Background(), []postmark.TemplatedEmail{
+		{TemplateID: template.TemplateID, From: "sender@example.com", To: "john@example.com"},
+		{TemplateAlias: "missing", From: "sender@example.com", To: "john@example.com"},
+	})
+	require.NoError(t, err)
+	require.Len(t, batch, 2)
+	assert.Equal(t, int64(0), batch[0].ErrorCode)
+	assert.Equal(t, postmark.ErrorCodeTemplateNotFound, batch[1].ErrorCode)
+	assert.Contains(t, batch[1].Message, "not found")
+}
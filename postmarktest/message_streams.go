@@ -0,0 +1,313 @@
+package postmarktest
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/mail"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mrz1836/postmark"
+)
+
+// archivedStreamRetention is how long Postmark keeps archived message streams before purging them
+const archivedStreamRetention = 45 * 24 * time.Hour
+
+// Suppressions returns the suppressed email addresses of the message stream
+func (s *Server) Suppressions(streamID string) []postmark.Suppression {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sortedSuppressions(streamID)
+}
+
+// AddSuppression suppresses an email address on the message stream, as if it bounced, complained or unsubscribed
+func (s *Server) AddSuppression(streamID string, suppression postmark.Suppression) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.suppress(streamID, suppression)
+}
+
+// suppress adds a suppression, the caller must hold the lock
+func (s *Server) suppress(streamID string, suppression postmark.Suppression) {
+	if suppression.CreatedAt.IsZero() {
+		suppression.CreatedAt = s.Now()
+	}
+	if s.suppressions[streamID] == nil {
+		s.suppressions[streamID] = make(map[string]postmark.Suppression)
+	}
+	s.suppressions[streamID][strings.ToLower(suppression.EmailAddress)] = suppression
+}
+
+// sortedSuppressions returns the suppressions of a stream ordered by address, the caller must hold the lock
+func (s *Server) sortedSuppressions(streamID string) []postmark.Suppression {
+	return slices.SortedFunc(maps.Values(s.suppressions[streamID]), func(a, b postmark.Suppression) int {
+		return cmp.Compare(strings.ToLower(a.EmailAddress), strings.ToLower(b.EmailAddress))
+	})
+}
+
+// stream returns the message stream with the ID in the path, answering with an error when there is none
+func (s *Server) stream(w http.ResponseWriter, r *http.Request) (*postmark.MessageStream, bool) {
+	stream, ok := s.streams[r.PathValue("stream")]
+	if !ok {
+		writeNotFound(w, fmt.Sprintf("The message stream for the provided 'ID' (%s) was not found.", r.PathValue("stream")))
+		return nil, false
+	}
+	return stream, true
+}
+
+// listMessageStreams handles GET /message-streams
+func (s *Server) listMessageStreams(w http.ResponseWriter, r *http.Request) {
+	streamType := r.URL.Query().Get("MessageStreamType")
+	includeArchived := r.URL.Query().Get("IncludeArchivedStreams") == "true"
+
+	streams := slices.SortedFunc(maps.Values(s.streams), func(a, b *postmark.MessageStream) int {
+		return cmp.Compare(a.CreatedAt+a.ID, b.CreatedAt+b.ID)
+	})
+
+	matched := make([]postmark.MessageStream, 0, len(streams))
+	for _, stream := range streams {
+		if streamType != "" && streamType != "All" && string(stream.MessageStreamType) != streamType {
+			continue
+		}
+		if stream.ArchivedAt != nil && !includeArchived {
+			continue
+		}
+		matched = append(matched, *stream)
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"MessageStreams": matched,
+		"TotalCount":     len(matched),
+	})
+}
+
+// getMessageStream handles GET /message-streams/{stream}
+func (s *Server) getMessageStream(w http.ResponseWriter, r *http.Request) {
+	if stream, ok := s.stream(w, r); ok {
+		writeJSON(w, http.StatusOK, stream)
+	}
+}
+
+// createMessageStream handles POST /message-streams
+func (s *Server) createMessageStream(w http.ResponseWriter, r *http.Request) {
+	var req postmark.CreateMessageStreamRequest
+	if !decode(w, r, &req) {
+		return
+	}
+
+	switch {
+	case req.ID == "":
+		writeInvalid(w, "The 'ID' field is required.")
+		return
+	case req.Name == "":
+		writeInvalid(w, "The 'Name' field is required.")
+		return
+	case req.MessageStreamType != postmark.TransactionalMessageStreamType && req.MessageStreamType != postmark.BroadcastMessageStreamType:
+		writeInvalid(w, fmt.Sprintf("The 'MessageStreamType' '%s' is not valid, use Transactional or Broadcasts.", req.MessageStreamType))
+		return
+	}
+	if _, exists := s.streams[req.ID]; exists {
+		writeInvalid(w, fmt.Sprintf("A message stream with the 'ID' '%s' already exists on this server.", req.ID))
+		return
+	}
+
+	stream := &postmark.MessageStream{
+		ID:                                  req.ID,
+		ServerID:                            defaultServerID,
+		Name:                                req.Name,
+		Description:                         req.Description,
+		MessageStreamType:                   req.MessageStreamType,
+		CreatedAt:                           s.Now().Format(time.RFC3339),
+		SubscriptionManagementConfiguration: req.SubscriptionManagementConfiguration,
+	}
+	if stream.SubscriptionManagementConfiguration.UnsubscribeHandlingType == "" {
+		stream.SubscriptionManagementConfiguration.UnsubscribeHandlingType = postmark.NoneUnsubscribeHandlingType
+		if stream.MessageStreamType == postmark.BroadcastMessageStreamType {
+			stream.SubscriptionManagementConfiguration.UnsubscribeHandlingType = postmark.PostmarkUnsubscribeHandlingType
+		}
+	}
+
+	s.streams[stream.ID] = stream
+	writeJSON(w, http.StatusOK, stream)
+}
+
+// editMessageStream handles PATCH /message-streams/{stream}
+func (s *Server) editMessageStream(w http.ResponseWriter, r *http.Request) {
+	stream, ok := s.stream(w, r)
+	if !ok {
+		return
+	}
+
+	var req postmark.EditMessageStreamRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	if req.SubscriptionManagementConfiguration.UnsubscribeHandlingType == postmark.NoneUnsubscribeHandlingType &&
+		stream.MessageStreamType == postmark.BroadcastMessageStreamType {
+		writeInvalid(w, "Broadcast message streams require unsubscribe handling.")
+		return
+	}
+
+	setIfNotEmpty(&stream.Name, req.Name)
+	if req.Description != nil {
+		stream.Description = req.Description
+	}
+	if req.SubscriptionManagementConfiguration.UnsubscribeHandlingType != "" {
+		stream.SubscriptionManagementConfiguration = req.SubscriptionManagementConfiguration
+	}
+	updated := s.Now().Format(time.RFC3339)
+	stream.UpdatedAt = &updated
+
+	writeJSON(w, http.StatusOK, stream)
+}
+
+// archiveMessageStream handles POST /message-streams/{stream}/archive
+func (s *Server) archiveMessageStream(w http.ResponseWriter, r *http.Request) {
+	stream, ok := s.stream(w, r)
+	if !ok {
+		return
+	}
+	if stream.ID == defaultMessageStream || stream.MessageStreamType == postmark.InboundMessageStreamType {
+		writeInvalid(w, fmt.Sprintf("The default message stream '%s' cannot be archived.", stream.ID))
+		return
+	}
+
+	now := s.Now()
+	archived := now.Format(time.RFC3339)
+	purge := now.Add(archivedStreamRetention).Format(time.RFC3339)
+	stream.ArchivedAt = &archived
+	stream.ExpectedPurgeDate = &purge
+
+	writeJSON(w, http.StatusOK, postmark.ArchiveMessageStreamResponse{
+		ID:                stream.ID,
+		ServerID:          stream.ServerID,
+		ExpectedPurgeDate: purge,
+	})
+}
+
+// unarchiveMessageStream handles POST /message-streams/{stream}/unarchive
+func (s *Server) unarchiveMessageStream(w http.ResponseWriter, r *http.Request) {
+	stream, ok := s.stream(w, r)
+	if !ok {
+		return
+	}
+
+	stream.ArchivedAt = nil
+	stream.ExpectedPurgeDate = nil
+	writeJSON(w, http.StatusOK, stream)
+}
+
+// dumpSuppressions handles GET /message-streams/{stream}/suppressions/dump
+func (s *Server) dumpSuppressions(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.stream(w, r); !ok {
+		return
+	}
+
+	query := r.URL.Query()
+	matched := make([]postmark.Suppression, 0)
+	for _, suppression := range s.sortedSuppressions(r.PathValue("stream")) {
+		if email := query.Get("EmailAddress"); email != "" && !strings.EqualFold(email, suppression.EmailAddress) {
+			continue
+		}
+		if reason := query.Get("SuppressionReason"); reason != "" && reason != string(suppression.SuppressionReason) {
+			continue
+		}
+		if origin := query.Get("Origin"); origin != "" && origin != string(suppression.Origin) {
+			continue
+		}
+		if !inDateRange(suppression.CreatedAt, query.Get("fromdate"), query.Get("todate")) {
+			continue
+		}
+		matched = append(matched, suppression)
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"Suppressions": matched})
+}
+
+// createSuppressions handles POST /message-streams/{stream}/suppressions
+func (s *Server) createSuppressions(w http.ResponseWriter, r *http.Request) {
+	s.updateSuppressions(w, r, func(streamID, address string) postmark.SuppressionResponse {
+		s.suppress(streamID, postmark.Suppression{
+			EmailAddress:      address,
+			SuppressionReason: postmark.ManualSuppressionReason,
+			Origin:            postmark.CustomerOrigin,
+		})
+		return postmark.SuppressionResponse{EmailAddress: address, Status: postmark.SuppressionUpdateStatusSuppressed}
+	})
+}
+
+// deleteSuppressions handles POST /message-streams/{stream}/suppressions/delete
+func (s *Server) deleteSuppressions(w http.ResponseWriter, r *http.Request) {
+	s.updateSuppressions(w, r, func(streamID, address string) postmark.SuppressionResponse {
+		key := strings.ToLower(address)
+		if existing, ok := s.suppressions[streamID][key]; ok && existing.SuppressionReason == postmark.SpamComplaintReason {
+			return postmark.SuppressionResponse{
+				EmailAddress: address,
+				Status:       postmark.SuppressionUpdateStatusFailed,
+				Message:      "You do not have the required authority to change this suppression.",
+			}
+		}
+		delete(s.suppressions[streamID], key)
+		return postmark.SuppressionResponse{EmailAddress: address, Status: postmark.SuppressionUpdateStatusDeleted}
+	})
+}
+
+// updateSuppressions decodes a suppressions request and applies update to every valid address
+func (s *Server) updateSuppressions(w http.ResponseWriter, r *http.Request, update func(streamID, address string) postmark.SuppressionResponse) {
+	if _, ok := s.stream(w, r); !ok {
+		return
+	}
+
+	var req struct {
+		Suppressions []postmark.Suppression
+	}
+	if !decode(w, r, &req) {
+		return
+	}
+
+	res := make([]postmark.SuppressionResponse, 0, len(req.Suppressions))
+	for _, suppression := range req.Suppressions {
+		if _, err := mail.ParseAddress(suppression.EmailAddress); err != nil {
+			res = append(res, postmark.SuppressionResponse{
+				EmailAddress: suppression.EmailAddress,
+				Status:       postmark.SuppressionUpdateStatusFailed,
+				Message:      "An invalid email address was provided.",
+			})
+			continue
+		}
+		res = append(res, update(r.PathValue("stream"), suppression.EmailAddress))
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"Suppressions": res})
+}
+
+// inDateRange reports whether t falls within the optional fromdate/todate query values (dates or timestamps)
+func inDateRange(t time.Time, from, to string) bool {
+	if start, ok := parseQueryDate(from); ok && t.Before(start) {
+		return false
+	}
+	if end, ok := parseQueryDate(to); ok {
+		if len(to) == len(time.DateOnly) {
+			end = end.Add(24 * time.Hour)
+		} else {
+			end = end.Add(time.Second)
+		}
+		if !t.Before(end) {
+			return false
+		}
+	}
+	return true
+}
+
+// parseQueryDate parses a date or timestamp query value
+func parseQueryDate(value string) (time.Time, bool) {
+	for _, layout := range []string{time.DateOnly, "2006-01-02T15:04:05", time.RFC3339} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
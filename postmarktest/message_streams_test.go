@@ -0,0 +1,111 @@
+package postmarktest_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/postmark"
+)
+
+func TestMessageStreams(t *testing.T) {
+	_, client := newTestServer(t)
+	ctx := context.Background()
+
+	stream, err := client.CreateMessageStream(ctx, postmark.CreateMessageStreamRequest{
+		ID:                "newsletter",
+		Name:              "Newsletter",
+		MessageStreamType: postmark.BroadcastMessageStreamType,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, postmark.PostmarkUnsubscribeHandlingType, stream.SubscriptionManagementConfiguration.UnsubscribeHandlingType)
+
+	_, err = client.CreateMessageStream(ctx, postmark.CreateMessageStreamRequest{
+		ID:                "newsletter",
+		Name:              "Again",
+		MessageStreamType: postmark.BroadcastMessageStreamType,
+	})
+	require.Error(t, err)
+
+	_, err = client.CreateMessageStream(ctx, postmark.CreateMessageStreamRequest{
+		ID:                "inbound-2",
+		Name:              "Inbound",
+		MessageStreamType: postmark.InboundMessageStreamType,
+	})
+	require.Error(t, err, "inbound streams cannot be created")
+
+	broadcasts, err := client.ListMessageStreams(ctx, "Broadcasts", false)
+	require.NoError(t, err)
+	assert.Len(t, broadcasts, 2)
+
+	edited, err := client.EditMessageStream(ctx, "newsletter", postmark.EditMessageStreamRequest{Name: "Monthly"})
+	require.NoError(t, err)
+	assert.Equal(t, "Monthly", edited.Name)
+	assert.NotNil(t, edited.UpdatedAt)
+
+	archived, err := client.ArchiveMessageStream(ctx, "newsletter")
+	require.NoError(t, err)
+	assert.NotEmpty(t, archived.ExpectedPurgeDate)
+
+	_, err = client.SendEmail(ctx, postmark.Email{From: "a@example.com", To: "b@example.com", TextBody: "Hi", MessageStream: "newsletter"})
+	require.ErrorIs(t, err, postmark.ErrInvalidEmailRequest, "archived streams cannot send")
+
+	active, err := client.ListMessageStreams(ctx, "All", false)
+	require.NoError(t, err)
+	assert.Len(t, active, 3)
+	all, err := client.ListMessageStreams(ctx, "All", true)
+	require.NoError(t, err)
+	assert.Len(t, all, 4)
+
+	_, err = client.ArchiveMessageStream(ctx, "outbound")
+	require.Error(t, err)
+
+	unarchived, err := client.UnarchiveMessageStream(ctx, "newsletter")
+	require.NoError(t, err)
+	assert.Nil(t, unarchived.ArchivedAt)
+
+	_, err = client.GetMessageStream(ctx, "missing")
+	require.Error(t, err)
+}
+
+func TestSuppressions(t *testing.T) {
+	srv, client := newTestServer(t)
+	ctx := context.Background()
+	srv.AddSuppression("outbound", postmark.Suppression{
+		EmailAddress:      "complainer@example.com",
+		SuppressionReason: postmark.SpamComplaintReason,
+		Origin:            postmark.RecipientOrigin,
+	})
+
+	created, err := client.CreateSuppressions(ctx, "outbound", []postmark.Suppression{
+		{EmailAddress: "jane@example.com"},
+		{EmailAddress: "not an address"},
+	})
+	require.NoError(t, err)
+	require.Len(t, created, 2)
+	assert.Equal(t, postmark.SuppressionUpdateStatusSuppressed, created[0].Status)
+	assert.Equal(t, postmark.SuppressionUpdateStatusFailed, created[1].Status)
+
+	suppressions, err := client.GetSuppressions(ctx, "outbound", nil)
+	require.NoError(t, err)
+	assert.Len(t, suppressions, 2)
+
+	manual, err := client.GetSuppressions(ctx, "outbound", map[string]interface{}{"SuppressionReason": "ManualSuppression"})
+	require.NoError(t, err)
+	require.Len(t, manual, 1)
+	assert.Equal(t, "jane@example.com", manual[0].EmailAddress)
+	assert.Equal(t, postmark.CustomerOrigin, manual[0].Origin)
+
+	deleted, err := client.DeleteSuppressions(ctx, "outbound", []postmark.Suppression{
+		{EmailAddress: "jane@example.com"},
+		{EmailAddress: "complainer@example.com"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, postmark.SuppressionUpdateStatusDeleted, deleted[0].Status)
+	assert.Equal(t, postmark.SuppressionUpdateStatusFailed, deleted[1].Status)
+
+	assert.Len(t, srv.Suppressions("outbound"), 1)
+	assert.Empty(t, srv.Suppressions("broadcast"))
+}
@@ -0,0 +1,179 @@
+package postmarktest
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/mail"
+	"slices"
+	"strings"
+
+	"github.com/mrz1836/postmark"
+)
+
+// ConfirmSenderSignature confirms the sender signature of the email address, as if the confirmation
+// link had been followed. It reports whether the sender signature exists.
+func (s *Server) ConfirmSenderSignature(email string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, sender := range s.senders {
+		if strings.EqualFold(sender.FromEmail, email) {
+			sender.Confirmed = true
+			return true
+		}
+	}
+	return false
+}
+
+// senderSummary returns the subset of the sender signature returned by list endpoints
+func senderSummary(sender *postmark.SenderSignatureDetails) postmark.SenderSignature {
+	return postmark.SenderSignature{
+		Domain:       sender.Domain,
+		FromEmail:    sender.FromEmail,
+		ReplyToEmail: sender.ReplyToEmail,
+		Name:         sender.Name,
+		Confirmed:    sender.Confirmed,
+		ID:           sender.ID,
+	}
+}
+
+// sender returns the sender signature with the ID in the path, answering with an error when there is none
+func (s *Server) sender(w http.ResponseWriter, r *http.Request) (*postmark.SenderSignatureDetails, bool) {
+	id, ok := pathID(w, r, "sender", "Sender signature")
+	if !ok {
+		return nil, false
+	}
+	sender, ok := s.senders[id]
+	if !ok {
+		writeError(w, http.StatusUnprocessableEntity, postmark.ErrorCodeSenderSignatureNotFound,
+			fmt.Sprintf("Sender signature '%d' was not found.", id))
+		return nil, false
+	}
+	return sender, true
+}
+
+// listSenderSignatures handles GET /senders
+func (s *Server) listSenderSignatures(w http.ResponseWriter, r *http.Request) {
+	senders := make([]postmark.SenderSignature, 0, len(s.senders))
+	for _, sender := range slices.SortedFunc(maps.Values(s.senders), func(a, b *postmark.SenderSignatureDetails) int {
+		return cmp.Compare(a.ID, b.ID)
+	}) {
+		senders = append(senders, senderSummary(sender))
+	}
+
+	count, offset := page(r)
+	writeJSON(w, http.StatusOK, postmark.SenderSignaturesList{
+		TotalCount:       len(senders),
+		SenderSignatures: paginate(senders, count, offset),
+	})
+}
+
+// getSenderSignature handles GET /senders/{sender}
+func (s *Server) getSenderSignature(w http.ResponseWriter, r *http.Request) {
+	if sender, ok := s.sender(w, r); ok {
+		writeJSON(w, http.StatusOK, sender)
+	}
+}
+
+// createSenderSignature handles POST /senders, new sender signatures are unconfirmed (see ConfirmSenderSignature)
+func (s *Server) createSenderSignature(w http.ResponseWriter, r *http.Request) {
+	var req postmark.SenderSignatureCreateRequest
+	if !decode(w, r, &req) {
+		return
+	}
+
+	from, err := mail.ParseAddress(req.FromEmail)
+	switch {
+	case err != nil:
+		writeInvalid(w, fmt.Sprintf("The 'FromEmail' '%s' is not a valid email address.", req.FromEmail))
+		return
+	case req.Name == "":
+		writeInvalid(w, "The 'Name' field is required.")
+		return
+	}
+	for _, sender := range s.senders {
+		if strings.EqualFold(sender.FromEmail, from.Address) {
+			writeInvalid(w, fmt.Sprintf("A sender signature for '%s' already exists.", from.Address))
+			return
+		}
+	}
+
+	domain := from.Address[strings.LastIndex(from.Address, "@")+1:]
+	if message := checkReturnPath(domain, req.ReturnPathDomain); message != "" {
+		writeInvalid(w, message)
+		return
+	}
+
+	sender := &postmark.SenderSignatureDetails{
+		ID:                         s.newID(),
+		Domain:                     domain,
+		FromEmail:                  from.Address,
+		ReplyToEmail:               req.ReplyToEmail,
+		Name:                       req.Name,
+		SPFHost:                    domain,
+		SPFTextValue:               "v=spf1 a mx include:spf.mtasv.net ~all",
+		ReturnPathDomain:           req.ReturnPathDomain,
+		ReturnPathDomainCNAMEValue: "pm.mtasv.net",
+		ConfirmationPersonalNote:   req.ConfirmationPersonalNote,
+	}
+
+	s.senders[sender.ID] = sender
+	writeJSON(w, http.StatusOK, sender)
+}
+
+// editSenderSignature handles PUT /senders/{sender}
+func (s *Server) editSenderSignature(w http.ResponseWriter, r *http.Request) {
+	sender, ok := s.sender(w, r)
+	if !ok {
+		return
+	}
+
+	var req postmark.SenderSignatureEditRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	if req.Name == "" {
+		writeInvalid(w, "The 'Name' field is required.")
+		return
+	}
+	if message := checkReturnPath(sender.Domain, req.ReturnPathDomain); message != "" {
+		writeInvalid(w, message)
+		return
+	}
+
+	sender.Name = req.Name
+	sender.ReplyToEmail = req.ReplyToEmail
+	sender.ConfirmationPersonalNote = req.ConfirmationPersonalNote
+	if req.ReturnPathDomain != sender.ReturnPathDomain {
+		sender.ReturnPathDomain = req.ReturnPathDomain
+		sender.ReturnPathDomainVerified = false
+	}
+	writeJSON(w, http.StatusOK, sender)
+}
+
+// deleteSenderSignature handles DELETE /senders/{sender}
+func (s *Server) deleteSenderSignature(w http.ResponseWriter, r *http.Request) {
+	sender, ok := s.sender(w, r)
+	if !ok {
+		return
+	}
+
+	delete(s.senders, sender.ID)
+	writeOK(w, fmt.Sprintf("Signature %s removed.", sender.FromEmail))
+}
+
+// resendSenderSignatureConfirmation handles POST /senders/{sender}/resend
+func (s *Server) resendSenderSignatureConfirmation(w http.ResponseWriter, r *http.Request) {
+	sender, ok := s.sender(w, r)
+	if !ok {
+		return
+	}
+	if sender.Confirmed {
+		writeInvalid(w, fmt.Sprintf("The sender signature '%s' is already confirmed.", sender.FromEmail))
+		return
+	}
+
+	writeOK(w, fmt.Sprintf("Confirmation email for Sender Signature %s was re-sent.", sender.FromEmail))
+}
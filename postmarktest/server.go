@@ -0,0 +1,348 @@
+// Package postmarktest provides an in-memory fake of the Postmark API for integration tests.
+//
+// The fake implements emails, batches, templates, message streams, suppressions, bounces, webhooks,
+// domains, sender signatures and servers. It keeps its state in memory, validates requests the way
+// Postmark does and answers with the documented error codes where Postmark defines one. Resources
+// that do not exist are reported with a 404 response.
+//
+//	srv := postmarktest.NewServer()
+//	defer srv.Close()
+//
+//	client := srv.Client()
+//	res, err := client.SendEmail(ctx, postmark.Email{...})
+//	sent := srv.Messages()
+package postmarktest
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/mrz1836/postmark"
+)
+
+const (
+	// DefaultServerToken is the server token accepted by a Server unless ServerToken is changed
+	DefaultServerToken = "postmarktest-server-token"
+
+	// DefaultAccountToken is the account token accepted by a Server unless AccountToken is changed
+	DefaultAccountToken = "postmarktest-account-token"
+
+	// defaultServerID is the ID of the Postmark server the server token belongs to
+	defaultServerID = 1
+
+	// defaultPageSize is used when a list request has no count
+	defaultPageSize = 100
+)
+
+// Server is a fake Postmark API backed by an httptest.Server.
+// Create one with NewServer, or with NewUnstartedServer to change its fields before it starts.
+type Server struct {
+	// ServerToken is the token expected in the X-Postmark-Server-Token header
+	ServerToken string
+	// AccountToken is the token expected in the X-Postmark-Account-Token header
+	AccountToken string
+	// RequireSenderSignature rejects emails whose From address has no confirmed sender signature
+	// and is not on a verified domain, like a production Postmark account
+	RequireSenderSignature bool
+	// Now returns the current time, it defaults to time.Now
+	Now func() time.Time
+
+	// URL is the base URL of the fake, set once the server is started
+	URL string
+
+	httpServer *httptest.Server
+
+	mu           sync.Mutex
+	nextID       int64
+	messages     []Message
+	templates    map[int64]*postmark.Template
+	streams      map[string]*postmark.MessageStream
+	suppressions map[string]map[string]postmark.Suppression
+	bounces      map[int64]*postmark.Bounce
+	webhooks     map[int]*postmark.Webhook
+	domains      map[int64]*postmark.DomainDetails
+	senders      map[int64]*postmark.SenderSignatureDetails
+	servers      map[int64]*postmark.Server
+}
+
+// NewServer starts and returns a new fake Postmark API, the caller should call Close when finished
+func NewServer() *Server {
+	s := NewUnstartedServer()
+	s.Start()
+	return s
+}
+
+// NewUnstartedServer returns a new fake Postmark API that is not started yet.
+// Change its fields, then call Start.
+func NewUnstartedServer() *Server {
+	s := &Server{
+		ServerToken:  DefaultServerToken,
+		AccountToken: DefaultAccountToken,
+		Now:          time.Now,
+	}
+	s.httpServer = httptest.NewUnstartedServer(s.routes())
+	s.reset()
+	return s
+}
+
+// Start starts the server
+func (s *Server) Start() {
+	s.httpServer.Start()
+	s.URL = s.httpServer.URL
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.servers[defaultServerID].APITokens = []string{s.ServerToken}
+}
+
+// Close shuts down the server and blocks until all outstanding requests have completed
+func (s *Server) Close() {
+	s.httpServer.Close()
+}
+
+// Client returns a postmark.Client that talks to the fake with its tokens
+func (s *Server) Client() *postmark.Client {
+	client := postmark.NewClient(s.ServerToken, s.AccountToken)
+	client.BaseURL = s.URL
+	client.HTTPClient = s.httpServer.Client()
+	return client
+}
+
+// Reset discards all state, leaving the server as it was when it started
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.reset()
+}
+
+// reset initializes the state, the caller must hold the lock (or own the server)
+func (s *Server) reset() {
+	s.nextID = defaultServerID // IDs are shared by all resources and start after the default server
+	s.messages = nil
+	s.templates = make(map[int64]*postmark.Template)
+	s.streams = make(map[string]*postmark.MessageStream)
+	s.suppressions = make(map[string]map[string]postmark.Suppression)
+	s.bounces = make(map[int64]*postmark.Bounce)
+	s.webhooks = make(map[int]*postmark.Webhook)
+	s.domains = make(map[int64]*postmark.DomainDetails)
+	s.senders = make(map[int64]*postmark.SenderSignatureDetails)
+	s.servers = map[int64]*postmark.Server{
+		defaultServerID: {
+			ID:           defaultServerID,
+			Name:         "postmarktest",
+			APITokens:    []string{s.ServerToken},
+			Color:        "Blue",
+			DeliveryType: "Live",
+			TrackLinks:   "None",
+		},
+	}
+
+	created := s.Now().Format(time.RFC3339)
+	for _, stream := range []postmark.MessageStream{
+		{ID: "outbound", Name: "Default Transactional Stream", MessageStreamType: postmark.TransactionalMessageStreamType},
+		{ID: "inbound", Name: "Default Inbound Stream", MessageStreamType: postmark.InboundMessageStreamType},
+		{ID: "broadcast", Name: "Default Broadcast Stream", MessageStreamType: postmark.BroadcastMessageStreamType},
+	} {
+		stream.ServerID = defaultServerID
+		stream.CreatedAt = created
+		stream.SubscriptionManagementConfiguration.UnsubscribeHandlingType = postmark.NoneUnsubscribeHandlingType
+		if stream.MessageStreamType == postmark.BroadcastMessageStreamType {
+			stream.SubscriptionManagementConfiguration.UnsubscribeHandlingType = postmark.PostmarkUnsubscribeHandlingType
+		}
+		s.streams[stream.ID] = &stream
+	}
+}
+
+// routes registers every endpoint of the fake
+func (s *Server) routes() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("POST /email", s.withServerToken(s.sendEmail))
+	mux.HandleFunc("POST /email/batch", s.withServerToken(s.sendEmailBatch))
+	mux.HandleFunc("POST /email/withTemplate", s.withServerToken(s.sendTemplatedEmail))
+	mux.HandleFunc("POST /email/batchWithTemplates", s.withServerToken(s.sendTemplatedEmailBatch))
+
+	mux.HandleFunc("GET /templates", s.withServerToken(s.listTemplates))
+	mux.HandleFunc("POST /templates", s.withServerToken(s.createTemplate))
+	mux.HandleFunc("POST /templates/validate", s.withServerToken(s.validateTemplate))
+	mux.HandleFunc("GET /templates/{template}", s.withServerToken(s.getTemplate))
+	mux.HandleFunc("PUT /templates/{template}", s.withServerToken(s.editTemplate))
+	mux.HandleFunc("DELETE /templates/{template}", s.withServerToken(s.deleteTemplate))
+
+	mux.HandleFunc("GET /message-streams", s.withServerToken(s.listMessageStreams))
+	mux.HandleFunc("POST /message-streams", s.withServerToken(s.createMessageStream))
+	mux.HandleFunc("GET /message-streams/{stream}", s.withServerToken(s.getMessageStream))
+	mux.HandleFunc("PATCH /message-streams/{stream}", s.withServerToken(s.editMessageStream))
+	mux.HandleFunc("POST /message-streams/{stream}/archive", s.withServerToken(s.archiveMessageStream))
+	mux.HandleFunc("POST /message-streams/{stream}/unarchive", s.withServerToken(s.unarchiveMessageStream))
+	mux.HandleFunc("GET /message-streams/{stream}/suppressions/dump", s.withServerToken(s.dumpSuppressions))
+	mux.HandleFunc("POST /message-streams/{stream}/suppressions", s.withServerToken(s.createSuppressions))
+	mux.HandleFunc("POST /message-streams/{stream}/suppressions/delete", s.withServerToken(s.deleteSuppressions))
+
+	mux.HandleFunc("GET /deliverystats", s.withServerToken(s.deliveryStats))
+	mux.HandleFunc("GET /bounces", s.withServerToken(s.listBounces))
+	mux.HandleFunc("GET /bounces/tags", s.withServerToken(s.bouncedTags))
+	mux.HandleFunc("GET /bounces/{bounce}", s.withServerToken(s.getBounce))
+	mux.HandleFunc("GET /bounces/{bounce}/dump", s.withServerToken(s.getBounceDump))
+	mux.HandleFunc("PUT /bounces/{bounce}/activate", s.withServerToken(s.activateBounce))
+
+	mux.HandleFunc("GET /webhooks", s.withServerToken(s.listWebhooks))
+	mux.HandleFunc("POST /webhooks", s.withServerToken(s.createWebhook))
+	mux.HandleFunc("GET /webhooks/{webhook}", s.withServerToken(s.getWebhook))
+	mux.HandleFunc("PUT /webhooks/{webhook}", s.withServerToken(s.editWebhook))
+	mux.HandleFunc("DELETE /webhooks/{webhook}", s.withServerToken(s.deleteWebhook))
+
+	mux.HandleFunc("GET /domains", s.withAccountToken(s.listDomains))
+	mux.HandleFunc("POST /domains", s.withAccountToken(s.createDomain))
+	mux.HandleFunc("GET /domains/{domain}", s.withAccountToken(s.getDomain))
+	mux.HandleFunc("PUT /domains/{domain}", s.withAccountToken(s.editDomain))
+	mux.HandleFunc("DELETE /domains/{domain}", s.withAccountToken(s.deleteDomain))
+	mux.HandleFunc("PUT /domains/{domain}/verifyDkim", s.withAccountToken(s.getDomain))
+	mux.HandleFunc("PUT /domains/{domain}/verifyReturnPath", s.withAccountToken(s.getDomain))
+	mux.HandleFunc("POST /domains/{domain}/rotatedkim", s.withAccountToken(s.rotateDKIM))
+
+	mux.HandleFunc("GET /senders", s.withAccountToken(s.listSenderSignatures))
+	mux.HandleFunc("POST /senders", s.withAccountToken(s.createSenderSignature))
+	mux.HandleFunc("GET /senders/{sender}", s.withAccountToken(s.getSenderSignature))
+	mux.HandleFunc("PUT /senders/{sender}", s.withAccountToken(s.editSenderSignature))
+	mux.HandleFunc("DELETE /senders/{sender}", s.withAccountToken(s.deleteSenderSignature))
+	mux.HandleFunc("POST /senders/{sender}/resend", s.withAccountToken(s.resendSenderSignatureConfirmation))
+
+	mux.HandleFunc("GET /servers", s.withAccountToken(s.listServers))
+	mux.HandleFunc("POST /servers", s.withAccountToken(s.createServer))
+	mux.HandleFunc("GET /servers/{server}", s.withAccountToken(s.getServer))
+	mux.HandleFunc("PUT /servers/{server}", s.withAccountToken(s.editServer))
+	mux.HandleFunc("DELETE /servers/{server}", s.withAccountToken(s.deleteServer))
+
+	return mux
+}
+
+// withServerToken rejects requests without the server token
+func (s *Server) withServerToken(next http.HandlerFunc) http.HandlerFunc {
+	return s.withToken("X-Postmark-Server-Token", func() string { return s.ServerToken }, "Server", next)
+}
+
+// withAccountToken rejects requests without the account token
+func (s *Server) withAccountToken(next http.HandlerFunc) http.HandlerFunc {
+	return s.withToken("X-Postmark-Account-Token", func() string { return s.AccountToken }, "Account", next)
+}
+
+// withToken checks the token header before calling next, and locks the state for the handler
+func (s *Server) withToken(header string, token func() string, kind string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		value := r.Header.Get(header)
+		if value == "" {
+			writeError(w, http.StatusUnauthorized, postmark.ErrorCodeInvalidAPIToken,
+				"No Account or Server API tokens were supplied in the HTTP headers. Please add a header for either X-Postmark-Server-Token or X-Postmark-Account-Token.")
+			return
+		}
+		if value != token() {
+			writeError(w, http.StatusUnauthorized, postmark.ErrorCodeInvalidAPIToken,
+				fmt.Sprintf("Request does not contain a valid %s token.", kind))
+			return
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		next(w, r)
+	}
+}
+
+// newID returns the next numeric ID, the caller must hold the lock
+func (s *Server) newID() int64 {
+	s.nextID++
+	return s.nextID
+}
+
+// newMessageID returns a random UUID formatted like Postmark message IDs
+func newMessageID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+
+	h := hex.EncodeToString(b[:])
+	return h[0:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:32]
+}
+
+// decode reads the JSON request body into dst, answering with an error if it is invalid
+func decode(w http.ResponseWriter, r *http.Request, dst interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(dst); err != nil {
+		writeError(w, http.StatusUnprocessableEntity, postmark.ErrorCodeInvalidJSON,
+			fmt.Sprintf("Provided request body is not valid JSON: %v", err))
+		return false
+	}
+	return true
+}
+
+// writeJSON writes v as the JSON response body
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeOK writes the response Postmark sends for successful requests without a body
+func writeOK(w http.ResponseWriter, message string) {
+	writeJSON(w, http.StatusOK, postmark.APIError{Message: message})
+}
+
+// writeError writes a Postmark error response
+func writeError(w http.ResponseWriter, status int, code int64, message string) {
+	writeJSON(w, status, postmark.APIError{ErrorCode: code, Message: message})
+}
+
+// writeInvalid writes the error Postmark returns for well formed JSON that is missing or has invalid fields
+func writeInvalid(w http.ResponseWriter, message string) {
+	writeError(w, http.StatusUnprocessableEntity, postmark.ErrorCodeIncompatibleJSON, message)
+}
+
+// writeNotFound writes the error returned for unknown resources
+func writeNotFound(w http.ResponseWriter, message string) {
+	writeError(w, http.StatusNotFound, 0, message)
+}
+
+// pathID parses a numeric path value, answering with a not found error when it is not a number
+func pathID(w http.ResponseWriter, r *http.Request, name, resource string) (int64, bool) {
+	id, err := strconv.ParseInt(r.PathValue(name), 10, 64)
+	if err != nil {
+		writeNotFound(w, fmt.Sprintf("%s '%s' was not found.", resource, r.PathValue(name)))
+		return 0, false
+	}
+	return id, true
+}
+
+// setIfNotEmpty changes dst when value is not empty, used by endpoints that only update the fields that are set
+func setIfNotEmpty(dst *string, value string) {
+	if value != "" {
+		*dst = value
+	}
+}
+
+// page returns the count and offset query values of a list request
+func page(r *http.Request) (count, offset int) {
+	count, err := strconv.Atoi(r.URL.Query().Get("count"))
+	if err != nil || count <= 0 {
+		count = defaultPageSize
+	}
+	offset, err = strconv.Atoi(r.URL.Query().Get("offset"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+	return count, offset
+}
+
+// paginate returns the page of items selected by count and offset
+func paginate[T any](items []T, count, offset int) []T {
+	if offset >= len(items) {
+		return []T{}
+	}
+	return items[offset:min(offset+count, len(items))]
+}
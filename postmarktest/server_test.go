@@ -0,0 +1,67 @@
+package postmarktest_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/postmark"
+	"github.com/mrz1836/postmark/postmarktest"
+)
+
+// newTestServer starts a fake and closes it when the test ends
+func newTestServer(t *testing.T) (*postmarktest.Server, *postmark.Client) {
+	t.Helper()
+
+	srv := postmarktest.NewServer()
+	t.Cleanup(srv.Close)
+	return srv, srv.Client()
+}
+
+func TestServerTokens(t *testing.T) {
+	srv := postmarktest.NewUnstartedServer()
+	srv.ServerToken = "my-server-token"
+	srv.Start()
+	defer srv.Close()
+
+	client := srv.Client()
+	_, err := client.ListMessageStreams(context.Background(), "All", false)
+	require.NoError(t, err)
+
+	wrong := srv.Client()
+	wrong.ServerToken = "wrong"
+	_, err = wrong.ListMessageStreams(context.Background(), "All", false)
+	require.ErrorIs(t, err, postmark.ErrInvalidAPIToken)
+
+	wrong.AccountToken = ""
+	_, err = wrong.GetServers(context.Background(), 10, 0, "")
+	require.ErrorIs(t, err, postmark.ErrInvalidAPIToken)
+
+	servers, err := client.GetServers(context.Background(), 10, 0, "")
+	require.NoError(t, err)
+	require.Len(t, servers.Servers, 1)
+	assert.Equal(t, []string{"my-server-token"}, servers.Servers[0].APITokens)
+}
+
+func TestServerReset(t *testing.T) {
+	srv, client := newTestServer(t)
+	ctx := context.Background()
+
+	_, err := client.SendEmail(ctx, postmark.Email{From: "a@example.com", To: "b@example.com", TextBody: "hi"})
+	require.NoError(t, err)
+	_, err = client.CreateWebhook(ctx, postmark.Webhook{URL: "https://example.com/hook"})
+	require.NoError(t, err)
+
+	srv.Reset()
+
+	assert.Empty(t, srv.Messages())
+	webhooks, err := client.ListWebhooks(ctx, "")
+	require.NoError(t, err)
+	assert.Empty(t, webhooks)
+
+	streams, err := client.ListMessageStreams(ctx, "All", false)
+	require.NoError(t, err)
+	assert.Len(t, streams, 3)
+}
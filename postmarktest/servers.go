@@ -0,0 +1,156 @@
+package postmarktest
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/mrz1836/postmark"
+)
+
+// server returns the server with the ID in the path, answering with an error when there is none
+func (s *Server) server(w http.ResponseWriter, r *http.Request) (*postmark.Server, bool) {
+	id, ok := pathID(w, r, "server", "Server")
+	if !ok {
+		return nil, false
+	}
+	server, ok := s.servers[id]
+	if !ok {
+		writeNotFound(w, fmt.Sprintf("Server '%d' was not found.", id))
+		return nil, false
+	}
+	return server, true
+}
+
+// checkServerName validates the name of a new or renamed server, id is zero for new servers
+func (s *Server) checkServerName(name string, id int64) string {
+	if strings.TrimSpace(name) == "" {
+		return "The 'Name' field is required."
+	}
+	for _, server := range s.servers {
+		if server.ID != id && strings.EqualFold(server.Name, name) {
+			return fmt.Sprintf("A server named '%s' already exists.", name)
+		}
+	}
+	return ""
+}
+
+// listServers handles GET /servers
+func (s *Server) listServers(w http.ResponseWriter, r *http.Request) {
+	name := strings.ToLower(r.URL.Query().Get("name"))
+
+	servers := make([]postmark.Server, 0, len(s.servers))
+	for _, server := range slices.SortedFunc(maps.Values(s.servers), func(a, b *postmark.Server) int {
+		return cmp.Compare(a.ID, b.ID)
+	}) {
+		if name == "" || strings.Contains(strings.ToLower(server.Name), name) {
+			servers = append(servers, *server)
+		}
+	}
+
+	count, offset := page(r)
+	writeJSON(w, http.StatusOK, postmark.ServersList{
+		TotalCount: len(servers),
+		Servers:    paginate(servers, count, offset),
+	})
+}
+
+// getServer handles GET /servers/{server}
+func (s *Server) getServer(w http.ResponseWriter, r *http.Request) {
+	if server, ok := s.server(w, r); ok {
+		writeJSON(w, http.StatusOK, server)
+	}
+}
+
+// createServer handles POST /servers.
+// The tokens of new servers are listed in ApiTokens but only ServerToken is accepted by the fake.
+func (s *Server) createServer(w http.ResponseWriter, r *http.Request) {
+	var req postmark.ServerCreateRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	if message := s.checkServerName(req.Name, 0); message != "" {
+		writeInvalid(w, message)
+		return
+	}
+
+	server := &postmark.Server{
+		ID:                         s.newID(),
+		Name:                       req.Name,
+		APITokens:                  []string{newMessageID()},
+		Color:                      req.Color,
+		SMTPAPIActivated:           req.SMTPAPIActivated,
+		RawEmailEnabled:            req.RawEmailEnabled,
+		DeliveryType:               req.DeliveryType,
+		InboundHookURL:             req.InboundHookURL,
+		BounceHookURL:              req.BounceHookURL,
+		OpenHookURL:                req.OpenHookURL,
+		DeliveryHookURL:            req.DeliveryHookURL,
+		PostFirstOpenOnly:          req.PostFirstOpenOnly,
+		TrackOpens:                 req.TrackOpens,
+		TrackLinks:                 req.TrackLinks,
+		IncludeBounceContentInHook: req.IncludeBounceContentInHook,
+		InboundDomain:              req.InboundDomain,
+		InboundSpamThreshold:       req.InboundSpamThreshold,
+		EnableSMTPAPIErrorHooks:    req.EnableSMTPAPIErrorHooks,
+	}
+	server.ServerLink = fmt.Sprintf("https://account.postmarkapp.com/servers/%d/streams", server.ID)
+	server.InboundHash = strings.ReplaceAll(newMessageID(), "-", "")
+	server.InboundAddress = server.InboundHash + "@inbound.postmarkapp.com"
+
+	s.servers[server.ID] = server
+	writeJSON(w, http.StatusOK, server)
+}
+
+// editServer handles PUT /servers/{server}
+func (s *Server) editServer(w http.ResponseWriter, r *http.Request) {
+	server, ok := s.server(w, r)
+	if !ok {
+		return
+	}
+
+	var req postmark.ServerEditRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	if message := s.checkServerName(req.Name, server.ID); message != "" {
+		writeInvalid(w, message)
+		return
+	}
+
+	server.Name = req.Name
+	setIfNotEmpty(&server.Color, req.Color)
+	server.SMTPAPIActivated = req.SMTPAPIActivated
+	server.RawEmailEnabled = req.RawEmailEnabled
+	server.InboundHookURL = req.InboundHookURL
+	server.BounceHookURL = req.BounceHookURL
+	server.OpenHookURL = req.OpenHookURL
+	server.DeliveryHookURL = req.DeliveryHookURL
+	server.PostFirstOpenOnly = req.PostFirstOpenOnly
+	server.InboundDomain = req.InboundDomain
+	server.InboundSpamThreshold = req.InboundSpamThreshold
+	server.TrackOpens = req.TrackOpens
+	setIfNotEmpty(&server.TrackLinks, req.TrackLinks)
+	server.IncludeBounceContentInHook = req.IncludeBounceContentInHook
+	server.EnableSMTPAPIErrorHooks = req.EnableSMTPAPIErrorHooks
+
+	writeJSON(w, http.StatusOK, server)
+}
+
+// deleteServer handles DELETE /servers/{server}, the server the fake's ServerToken belongs to cannot be deleted
+func (s *Server) deleteServer(w http.ResponseWriter, r *http.Request) {
+	server, ok := s.server(w, r)
+	if !ok {
+		return
+	}
+	if server.ID == defaultServerID {
+		writeInvalid(w, "The server used by the fake's server token cannot be deleted.")
+		return
+	}
+
+	delete(s.servers, server.ID)
+	writeOK(w, fmt.Sprintf("Server %s removed.", server.Name))
+}
@@ -0,0 +1,239 @@
+package postmarktest
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/mrz1836/postmark"
+)
+
+const (
+	// templateTypeStandard is the type of templates used to send email
+	templateTypeStandard = "Standard"
+
+	// templateTypeLayout is the type of templates that wrap standard templates
+	templateTypeLayout = "Layout"
+)
+
+// AddTemplate stores a template as if it was created through the API and returns it with its TemplateID set
+func (s *Server) AddTemplate(template postmark.Template) postmark.Template {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return *s.storeTemplate(template)
+}
+
+// storeTemplate fills in the defaults of a new template and stores it, the caller must hold the lock
+func (s *Server) storeTemplate(template postmark.Template) *postmark.Template {
+	template.TemplateID = s.newID()
+	template.AssociatedServerID = defaultServerID
+	template.Active = true
+	if template.TemplateType == "" {
+		template.TemplateType = templateTypeStandard
+	}
+	s.templates[template.TemplateID] = &template
+	return &template
+}
+
+// findTemplate returns the template with the ID or alias, nil if there is none
+func (s *Server) findTemplate(idOrAlias string) *postmark.Template {
+	if id, err := strconv.ParseInt(idOrAlias, 10, 64); err == nil {
+		return s.templates[id]
+	}
+	for _, template := range s.templates {
+		if template.Alias != "" && template.Alias == idOrAlias {
+			return template
+		}
+	}
+	return nil
+}
+
+// sortedTemplates returns the templates ordered by ID
+func (s *Server) sortedTemplates() []*postmark.Template {
+	return slices.SortedFunc(maps.Values(s.templates), func(a, b *postmark.Template) int {
+		return cmp.Compare(a.TemplateID, b.TemplateID)
+	})
+}
+
+// templateInfo returns the subset of the template returned by list endpoints
+func templateInfo(template *postmark.Template) postmark.TemplateInfo {
+	return postmark.TemplateInfo{
+		TemplateID:     template.TemplateID,
+		Name:           template.Name,
+		Active:         template.Active,
+		Alias:          template.Alias,
+		TemplateType:   template.TemplateType,
+		LayoutTemplate: template.LayoutTemplate,
+	}
+}
+
+// listTemplates handles GET /templates
+func (s *Server) listTemplates(w http.ResponseWriter, r *http.Request) {
+	templateType := r.URL.Query().Get("TemplateType")
+	layout := r.URL.Query().Get("LayoutTemplate")
+
+	var matched []postmark.TemplateInfo
+	for _, template := range s.sortedTemplates() {
+		if templateType != "" && templateType != "All" && template.TemplateType != templateType {
+			continue
+		}
+		if layout != "" && template.LayoutTemplate != layout {
+			continue
+		}
+		matched = append(matched, templateInfo(template))
+	}
+
+	count, offset := page(r)
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"TotalCount": len(matched),
+		"Templates":  paginate(matched, count, offset),
+	})
+}
+
+// getTemplate handles GET /templates/{template}
+func (s *Server) getTemplate(w http.ResponseWriter, r *http.Request) {
+	template := s.findTemplate(r.PathValue("template"))
+	if template == nil {
+		writeTemplateNotFound(w, r.PathValue("template"))
+		return
+	}
+	writeJSON(w, http.StatusOK, template)
+}
+
+// createTemplate handles POST /templates
+func (s *Server) createTemplate(w http.ResponseWriter, r *http.Request) {
+	var template postmark.Template
+	if !decode(w, r, &template) {
+		return
+	}
+	if message := s.checkTemplate(template, 0); message != "" {
+		writeInvalid(w, message)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, templateInfo(s.storeTemplate(template)))
+}
+
+// editTemplate handles PUT /templates/{template}, only the fields that are set are changed
+func (s *Server) editTemplate(w http.ResponseWriter, r *http.Request) {
+	existing := s.findTemplate(r.PathValue("template"))
+	if existing == nil {
+		writeTemplateNotFound(w, r.PathValue("template"))
+		return
+	}
+
+	var changes postmark.Template
+	if !decode(w, r, &changes) {
+		return
+	}
+
+	updated := *existing
+	setIfNotEmpty(&updated.Name, changes.Name)
+	setIfNotEmpty(&updated.Subject, changes.Subject)
+	setIfNotEmpty(&updated.HTMLBody, changes.HTMLBody)
+	setIfNotEmpty(&updated.TextBody, changes.TextBody)
+	setIfNotEmpty(&updated.Alias, changes.Alias)
+	setIfNotEmpty(&updated.LayoutTemplate, changes.LayoutTemplate)
+	if message := s.checkTemplate(updated, updated.TemplateID); message != "" {
+		writeInvalid(w, message)
+		return
+	}
+
+	*existing = updated
+	writeJSON(w, http.StatusOK, templateInfo(existing))
+}
+
+// deleteTemplate handles DELETE /templates/{template}
+func (s *Server) deleteTemplate(w http.ResponseWriter, r *http.Request) {
+	template := s.findTemplate(r.PathValue("template"))
+	if template == nil {
+		writeTemplateNotFound(w, r.PathValue("template"))
+		return
+	}
+
+	if template.TemplateType == templateTypeLayout {
+		for _, other := range s.templates {
+			if other.LayoutTemplate == template.Alias {
+				writeInvalid(w, fmt.Sprintf("The layout '%s' is used by other templates and cannot be deleted.", template.Alias))
+				return
+			}
+		}
+	}
+
+	delete(s.templates, template.TemplateID)
+	writeOK(w, fmt.Sprintf("Template %d removed.", template.TemplateID))
+}
+
+// validateTemplate handles POST /templates/validate.
+// Templates are not rendered, the content is returned as is along with the model suggested by its tags,
+// fields with a syntax error are left out of the model.
+func (s *Server) validateTemplate(w http.ResponseWriter, r *http.Request) {
+	var body postmark.ValidateTemplateBody
+	if !decode(w, r, &body) {
+		return
+	}
+
+	model, _ := postmark.NewTemplateRenderer().SuggestModel(postmark.Template{
+		Subject: body.Subject, HTMLBody: body.HTMLBody, TextBody: body.TextBody,
+	})
+	writeJSON(w, http.StatusOK, postmark.ValidateTemplateResponse{
+		AllContentIsValid:      true,
+		Subject:                postmark.Validation{ContentIsValid: true, RenderedContent: body.Subject},
+		HTMLBody:               postmark.Validation{ContentIsValid: true, RenderedContent: body.HTMLBody},
+		TextBody:               postmark.Validation{ContentIsValid: true, RenderedContent: body.TextBody},
+		SuggestedTemplateModel: model,
+	})
+}
+
+// checkTemplate checks a new or edited template, returning a message describing the first problem.
+// id is the ID of the template being edited, zero for new templates.
+func (s *Server) checkTemplate(template postmark.Template, id int64) string {
+	if template.Name == "" {
+		return "The 'Name' field is required."
+	}
+	if template.TemplateType != "" && template.TemplateType != templateTypeStandard && template.TemplateType != templateTypeLayout {
+		return fmt.Sprintf("The 'TemplateType' '%s' is not valid, use Standard or Layout.", template.TemplateType)
+	}
+
+	if template.TemplateType == templateTypeLayout {
+		if template.Alias == "" {
+			return "Layout templates require an 'Alias'."
+		}
+		if template.Subject != "" {
+			return "Layout templates cannot have a 'Subject'."
+		}
+		if template.LayoutTemplate != "" {
+			return "Layout templates cannot use a 'LayoutTemplate'."
+		}
+	} else if template.Subject == "" {
+		return "The 'Subject' field is required."
+	}
+
+	if template.HTMLBody == "" && template.TextBody == "" {
+		return "Provide either a 'HtmlBody' or 'TextBody' or both."
+	}
+
+	if template.Alias != "" {
+		if other := s.findTemplate(template.Alias); other != nil && other.TemplateID != id {
+			return fmt.Sprintf("The 'Alias' '%s' is already taken by another template.", template.Alias)
+		}
+	}
+
+	if template.LayoutTemplate != "" {
+		layout := s.findTemplate(template.LayoutTemplate)
+		if layout == nil || layout.TemplateType != templateTypeLayout {
+			return fmt.Sprintf("The 'LayoutTemplate' '%s' does not exist.", template.LayoutTemplate)
+		}
+	}
+
+	return ""
+}
+
+// writeTemplateNotFound writes the error Postmark returns for unknown templates
+func writeTemplateNotFound(w http.ResponseWriter, idOrAlias string) {
+	writeError(w, http.StatusUnprocessableEntity, postmark.ErrorCodeTemplateNotFound,
+		fmt.Sprintf("The template '%s' was not found.", idOrAlias))
+}
@@ -0,0 +1,87 @@
+package postmarktest_test
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/postmark"
+)
+
+func TestTemplates(t *testing.T) {
+	_, client := newTestServer(t)
+	ctx := context.Background()
+
+	layout, err := client.CreateTemplate(ctx, postmark.Template{
+		Name:         "Base",
+		Alias:        "base",
+		TemplateType: "Layout",
+		HTMLBody:     "<html>{{{@content}}}</html>",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Layout", layout.TemplateType)
+
+	welcome, err := client.CreateTemplate(ctx, postmark.Template{
+		Name:           "Welcome",
+		Alias:          "welcome",
+		Subject:        "Welcome",
+		HTMLBody:       "<p>Hi</p>",
+		LayoutTemplate: "base",
+	})
+	require.NoError(t, err)
+	assert.True(t, welcome.Active)
+
+	_, err = client.CreateTemplate(ctx, postmark.Template{Name: "Copy", Alias: "welcome", Subject: "Copy", TextBody: "Hi"})
+	require.ErrorIs(t, err, postmark.ErrInvalidJSON)
+	assert.Equal(t, postmark.ErrorCodeIncompatibleJSON, postmark.ErrorCode(err))
+
+	_, err = client.CreateTemplate(ctx, postmark.Template{Name: "No subject", TextBody: "Hi"})
+	require.Error(t, err)
+
+	_, err = client.CreateTemplate(ctx, postmark.Template{Name: "Bad layout", Subject: "Hi", TextBody: "Hi", LayoutTemplate: "missing"})
+	require.Error(t, err)
+
+	all, total, err := client.GetTemplates(ctx, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.Len(t, all, 2)
+
+	layouts, total, err := client.GetTemplatesFiltered(ctx, 10, 0, "Layout", "")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, "base", layouts[0].Alias)
+
+	_, err = client.EditTemplate(ctx, "welcome", postmark.Template{Name: "Welcome!", Subject: "Hello"})
+	require.NoError(t, err)
+
+	template, err := client.GetTemplate(ctx, strconv.FormatInt(welcome.TemplateID, 10))
+	require.NoError(t, err)
+	assert.Equal(t, "Welcome!", template.Name)
+	assert.Equal(t, "Hello", template.Subject)
+	assert.Equal(t, "<p>Hi</p>", template.HTMLBody)
+
+	err = client.DeleteTemplate(ctx, "base")
+	require.Error(t, err, "a layout in use cannot be deleted")
+
+	require.NoError(t, client.DeleteTemplate(ctx, "welcome"))
+	require.NoError(t, client.DeleteTemplate(ctx, "base"))
+
+	_, err = client.GetTemplate(ctx, "welcome")
+	require.ErrorIs(t, err, postmark.ErrTemplateNotFound)
+}
+
+func TestValidateTemplate(t *testing.T) {
+	_, client := newTestServer(t)
+
+	res, err := client.ValidateTemplate(context.Background(), postmark.ValidateTemplateBody{
+		Subject:  "Hi {{name}}",
+		TextBody: "Body",
+	})
+
+	require.NoError(t, err)
+	assert.True(t, res.AllContentIsValid)
+	assert.Equal(t, "Body", res.TextBody.RenderedContent)
+}
@@ -0,0 +1,121 @@
+package postmarktest
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+
+	"github.com/mrz1836/postmark"
+)
+
+// webhook returns the webhook with the ID in the path, answering with an error when there is none
+func (s *Server) webhook(w http.ResponseWriter, r *http.Request) (*postmark.Webhook, bool) {
+	id, ok := pathID(w, r, "webhook", "Webhook")
+	if !ok {
+		return nil, false
+	}
+	webhook, ok := s.webhooks[int(id)]
+	if !ok {
+		writeNotFound(w, fmt.Sprintf("Webhook '%d' was not found.", id))
+		return nil, false
+	}
+	return webhook, true
+}
+
+// checkWebhook validates a new or edited webhook, returning a message describing the first problem
+func (s *Server) checkWebhook(webhook postmark.Webhook) string {
+	target, err := url.Parse(webhook.URL)
+	if webhook.URL == "" || err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return fmt.Sprintf("The 'Url' '%s' is not a valid absolute http or https URL.", webhook.URL)
+	}
+	if stream, ok := s.streams[webhook.MessageStream]; !ok || stream.MessageStreamType == postmark.InboundMessageStreamType {
+		return fmt.Sprintf("The 'MessageStream' '%s' does not exist on this server.", webhook.MessageStream)
+	}
+	return ""
+}
+
+// listWebhooks handles GET /webhooks
+func (s *Server) listWebhooks(w http.ResponseWriter, r *http.Request) {
+	stream := r.URL.Query().Get("MessageStream")
+
+	webhooks := make([]postmark.Webhook, 0, len(s.webhooks))
+	for _, webhook := range slices.SortedFunc(maps.Values(s.webhooks), func(a, b *postmark.Webhook) int {
+		return cmp.Compare(a.ID, b.ID)
+	}) {
+		if stream == "" || webhook.MessageStream == stream {
+			webhooks = append(webhooks, *webhook)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"Webhooks": webhooks})
+}
+
+// getWebhook handles GET /webhooks/{webhook}
+func (s *Server) getWebhook(w http.ResponseWriter, r *http.Request) {
+	if webhook, ok := s.webhook(w, r); ok {
+		writeJSON(w, http.StatusOK, webhook)
+	}
+}
+
+// createWebhook handles POST /webhooks
+func (s *Server) createWebhook(w http.ResponseWriter, r *http.Request) {
+	var webhook postmark.Webhook
+	if !decode(w, r, &webhook) {
+		return
+	}
+	if webhook.MessageStream == "" {
+		webhook.MessageStream = defaultMessageStream
+	}
+	if message := s.checkWebhook(webhook); message != "" {
+		writeInvalid(w, message)
+		return
+	}
+
+	webhook.ID = int(s.newID())
+	s.webhooks[webhook.ID] = &webhook
+	writeJSON(w, http.StatusOK, webhook)
+}
+
+// editWebhook handles PUT /webhooks/{webhook}, the message stream of a webhook cannot change
+func (s *Server) editWebhook(w http.ResponseWriter, r *http.Request) {
+	existing, ok := s.webhook(w, r)
+	if !ok {
+		return
+	}
+
+	var changes postmark.Webhook
+	if !decode(w, r, &changes) {
+		return
+	}
+
+	updated := *existing
+	setIfNotEmpty(&updated.URL, changes.URL)
+	if changes.HTTPAuth != nil {
+		updated.HTTPAuth = changes.HTTPAuth
+	}
+	if changes.HTTPHeaders != nil {
+		updated.HTTPHeaders = changes.HTTPHeaders
+	}
+	updated.Triggers = changes.Triggers
+	if message := s.checkWebhook(updated); message != "" {
+		writeInvalid(w, message)
+		return
+	}
+
+	*existing = updated
+	writeJSON(w, http.StatusOK, existing)
+}
+
+// deleteWebhook handles DELETE /webhooks/{webhook}
+func (s *Server) deleteWebhook(w http.ResponseWriter, r *http.Request) {
+	webhook, ok := s.webhook(w, r)
+	if !ok {
+		return
+	}
+
+	delete(s.webhooks, webhook.ID)
+	writeOK(w, fmt.Sprintf("Webhook %d removed.", webhook.ID))
+}
@@ -0,0 +1,51 @@
+package postmarktest_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mrz1836/postmark"
+)
+
+func TestWebhooks(t *testing.T) {
+	_, client := newTestServer(t)
+	ctx := context.Background()
+
+	webhook, err := client.CreateWebhook(ctx, postmark.Webhook{
+		URL:      "https://example.com/hooks/postmark",
+		HTTPAuth: &postmark.WebhookHTTPAuth{Username: "user", Password: "pass"},
+		Triggers: postmark.WebhookTrigger{Delivery: postmark.WebhookTriggerEnabled{Enabled: true}},
+	})
+	require.NoError(t, err)
+	assert.NotZero(t, webhook.ID)
+	assert.Equal(t, "outbound", webhook.MessageStream)
+
+	_, err = client.CreateWebhook(ctx, postmark.Webhook{URL: "not a url"})
+	require.Error(t, err)
+	_, err = client.CreateWebhook(ctx, postmark.Webhook{URL: "https://example.com", MessageStream: "inbound"})
+	require.Error(t, err)
+
+	_, err = client.CreateWebhook(ctx, postmark.Webhook{URL: "https://example.com/broadcast", MessageStream: "broadcast"})
+	require.NoError(t, err)
+
+	webhooks, err := client.ListWebhooks(ctx, "outbound")
+	require.NoError(t, err)
+	require.Len(t, webhooks, 1)
+
+	edited, err := client.EditWebhook(ctx, webhook.ID, postmark.Webhook{
+		URL:      "https://example.com/hooks/v2",
+		Triggers: postmark.WebhookTrigger{Bounce: postmark.WebhookTriggerIncContent{WebhookTriggerEnabled: postmark.WebhookTriggerEnabled{Enabled: true}}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/hooks/v2", edited.URL)
+	assert.Equal(t, "user", edited.HTTPAuth.Username)
+	assert.True(t, edited.Triggers.Bounce.Enabled)
+	assert.False(t, edited.Triggers.Delivery.Enabled)
+
+	require.NoError(t, client.DeleteWebhook(ctx, webhook.ID))
+	_, err = client.GetWebhook(ctx, webhook.ID)
+	require.Error(t, err)
+}
@@ -0,0 +1,128 @@
+package postmark
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// TokenType identifies which Postmark API token authenticates a request
+type TokenType string
+
+const (
+	// ServerTokenType is used for requests authenticated with the Server Token
+	ServerTokenType TokenType = serverToken
+	// AccountTokenType is used for requests authenticated with the Account Token
+	AccountTokenType TokenType = accountToken
+)
+
+// RateLimit throttles the requests made by a Client. Server and account token traffic
+// have separate budgets, a nil limiter leaves that traffic unthrottled.
+type RateLimit struct {
+	// Server limits requests made with the Server Token (sending, messages, stats, ...)
+	Server *RateLimiter
+	// Account limits requests made with the Account Token (servers, domains, senders, ...)
+	Account *RateLimiter
+	// OnWait is called after every request passes its limiter with the time spent waiting (optional)
+	OnWait func(ctx context.Context, tokenType TokenType, wait time.Duration)
+}
+
+// limiter returns the limiter for the given token type
+func (r *RateLimit) limiter(tokenType TokenType) *RateLimiter {
+	if tokenType == AccountTokenType {
+		return r.Account
+	}
+	return r.Server
+}
+
+// wait blocks until the request is allowed by the limiter for its token type
+func (r *RateLimit) wait(ctx context.Context, tokenType TokenType) error {
+	if r == nil {
+		return nil
+	}
+
+	limiter := r.limiter(tokenType)
+	if limiter == nil {
+		return nil
+	}
+
+	waited, err := limiter.Wait(ctx)
+	if err != nil {
+		return err
+	}
+
+	if r.OnWait != nil {
+		r.OnWait(ctx, tokenType, waited)
+	}
+	return nil
+}
+
+// RateLimiter is a token bucket that is safe for concurrent use.
+// It can be shared by several clients to enforce a common budget.
+type RateLimiter struct {
+	mu     sync.Mutex
+	rate   float64 // tokens added per second
+	burst  float64 // bucket capacity
+	tokens float64 // may go negative while callers are queued
+	last   time.Time
+}
+
+// NewRateLimiter creates a RateLimiter allowing requestsPerSecond on average,
+// with bursts of up to burst requests. The bucket starts full.
+func NewRateLimiter(requestsPerSecond float64, burst int) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &RateLimiter{
+		rate:   requestsPerSecond,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// Wait blocks until a request is allowed or the context is done.
+// It returns how long the caller waited.
+func (l *RateLimiter) Wait(ctx context.Context) (time.Duration, error) {
+	if l.rate <= 0 {
+		return 0, ctx.Err()
+	}
+
+	delay := l.reserve(time.Now())
+	if err := sleepContext(ctx, delay); err != nil {
+		l.cancel()
+		return 0, err
+	}
+	return delay, nil
+}
+
+// reserve takes a token and returns how long the caller must wait for it to become available
+func (l *RateLimiter) reserve(now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if elapsed := now.Sub(l.last); elapsed > 0 {
+		l.tokens += elapsed.Seconds() * l.rate
+		if l.tokens > l.burst {
+			l.tokens = l.burst
+		}
+		l.last = now
+	}
+
+	l.tokens--
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens / l.rate * float64(time.Second))
+}
+
+// cancel gives back a token that was reserved but not used
+func (l *RateLimiter) cancel() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.tokens++
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+}
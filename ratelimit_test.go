@@ -0,0 +1,95 @@
+package postmark
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+func (s *PostmarkTestSuite) TestRateLimiterReserve() {
+	limiter := NewRateLimiter(10, 2)
+	now := limiter.last
+
+	s.Equal(time.Duration(0), limiter.reserve(now), "first token comes from the burst")
+	s.Equal(time.Duration(0), limiter.reserve(now), "second token comes from the burst")
+	s.Equal(100*time.Millisecond, limiter.reserve(now), "bucket is empty, wait for one token")
+	s.Equal(200*time.Millisecond, limiter.reserve(now), "queued behind the previous caller")
+
+	// After a second the bucket has refilled the debt and is capped at the burst size
+	later := now.Add(time.Second)
+	s.Equal(time.Duration(0), limiter.reserve(later))
+	s.Equal(time.Duration(0), limiter.reserve(later))
+	s.InDelta(0.0, limiter.tokens, 0.0001)
+}
+
+func (s *PostmarkTestSuite) TestRateLimiterWaitCanceled() {
+	limiter := NewRateLimiter(0.001, 1)
+	_ = limiter.reserve(limiter.last)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	waited, err := limiter.Wait(ctx)
+
+	s.Require().ErrorIs(err, context.Canceled)
+	s.Equal(time.Duration(0), waited)
+	s.InDelta(0.0, limiter.tokens, 0.01, "canceled reservation must be returned")
+}
+
+func (s *PostmarkTestSuite) TestRateLimiterUnlimited() {
+	limiter := NewRateLimiter(0, 1)
+
+	for i := 0; i < 5; i++ {
+		waited, err := limiter.Wait(context.Background())
+		s.Require().NoError(err)
+		s.Equal(time.Duration(0), waited)
+	}
+}
+
+func (s *PostmarkTestSuite) TestDoRequestRateLimit() {
+	s.mux.Get("/rate-limited", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"message": "success"}`))
+	})
+
+	var mu sync.Mutex
+	observed := map[TokenType]int{}
+
+	client := s.newClient()
+	client.RateLimit = &RateLimit{
+		Server: NewRateLimiter(1000, 1),
+		OnWait: func(_ context.Context, tokenType TokenType, wait time.Duration) {
+			mu.Lock()
+			defer mu.Unlock()
+			observed[tokenType]++
+			s.GreaterOrEqual(wait, time.Duration(0))
+		},
+	}
+
+	for i := 0; i < 3; i++ {
+		err := client.doRequest(context.Background(), "", http.MethodGet, "rate-limited", nil, nil, serverToken)
+		s.Require().NoError(err)
+	}
+
+	// Account traffic has no limiter configured, so it is neither throttled nor observed
+	err := client.doRequest(context.Background(), "", http.MethodGet, "rate-limited", nil, nil, accountToken)
+	s.Require().NoError(err)
+
+	s.Equal(3, observed[ServerTokenType])
+	s.Equal(0, observed[AccountTokenType])
+}
+
+func (s *PostmarkTestSuite) TestDoRequestRateLimitContextCanceled() {
+	limiter := NewRateLimiter(0.001, 1)
+	_ = limiter.reserve(limiter.last)
+
+	client := s.newClient()
+	client.RateLimit = &RateLimit{Account: limiter}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	err := client.doRequest(ctx, "", http.MethodGet, "rate-limited", nil, nil, accountToken)
+
+	s.Require().ErrorIs(err, context.DeadlineExceeded)
+}
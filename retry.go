@@ -0,0 +1,161 @@
+package postmark
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// Default values used by DefaultRetryPolicy
+const (
+	defaultRetryMaxAttempts = 3
+	defaultRetryBaseBackoff = 500 * time.Millisecond
+	defaultRetryMaxBackoff  = 30 * time.Second
+	defaultRetryJitter      = 0.2
+)
+
+// RetryPolicy configures how the Client retries failed requests.
+// A nil policy on the Client disables retries, which is the default.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one. Values below 2 disable retries.
+	MaxAttempts int
+	// BaseBackoff is the delay before the first retry. It doubles with every subsequent attempt.
+	BaseBackoff time.Duration
+	// MaxBackoff caps the delay between attempts, including a Retry-After sent by the server. Zero means no cap.
+	MaxBackoff time.Duration
+	// Jitter is the fraction (0 to 1) of the computed delay that is randomly subtracted to spread out retries.
+	Jitter float64
+	// RetryableStatusCodes are the HTTP status codes that trigger a retry.
+	RetryableStatusCodes []int
+	// RetryableErrorCodes are the Postmark APIError codes that trigger a retry, regardless of HTTP status.
+	RetryableErrorCodes []int64
+	// RetryNonIdempotent allows POST and PATCH requests (e.g. email/batch) to be retried.
+	// Enable with care: a request that reached Postmark before failing may be delivered twice.
+	RetryNonIdempotent bool
+}
+
+// DefaultRetryPolicy returns a RetryPolicy that retries idempotent requests up to
+// three times on throttling, server errors and Postmark maintenance responses
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts: defaultRetryMaxAttempts,
+		BaseBackoff: defaultRetryBaseBackoff,
+		MaxBackoff:  defaultRetryMaxBackoff,
+		Jitter:      defaultRetryJitter,
+		RetryableStatusCodes: []int{
+			http.StatusTooManyRequests,
+			http.StatusInternalServerError,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+		RetryableErrorCodes: []int64{ErrorCodeMaintenance},
+	}
+}
+
+// attempts returns the number of attempts allowed for the given method
+func (p *RetryPolicy) attempts(method string) int {
+	if p == nil || p.MaxAttempts < 2 {
+		return 1
+	}
+	if !p.RetryNonIdempotent && !isIdempotent(method) {
+		return 1
+	}
+	return p.MaxAttempts
+}
+
+// shouldRetry reports whether a failed attempt is worth repeating
+func (p *RetryPolicy) shouldRetry(ctx context.Context, statusCode int, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	var apiErr APIError
+	if errors.As(err, &apiErr) && slices.Contains(p.RetryableErrorCodes, apiErr.ErrorCode) {
+		return true
+	}
+
+	// A zero status code means the request never got a response (transport error)
+	if statusCode == 0 {
+		return err != nil
+	}
+
+	return slices.Contains(p.RetryableStatusCodes, statusCode)
+}
+
+// backoff returns how long to wait before the given retry (1 is the first retry).
+// A Retry-After header sent by the server takes precedence over the computed delay, up to MaxBackoff.
+func (p *RetryPolicy) backoff(retry int, header http.Header) time.Duration {
+	if delay, ok := parseRetryAfter(header, time.Now()); ok {
+		if p.MaxBackoff > 0 && delay > p.MaxBackoff {
+			return p.MaxBackoff
+		}
+		return delay
+	}
+
+	delay := float64(p.BaseBackoff) * math.Pow(2, float64(retry-1))
+	if p.MaxBackoff > 0 && delay > float64(p.MaxBackoff) {
+		delay = float64(p.MaxBackoff)
+	}
+	if p.Jitter > 0 {
+		delay -= delay * math.Min(p.Jitter, 1) * rand.Float64() //nolint:gosec // jitter does not need a secure source
+	}
+
+	return time.Duration(delay)
+}
+
+// isIdempotent reports whether a request with this method can be safely repeated
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// parseRetryAfter reads the Retry-After header, which is either a number of seconds or an HTTP date
+func parseRetryAfter(header http.Header, now time.Time) (time.Duration, bool) {
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		if delay := date.Sub(now); delay > 0 {
+			return delay, true
+		}
+		return 0, true
+	}
+
+	return 0, false
+}
+
+// sleepContext waits for the delay or until the context is done, whichever happens first
+func sleepContext(ctx context.Context, delay time.Duration) error {
+	if delay <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
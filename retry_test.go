@@ -0,0 +1,242 @@
+package postmark
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+)
+
+// testRetryPolicy returns a fast retry policy suitable for tests
+func testRetryPolicy() *RetryPolicy {
+	policy := DefaultRetryPolicy()
+	policy.BaseBackoff = time.Millisecond
+	policy.MaxBackoff = 5 * time.Millisecond
+	policy.Jitter = 0
+	return policy
+}
+
+func (s *PostmarkTestSuite) TestDoRequestRetry() {
+	tests := []struct {
+		name          string
+		method        string
+		failures      int
+		failureStatus int
+		failureBody   string
+		retryPOST     bool
+		wantErr       bool
+		wantAttempts  int
+	}{
+		{
+			name:          "GET retried after server error",
+			method:        http.MethodGet,
+			failures:      2,
+			failureStatus: http.StatusServiceUnavailable,
+			failureBody:   `{"ErrorCode": 0, "Message": "Service Unavailable"}`,
+			wantAttempts:  3,
+		},
+		{
+			name:          "GET gives up after max attempts",
+			method:        http.MethodGet,
+			failures:      5,
+			failureStatus: http.StatusInternalServerError,
+			failureBody:   `{"ErrorCode": 0, "Message": "Internal Server Error"}`,
+			wantErr:       true,
+			wantAttempts:  3,
+		},
+		{
+			name:          "GET retried on maintenance error code",
+			method:        http.MethodGet,
+			failures:      1,
+			failureStatus: http.StatusUnprocessableEntity,
+			failureBody:   `{"ErrorCode": 100, "Message": "Maintenance"}`,
+			wantAttempts:  2,
+		},
+		{
+			name:          "GET not retried on client error",
+			method:        http.MethodGet,
+			failures:      1,
+			failureStatus: http.StatusUnprocessableEntity,
+			failureBody:   `{"ErrorCode": 300, "Message": "Invalid email request"}`,
+			wantErr:       true,
+			wantAttempts:  1,
+		},
+		{
+			name:          "POST not retried by default",
+			method:        http.MethodPost,
+			failures:      1,
+			failureStatus: http.StatusServiceUnavailable,
+			failureBody:   `{"ErrorCode": 0, "Message": "Service Unavailable"}`,
+			wantErr:       true,
+			wantAttempts:  1,
+		},
+		{
+			name:          "POST retried when opted in",
+			method:        http.MethodPost,
+			failures:      1,
+			failureStatus: http.StatusTooManyRequests,
+			failureBody:   `{"ErrorCode": 0, "Message": "Too Many Requests"}`,
+			retryPOST:     true,
+			wantAttempts:  2,
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			attempts := 0
+			s.mux.HandleFunc(tt.method, "/retry-test", func(w http.ResponseWriter, req *http.Request) {
+				attempts++
+				if req.Method == http.MethodPost {
+					body, err := io.ReadAll(req.Body)
+					s.Require().NoError(err)
+					s.JSONEq(`{"test":"data"}`, string(body), "body must be replayed on every attempt")
+				}
+				if attempts <= tt.failures {
+					w.WriteHeader(tt.failureStatus)
+					_, _ = w.Write([]byte(tt.failureBody))
+					return
+				}
+				_, _ = w.Write([]byte(`{"message": "success"}`))
+			})
+
+			client := s.newClient()
+			client.Retry = testRetryPolicy()
+			client.Retry.RetryNonIdempotent = tt.retryPOST
+
+			var payload interface{}
+			if tt.method == http.MethodPost {
+				payload = map[string]string{"test": "data"}
+			}
+
+			var result map[string]string
+			err := client.doRequest(context.Background(), "", tt.method, "retry-test", payload, &result, serverToken)
+
+			if tt.wantErr {
+				s.Require().Error(err)
+			} else {
+				s.Require().NoError(err)
+				s.Equal("success", result["message"])
+			}
+			s.Equal(tt.wantAttempts, attempts)
+		})
+	}
+}
+
+func (s *PostmarkTestSuite) TestDoRequestRetryHonorsRetryAfter() {
+	attempts := 0
+	s.mux.Get("/retry-after", func(w http.ResponseWriter, _ *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(`{"ErrorCode": 0, "Message": "Too Many Requests"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"message": "success"}`))
+	})
+
+	client := s.newClient()
+	client.Retry = testRetryPolicy()
+	// A large base backoff would stall the test if Retry-After was ignored
+	client.Retry.BaseBackoff = time.Hour
+	client.Retry.MaxBackoff = 0
+
+	var result map[string]string
+	err := client.doRequest(context.Background(), "", http.MethodGet, "retry-after", nil, &result, serverToken)
+
+	s.Require().NoError(err)
+	s.Equal(2, attempts)
+}
+
+func (s *PostmarkTestSuite) TestDoRequestRetryContextCanceledDuringBackoff() {
+	s.mux.Get("/retry-canceled", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte(`{"ErrorCode": 0, "Message": "Service Unavailable"}`))
+	})
+
+	client := s.newClient()
+	client.Retry = testRetryPolicy()
+	client.Retry.BaseBackoff = time.Hour
+	client.Retry.MaxBackoff = 0
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	err := client.doRequest(ctx, "", http.MethodGet, "retry-canceled", nil, nil, serverToken)
+
+	s.Require().ErrorIs(err, context.DeadlineExceeded)
+}
+
+func (s *PostmarkTestSuite) TestParseRetryAfter() {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		value     string
+		wantDelay time.Duration
+		wantOK    bool
+	}{
+		{name: "missing header", value: "", wantOK: false},
+		{name: "seconds", value: "7", wantDelay: 7 * time.Second, wantOK: true},
+		{name: "negative seconds", value: "-1", wantOK: false},
+		{name: "http date", value: now.Add(3 * time.Second).Format(http.TimeFormat), wantDelay: 3 * time.Second, wantOK: true},
+		{name: "http date in the past", value: now.Add(-time.Minute).Format(http.TimeFormat), wantDelay: 0, wantOK: true},
+		{name: "garbage", value: "soon", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			header := http.Header{}
+			if tt.value != "" {
+				header.Set("Retry-After", tt.value)
+			}
+
+			delay, ok := parseRetryAfter(header, now)
+
+			s.Equal(tt.wantOK, ok)
+			s.Equal(tt.wantDelay, delay)
+		})
+	}
+}
+
+func (s *PostmarkTestSuite) TestRetryPolicyBackoff() {
+	policy := &RetryPolicy{
+		BaseBackoff: 100 * time.Millisecond,
+		MaxBackoff:  300 * time.Millisecond,
+	}
+
+	s.Equal(100*time.Millisecond, policy.backoff(1, http.Header{}))
+	s.Equal(200*time.Millisecond, policy.backoff(2, http.Header{}))
+	s.Equal(300*time.Millisecond, policy.backoff(3, http.Header{}), "delay should be capped by MaxBackoff")
+
+	policy.Jitter = 0.5
+	for i := 0; i < 20; i++ {
+		delay := policy.backoff(1, http.Header{})
+		s.GreaterOrEqual(delay, 50*time.Millisecond)
+		s.LessOrEqual(delay, 100*time.Millisecond)
+	}
+}
+
+func (s *PostmarkTestSuite) TestRetryPolicyBackoffCapsRetryAfter() {
+	policy := &RetryPolicy{BaseBackoff: time.Second, MaxBackoff: 30 * time.Second}
+
+	s.Equal(2*time.Second, policy.backoff(1, http.Header{"Retry-After": []string{"2"}}))
+	s.Equal(30*time.Second, policy.backoff(1, http.Header{"Retry-After": []string{"86400"}}), "Retry-After should be capped by MaxBackoff")
+
+	farFuture := time.Now().Add(48 * time.Hour).UTC().Format(http.TimeFormat)
+	s.Equal(30*time.Second, policy.backoff(1, http.Header{"Retry-After": []string{farFuture}}))
+}
+
+func (s *PostmarkTestSuite) TestRetryPolicyAttempts() {
+	var nilPolicy *RetryPolicy
+	s.Equal(1, nilPolicy.attempts(http.MethodGet))
+
+	policy := DefaultRetryPolicy()
+	s.Equal(3, policy.attempts(http.MethodGet))
+	s.Equal(3, policy.attempts(http.MethodDelete))
+	s.Equal(1, policy.attempts(http.MethodPost))
+	s.Equal(1, policy.attempts(http.MethodPatch))
+
+	policy.RetryNonIdempotent = true
+	s.Equal(3, policy.attempts(http.MethodPost))
+}
@@ -3,6 +3,7 @@ package postmark
 import (
 	"context"
 	"fmt"
+	"iter"
 	"net/url"
 )
 
@@ -130,53 +131,47 @@ func (client *Client) GetSenderSignatures(ctx context.Context, count, offset int
 	values.Add("count", fmt.Sprintf("%d", count))
 	values.Add("offset", fmt.Sprintf("%d", offset))
 
-	err := client.getWithAccountToken(ctx, buildURLWithQuery("senders", *values), &res)
+	err := client.getWithAccountToken(ctx, "GetSenderSignatures", buildURLWithQuery("senders", *values), &res)
 	return res, err
 }
 
+// SenderSignatures returns an iterator over every sender signature on the account, fetching pages as needed
+func (client *Client) SenderSignatures(ctx context.Context) iter.Seq2[SenderSignature, error] {
+	return paginate(ctx, pager[SenderSignature]{
+		fetch: func(ctx context.Context, count, offset int64, _ map[string]interface{}) ([]SenderSignature, int64, error) {
+			res, err := client.GetSenderSignatures(ctx, count, offset)
+			return res.SenderSignatures, int64(res.TotalCount), err
+		},
+	}, nil)
+}
+
 // GetSenderSignature gets all the details for a specific sender signature.
 func (client *Client) GetSenderSignature(ctx context.Context, signatureID int64) (SenderSignatureDetails, error) {
 	var res SenderSignatureDetails
-	err := client.getWithAccountToken(ctx, fmt.Sprintf("senders/%d", signatureID), &res)
+	err := client.getWithAccountToken(ctx, "GetSenderSignature", fmt.Sprintf("senders/%d", signatureID), &res)
 	return res, err
 }
 
 // CreateSenderSignature creates a new sender signature and returns the full details of the new sender signature.
 func (client *Client) CreateSenderSignature(ctx context.Context, request SenderSignatureCreateRequest) (SenderSignatureDetails, error) {
 	var res SenderSignatureDetails
-	err := client.postWithAccountToken(ctx, "senders", request, &res)
+	err := client.postWithAccountToken(ctx, "CreateSenderSignature", "senders", request, &res)
 	return res, err
 }
 
 // EditSenderSignature updates an existing sender signature and returns the full details of the updated sender signature.
 func (client *Client) EditSenderSignature(ctx context.Context, signatureID int64, request SenderSignatureEditRequest) (SenderSignatureDetails, error) {
 	var res SenderSignatureDetails
-	err := client.putWithAccountToken(ctx, fmt.Sprintf("senders/%d", signatureID), request, &res)
+	err := client.putWithAccountToken(ctx, "EditSenderSignature", fmt.Sprintf("senders/%d", signatureID), request, &res)
 	return res, err
 }
 
 // DeleteSenderSignature removes a sender from the server.
 func (client *Client) DeleteSenderSignature(ctx context.Context, signatureID int64) error {
-	res := APIError{}
-	err := client.deleteWithAccountToken(ctx, fmt.Sprintf("senders/%d", signatureID), &res)
-	if err != nil {
-		return err
-	}
-	if res.ErrorCode != 0 {
-		return res
-	}
-	return nil
+	return client.deleteWithAccountToken(ctx, "DeleteSenderSignature", fmt.Sprintf("senders/%d", signatureID), &APIError{})
 }
 
 // ResendSenderSignatureConfirmation resends the confirmation email for a sender signature.
 func (client *Client) ResendSenderSignatureConfirmation(ctx context.Context, signatureID int64) error {
-	res := APIError{}
-	err := client.postWithAccountToken(ctx, fmt.Sprintf("senders/%d/resend", signatureID), nil, &res)
-	if err != nil {
-		return err
-	}
-	if res.ErrorCode != 0 {
-		return res
-	}
-	return nil
+	return client.postWithAccountToken(ctx, "ResendSenderSignatureConfirmation", fmt.Sprintf("senders/%d/resend", signatureID), nil, &APIError{})
 }
@@ -8,7 +8,7 @@ import (
 // with the currently in-use server API Key
 func (client *Client) GetCurrentServer(ctx context.Context) (Server, error) {
 	res := Server{}
-	err := client.get(ctx, "server", &res)
+	err := client.get(ctx, "GetCurrentServer", "server", &res)
 	return res, err
 }
 
@@ -16,6 +16,6 @@ func (client *Client) GetCurrentServer(ctx context.Context) (Server, error) {
 // with the currently in-use server API Key
 func (client *Client) EditCurrentServer(ctx context.Context, server Server) (Server, error) {
 	res := Server{}
-	err := client.put(ctx, "server", server, &res)
+	err := client.put(ctx, "EditCurrentServer", "server", server, &res)
 	return res, err
 }
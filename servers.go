@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"iter"
 	"net/url"
 )
 
@@ -168,7 +169,7 @@ func (s Server) MarshalJSON() ([]byte, error) {
 // GetServer fetches a specific server via serverID
 func (client *Client) GetServer(ctx context.Context, serverID int64) (Server, error) {
 	res := Server{}
-	err := client.getWithAccountToken(ctx, fmt.Sprintf("servers/%d", serverID), &res)
+	err := client.getWithAccountToken(ctx, "GetServer", fmt.Sprintf("servers/%d", serverID), &res)
 	return res, err
 }
 
@@ -186,33 +187,36 @@ func (client *Client) GetServers(ctx context.Context, count, offset int64, name
 		values.Add("name", name)
 	}
 
-	err := client.getWithAccountToken(ctx, buildURLWithQuery("servers", *values), &res)
+	err := client.getWithAccountToken(ctx, "GetServers", buildURLWithQuery("servers", *values), &res)
 	return res, err
 }
 
+// Servers returns an iterator over every server on the account, fetching pages as needed
+// Optionally filter by a specific server name, see GetServers
+func (client *Client) Servers(ctx context.Context, name string) iter.Seq2[Server, error] {
+	return paginate(ctx, pager[Server]{
+		fetch: func(ctx context.Context, count, offset int64, _ map[string]interface{}) ([]Server, int64, error) {
+			res, err := client.GetServers(ctx, count, offset, name)
+			return res.Servers, int64(res.TotalCount), err
+		},
+	}, nil)
+}
+
 // EditServer updates details for a specific server with serverID
 func (client *Client) EditServer(ctx context.Context, serverID int64, request ServerEditRequest) (Server, error) {
 	res := Server{}
-	err := client.putWithAccountToken(ctx, fmt.Sprintf("servers/%d", serverID), request, &res)
+	err := client.putWithAccountToken(ctx, "EditServer", fmt.Sprintf("servers/%d", serverID), request, &res)
 	return res, err
 }
 
 // CreateServer creates a server
 func (client *Client) CreateServer(ctx context.Context, request ServerCreateRequest) (Server, error) {
 	res := Server{}
-	err := client.postWithAccountToken(ctx, "servers", request, &res)
+	err := client.postWithAccountToken(ctx, "CreateServer", "servers", request, &res)
 	return res, err
 }
 
 // DeleteServer removes a server.
 func (client *Client) DeleteServer(ctx context.Context, serverID int64) error {
-	res := APIError{}
-	err := client.deleteWithAccountToken(ctx, fmt.Sprintf("servers/%d", serverID), &res)
-	if err != nil {
-		return err
-	}
-	if res.ErrorCode != 0 {
-		return res
-	}
-	return nil
+	return client.deleteWithAccountToken(ctx, "DeleteServer", fmt.Sprintf("servers/%d", serverID), &APIError{})
 }
@@ -33,10 +33,10 @@ type OutboundStats struct {
 }
 
 // GetOutboundStats - Gets a brief overview of statistics for all of your outbound email.
-// Available options: http://developer.postmarkapp.com/developer-api-stats.html#overview
+// Available options: http://developer.postmarkapp.com/developer-api-stats.html#overview (see StatsFilter)
 func (client *Client) GetOutboundStats(ctx context.Context, options map[string]interface{}) (OutboundStats, error) {
 	res := OutboundStats{}
-	err := client.get(ctx, buildURL("stats/outbound", options), &res)
+	err := client.get(ctx, "GetOutboundStats", buildURL("stats/outbound", options), &res)
 	return res, err
 }
 
@@ -60,7 +60,7 @@ type SendCounts struct {
 // Available options: http://developer.postmarkapp.com/developer-api-stats.html#sent-counts
 func (client *Client) GetSentCounts(ctx context.Context, options map[string]interface{}) (SendCounts, error) {
 	res := SendCounts{}
-	err := client.get(ctx, buildURL("stats/outbound/sends", options), &res)
+	err := client.get(ctx, "GetSentCounts", buildURL("stats/outbound/sends", options), &res)
 	return res, err
 }
 
@@ -96,7 +96,7 @@ type BounceCounts struct {
 // Available options: http://developer.postmarkapp.com/developer-api-stats.html#bounce-counts
 func (client *Client) GetBounceCounts(ctx context.Context, options map[string]interface{}) (BounceCounts, error) {
 	res := BounceCounts{}
-	err := client.get(ctx, buildURL("stats/outbound/bounces", options), &res)
+	err := client.get(ctx, "GetBounceCounts", buildURL("stats/outbound/bounces", options), &res)
 	return res, err
 }
 
@@ -121,7 +121,7 @@ type SpamCounts struct {
 // Available options: http://developer.postmarkapp.com/developer-api-stats.html#spam-complaints
 func (client *Client) GetSpamCounts(ctx context.Context, options map[string]interface{}) (SpamCounts, error) {
 	res := SpamCounts{}
-	err := client.get(ctx, buildURL("stats/outbound/spam", options), &res)
+	err := client.get(ctx, "GetSpamCounts", buildURL("stats/outbound/spam", options), &res)
 	return res, err
 }
 
@@ -145,7 +145,7 @@ type TrackedCounts struct {
 // Available options: http://developer.postmarkapp.com/developer-api-stats.html#email-tracked-count
 func (client *Client) GetTrackedCounts(ctx context.Context, options map[string]interface{}) (TrackedCounts, error) {
 	res := TrackedCounts{}
-	err := client.get(ctx, buildURL("stats/outbound/tracked", options), &res)
+	err := client.get(ctx, "GetTrackedCounts", buildURL("stats/outbound/tracked", options), &res)
 	return res, err
 }
 
@@ -173,7 +173,7 @@ type OpenCounts struct {
 // Available options: http://developer.postmarkapp.com/developer-api-stats.html#email-opens-count
 func (client *Client) GetOpenCounts(ctx context.Context, options map[string]interface{}) (OpenCounts, error) {
 	res := OpenCounts{}
-	err := client.get(ctx, buildURL("stats/outbound/opens", options), &res)
+	err := client.get(ctx, "GetOpenCounts", buildURL("stats/outbound/opens", options), &res)
 	return res, err
 }
 
@@ -215,7 +215,7 @@ type PlatformDay struct {
 // GetPlatformCounts gets the email platform usage
 func (client *Client) GetPlatformCounts(ctx context.Context, options map[string]interface{}) (PlatformCounts, error) {
 	res := PlatformCounts{}
-	err := client.get(ctx, buildURL("stats/outbound/platform", options), &res)
+	err := client.get(ctx, "GetPlatformCounts", buildURL("stats/outbound/platform", options), &res)
 	return res, err
 }
 
@@ -243,7 +243,7 @@ type ClickCounts struct {
 // Available options: http://developer.postmarkapp.com/developer-api-stats.html#click-counts
 func (client *Client) GetClickCounts(ctx context.Context, options map[string]interface{}) (ClickCounts, error) {
 	res := ClickCounts{}
-	err := client.get(ctx, buildURL("stats/outbound/clicks", options), &res)
+	err := client.get(ctx, "GetClickCounts", buildURL("stats/outbound/clicks", options), &res)
 	return res, err
 }
 
@@ -287,7 +287,7 @@ type BrowserFamilyCounts struct {
 // Available options: http://developer.postmarkapp.com/developer-api-stats.html#browser-usage
 func (client *Client) GetBrowserFamilyCounts(ctx context.Context, options map[string]interface{}) (BrowserFamilyCounts, error) {
 	res := BrowserFamilyCounts{}
-	err := client.get(ctx, buildURL("stats/outbound/clicks/browserfamilies", options), &res)
+	err := client.get(ctx, "GetBrowserFamilyCounts", buildURL("stats/outbound/clicks/browserfamilies", options), &res)
 	return res, err
 }
 
@@ -315,7 +315,7 @@ type ClickLocationCounts struct {
 // Available options: http://developer.postmarkapp.com/developer-api-stats.html#click-location
 func (client *Client) GetClickLocationCounts(ctx context.Context, options map[string]interface{}) (ClickLocationCounts, error) {
 	res := ClickLocationCounts{}
-	err := client.get(ctx, buildURL("stats/outbound/clicks/location", options), &res)
+	err := client.get(ctx, "GetClickLocationCounts", buildURL("stats/outbound/clicks/location", options), &res)
 	return res, err
 }
 
@@ -351,7 +351,7 @@ type ClickPlatformCounts struct {
 // Available options: http://developer.postmarkapp.com/developer-api-stats.html#browser-platform-usage
 func (client *Client) GetClickPlatformCounts(ctx context.Context, options map[string]interface{}) (ClickPlatformCounts, error) {
 	res := ClickPlatformCounts{}
-	err := client.get(ctx, buildURL("stats/outbound/clicks/platforms", options), &res)
+	err := client.get(ctx, "GetClickPlatformCounts", buildURL("stats/outbound/clicks/platforms", options), &res)
 	return res, err
 }
 
@@ -395,6 +395,6 @@ type EmailClientCounts struct {
 // Available options: http://developer.postmarkapp.com/developer-api-stats.html#email-client-usage
 func (client *Client) GetEmailClientCounts(ctx context.Context, options map[string]interface{}) (EmailClientCounts, error) {
 	res := EmailClientCounts{}
-	err := client.get(ctx, buildURL("stats/outbound/opens/emailclients", options), &res)
+	err := client.get(ctx, "GetEmailClientCounts", buildURL("stats/outbound/opens/emailclients", options), &res)
 	return res, err
 }
@@ -106,7 +106,7 @@ func (client *Client) GetSuppressions(
 	path := fmt.Sprintf("message-streams/%s/suppressions/dump", streamID)
 
 	res := suppressionsResponse{}
-	err := client.get(ctx, buildURL(path, options), &res)
+	err := client.get(ctx, "GetSuppressions", buildURL(path, options), &res)
 	return res.Suppressions, err
 }
 
@@ -118,7 +118,7 @@ func (client *Client) CreateSuppressions(
 ) ([]SuppressionResponse, error) {
 	res := updateSuppressionsResponse{}
 	path := fmt.Sprintf("message-streams/%s/suppressions", streamID)
-	err := client.post(ctx, path, suppressionsRequest{Suppressions: suppressions}, &res)
+	err := client.post(ctx, "CreateSuppressions", path, suppressionsRequest{Suppressions: suppressions}, &res)
 	return res.Suppressions, err
 }
 
@@ -131,6 +131,6 @@ func (client *Client) DeleteSuppressions(
 ) ([]SuppressionResponse, error) {
 	res := updateSuppressionsResponse{}
 	path := fmt.Sprintf("message-streams/%s/suppressions/delete", streamID)
-	err := client.post(ctx, path, suppressionsRequest{Suppressions: suppressions}, &res)
+	err := client.post(ctx, "DeleteSuppressions", path, suppressionsRequest{Suppressions: suppressions}, &res)
 	return res.Suppressions, err
 }
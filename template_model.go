@@ -0,0 +1,120 @@
+package postmark
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// SuggestModel returns the model a template expects, shaped like the SuggestedTemplateModel of ValidateTemplate:
+// values are "name_Value" strings, sections are objects and {{#each}} loops are lists of one item.
+// The fields used by the layout of the template are included.
+func (r *TemplateRenderer) SuggestModel(template Template) (map[string]interface{}, error) {
+	sources := []struct{ field, source string }{
+		{"Subject", template.Subject},
+		{"HTMLBody", template.HTMLBody},
+		{"TextBody", template.TextBody},
+	}
+	if template.LayoutTemplate != "" {
+		layout, ok := r.find(0, template.LayoutTemplate)
+		if !ok {
+			return nil, fmt.Errorf("%w: layout %q", ErrTemplateNotFound, template.LayoutTemplate)
+		}
+		sources = append(sources,
+			struct{ field, source string }{"HTMLBody", layout.HTMLBody},
+			struct{ field, source string }{"TextBody", layout.TextBody},
+		)
+	}
+
+	model := map[string]interface{}{}
+	var errs []error
+	for _, source := range sources {
+		nodes, err := parseMustachio(source.field, source.source)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		suggestMustachio(nodes, []map[string]interface{}{model})
+	}
+	return model, errors.Join(errs...)
+}
+
+// suggestMustachio adds the paths used by nodes to the model, scopes holds the objects of the enclosing sections
+func suggestMustachio(nodes []mustachioNode, scopes []map[string]interface{}) {
+	for _, node := range nodes {
+		switch node.kind {
+		case mustachioText:
+		case mustachioValue, mustachioRawValue, mustachioInverted:
+			if object, name := suggestPath(scopes, node.text); object != nil {
+				if _, ok := object[name]; !ok {
+					object[name] = name + "_Value"
+				}
+			}
+			if node.kind == mustachioInverted {
+				suggestMustachio(node.children, scopes)
+			}
+		case mustachioSection:
+			object, name := suggestPath(scopes, node.text)
+			if object == nil {
+				suggestMustachio(node.children, scopes)
+				continue
+			}
+			inner, ok := object[name].(map[string]interface{})
+			if !ok {
+				inner = map[string]interface{}{}
+			}
+			suggestMustachio(node.children, append(scopes, inner))
+			if len(inner) > 0 {
+				object[name] = inner
+			} else if _, ok = object[name]; !ok {
+				object[name] = name + "_Value"
+			}
+		case mustachioEach:
+			object, name := suggestPath(scopes, node.text)
+			if object == nil {
+				continue
+			}
+			item := map[string]interface{}{}
+			if list, ok := object[name].([]interface{}); ok && len(list) > 0 {
+				if existing, ok := list[0].(map[string]interface{}); ok {
+					item = existing
+				}
+			}
+			suggestMustachio(node.children, append(scopes, item))
+			if len(item) > 0 {
+				object[name] = []interface{}{item}
+			} else if _, ok := object[name].([]interface{}); !ok {
+				object[name] = []interface{}{name + "_Value"}
+			}
+		}
+	}
+}
+
+// suggestPath returns the object holding the last name of a path, creating the objects on the way.
+// It returns nil for the scope itself and for the @content placeholder of layouts.
+func suggestPath(scopes []map[string]interface{}, path string) (map[string]interface{}, string) {
+	depth := len(scopes) - 1
+	for path == ".." || strings.HasPrefix(path, "../") {
+		path = strings.TrimPrefix(strings.TrimPrefix(path, ".."), "/")
+		depth = max(0, depth-1)
+	}
+	if path == "~" || strings.HasPrefix(path, "~.") {
+		depth = 0
+		path = strings.TrimPrefix(strings.TrimPrefix(path, "~"), ".")
+	}
+	if path == "" || path == "." || strings.HasPrefix(path, "@") {
+		return nil, ""
+	}
+
+	object := scopes[depth]
+	names := strings.Split(path, ".")
+	for _, name := range names[:len(names)-1] {
+		inner, ok := object[name].(map[string]interface{})
+		if !ok {
+			inner = map[string]interface{}{}
+			object[name] = inner
+		}
+		object = inner
+	}
+	return object, names[len(names)-1]
+}
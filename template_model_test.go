@@ -0,0 +1,35 @@
+package postmark
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTemplateRendererSuggestModel(t *testing.T) {
+	layout := Template{Alias: "basic", TemplateType: "Layout", HTMLBody: "<h1>{{ company.name }}</h1>{{{ @content }}}"}
+	renderer := NewTemplateRenderer(layout)
+
+	model, err := renderer.SuggestModel(Template{
+		Subject:        "{{#company}}{{name}}{{/company}} {{subjectHeadline}}",
+		TextBody:       "{{#company}}{{address}}{{/company}}{{#each person}} {{name}} {{../subjectHeadline}}{{/each}}",
+		HTMLBody:       "{{#premium}}VIP{{/premium}}{{^tags}}none{{/tags}}{{#each tags}}{{.}}{{/each}}{{~.footer}}",
+		LayoutTemplate: "basic",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"company":         map[string]interface{}{"name": "name_Value", "address": "address_Value"},
+		"subjectHeadline": "subjectHeadline_Value",
+		"person":          []interface{}{map[string]interface{}{"name": "name_Value"}},
+		"premium":         "premium_Value",
+		"tags":            []interface{}{"tags_Value"},
+		"footer":          "footer_Value",
+	}, model)
+
+	_, err = renderer.SuggestModel(Template{Subject: "{{#a}}"})
+	require.ErrorIs(t, err, ErrTemplateSyntax)
+
+	_, err = renderer.SuggestModel(Template{Subject: "x", LayoutTemplate: "missing"})
+	require.ErrorIs(t, err, ErrTemplateNotFound)
+}
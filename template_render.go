@@ -0,0 +1,129 @@
+package postmark
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+)
+
+// RenderedTemplate is the content of a template rendered with a model
+type RenderedTemplate struct {
+	// Subject: rendered subject
+	Subject string
+	// HTMLBody: rendered HTML body, inside its layout if the template has one
+	HTMLBody string
+	// TextBody: rendered text body, inside its layout if the template has one
+	TextBody string
+}
+
+// TemplateRenderer renders templates locally with Postmark's Mustachio language, without calling the API.
+// Values are HTML escaped in the HTMLBody only, like Postmark does.
+type TemplateRenderer struct {
+	templates []Template
+}
+
+// NewTemplateRenderer returns a renderer that knows the templates and layouts, they are used to resolve
+// LayoutTemplate and the template of a TemplatedEmail
+func NewTemplateRenderer(templates ...Template) *TemplateRenderer {
+	return &TemplateRenderer{templates: templates}
+}
+
+// Render renders the Subject, HTMLBody and TextBody of a template with a model, a map or a struct.
+// When the template has a LayoutTemplate, the rendered bodies replace {{{ @content }}} in the layout.
+func (r *TemplateRenderer) Render(template Template, model interface{}) (RenderedTemplate, error) {
+	root, err := mustachioModel(model)
+	if err != nil {
+		return RenderedTemplate{}, err
+	}
+	scope := &mustachioScope{value: root}
+
+	var layout Template
+	if template.LayoutTemplate != "" {
+		var ok bool
+		if layout, ok = r.find(0, template.LayoutTemplate); !ok {
+			return RenderedTemplate{}, fmt.Errorf("%w: layout %q", ErrTemplateNotFound, template.LayoutTemplate)
+		}
+	}
+
+	subject, subjectErr := renderField("Subject", template.Subject, scope, false, nil)
+	html, htmlErr := renderField("HTMLBody", template.HTMLBody, scope, true, nil)
+	text, textErr := renderField("TextBody", template.TextBody, scope, false, nil)
+	if err = errors.Join(subjectErr, htmlErr, textErr); err != nil {
+		return RenderedTemplate{}, err
+	}
+
+	if layout.HTMLBody != "" && html != "" {
+		html, htmlErr = renderField("HTMLBody", layout.HTMLBody, scope, true, &html)
+	}
+	if layout.TextBody != "" && text != "" {
+		text, textErr = renderField("TextBody", layout.TextBody, scope, false, &text)
+	}
+	if err = errors.Join(htmlErr, textErr); err != nil {
+		return RenderedTemplate{}, fmt.Errorf("layout %q: %w", template.LayoutTemplate, err)
+	}
+
+	return RenderedTemplate{Subject: subject, HTMLBody: html, TextBody: text}, nil
+}
+
+// RenderEmail renders the template of a templated email, found by TemplateID or TemplateAlias,
+// and returns the email Postmark would send
+func (r *TemplateRenderer) RenderEmail(email TemplatedEmail) (Email, error) {
+	template, ok := r.find(email.TemplateID, email.TemplateAlias)
+	if !ok {
+		return Email{}, fmt.Errorf("%w: %s", ErrTemplateNotFound, templateRef(email.TemplateID, email.TemplateAlias))
+	}
+
+	rendered, err := r.Render(template, email.TemplateModel)
+	if err != nil {
+		return Email{}, err
+	}
+
+	return Email{
+		From:          email.From,
+		To:            email.To,
+		Cc:            email.Cc,
+		Bcc:           email.Bcc,
+		Subject:       rendered.Subject,
+		Tag:           email.Tag,
+		HTMLBody:      rendered.HTMLBody,
+		TextBody:      rendered.TextBody,
+		ReplyTo:       email.ReplyTo,
+		Headers:       email.Headers,
+		TrackOpens:    email.TrackOpens,
+		TrackLinks:    email.TrackLinks,
+		Attachments:   email.Attachments,
+		Metadata:      email.Metadata,
+		MessageStream: email.MessageStream,
+		InlineCSS:     email.InlineCSS,
+	}, nil
+}
+
+// find returns the template with the ID, or else the alias
+func (r *TemplateRenderer) find(id int64, alias string) (Template, bool) {
+	for _, template := range r.templates {
+		if (id != 0 && template.TemplateID == id) || (id == 0 && alias != "" && template.Alias == alias) {
+			return template, true
+		}
+	}
+	return Template{}, false
+}
+
+// renderField parses and renders one template field
+func renderField(field, source string, scope *mustachioScope, escape bool, content *string) (string, error) {
+	nodes, err := parseMustachio(field, source)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	renderMustachio(&buf, nodes, scope, escape, content)
+	return buf.String(), nil
+}
+
+// templateRef describes a template by ID or alias in errors
+func templateRef(id int64, alias string) string {
+	if id != 0 {
+		return fmt.Sprintf("template %d", id)
+	}
+	return fmt.Sprintf("template %q", alias)
+}
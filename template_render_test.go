@@ -0,0 +1,67 @@
+package postmark
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTemplateRenderer(t *testing.T) {
+	layout := Template{
+		Alias:        "basic",
+		TemplateType: "Layout",
+		HTMLBody:     "<html><body><h1>{{company}}</h1>{{{ @content }}}</body></html>",
+		TextBody:     "{{company}}\n\n{{{@content}}}\n-- \nThe team",
+	}
+	welcome := Template{
+		TemplateID:     7,
+		Alias:          "welcome",
+		Subject:        "Welcome {{name}}",
+		HTMLBody:       "<p>Hello {{name}}</p>{{#items}}<li>{{.}}</li>{{/items}}",
+		TextBody:       "Hello {{name}}",
+		LayoutTemplate: "basic",
+	}
+	renderer := NewTemplateRenderer(layout, welcome)
+
+	type welcomeModel struct {
+		Name    string   `json:"name"`
+		Company string   `json:"company"`
+		Items   []string `json:"items"`
+	}
+	rendered, err := renderer.Render(welcome, welcomeModel{Name: "Tom & Jerry", Company: "Acme", Items: []string{"a"}})
+	require.NoError(t, err)
+	assert.Equal(t, RenderedTemplate{
+		Subject:  "Welcome Tom & Jerry",
+		HTMLBody: "<html><body><h1>Acme</h1><p>Hello Tom &amp; Jerry</p><li>a</li></body></html>",
+		TextBody: "Acme\n\nHello Tom & Jerry\n-- \nThe team",
+	}, rendered)
+
+	email, err := renderer.RenderEmail(TemplatedEmail{
+		TemplateAlias: "welcome",
+		TemplateModel: map[string]interface{}{"name": "Jane", "company": "Acme"},
+		From:          "app@example.com",
+		To:            "jane@example.com",
+		Tag:           "welcome",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Welcome Jane", email.Subject)
+	assert.Equal(t, "app@example.com", email.From)
+	assert.Equal(t, "welcome", email.Tag)
+	assert.Contains(t, email.HTMLBody, "<p>Hello Jane</p>")
+
+	email, err = renderer.RenderEmail(TemplatedEmail{TemplateID: 7})
+	require.NoError(t, err)
+	assert.Equal(t, "Welcome ", email.Subject)
+
+	_, err = renderer.RenderEmail(TemplatedEmail{TemplateAlias: "missing"})
+	require.ErrorIs(t, err, ErrTemplateNotFound)
+
+	_, err = NewTemplateRenderer().Render(welcome, nil)
+	require.ErrorIs(t, err, ErrTemplateNotFound)
+
+	_, err = renderer.Render(Template{Subject: "{{#a}}", TextBody: "{{b"}, nil)
+	require.ErrorIs(t, err, ErrTemplateSyntax)
+	assert.Contains(t, err.Error(), "Subject: section {{#a}} is not closed")
+	assert.Contains(t, err.Error(), "TextBody: tag is not closed")
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"iter"
 	"net/url"
 	"strings"
 )
@@ -62,7 +63,7 @@ type TemplateInfo struct {
 // GetTemplate fetches a specific template via TemplateID
 func (client *Client) GetTemplate(ctx context.Context, templateID string) (Template, error) {
 	res := Template{}
-	err := client.get(ctx, fmt.Sprintf("templates/%s", templateID), &res)
+	err := client.get(ctx, "GetTemplate", fmt.Sprintf("templates/%s", templateID), &res)
 	return res, err
 }
 
@@ -96,35 +97,37 @@ func (client *Client) GetTemplatesFiltered(ctx context.Context, count, offset in
 		values.Add("LayoutTemplate", layoutTemplate)
 	}
 
-	err := client.get(ctx, buildURLWithQuery("templates", *values), &res)
+	err := client.get(ctx, "GetTemplatesFiltered", buildURLWithQuery("templates", *values), &res)
 	return res.Templates, res.TotalCount, err
 }
 
+// Templates returns an iterator over every template on the server, fetching pages as needed
+// templateType and layoutTemplate filter the same way as GetTemplatesFiltered
+func (client *Client) Templates(ctx context.Context, templateType, layoutTemplate string) iter.Seq2[TemplateInfo, error] {
+	return paginate(ctx, pager[TemplateInfo]{
+		fetch: func(ctx context.Context, count, offset int64, _ map[string]interface{}) ([]TemplateInfo, int64, error) {
+			return client.GetTemplatesFiltered(ctx, count, offset, templateType, layoutTemplate)
+		},
+	}, nil)
+}
+
 // CreateTemplate saves a new template to the server
 func (client *Client) CreateTemplate(ctx context.Context, template Template) (TemplateInfo, error) {
 	res := TemplateInfo{}
-	err := client.post(ctx, "templates", template, &res)
+	err := client.post(ctx, "CreateTemplate", "templates", template, &res)
 	return res, err
 }
 
 // EditTemplate updates details for a specific template with templateID
 func (client *Client) EditTemplate(ctx context.Context, templateID string, template Template) (TemplateInfo, error) {
 	res := TemplateInfo{}
-	err := client.put(ctx, fmt.Sprintf("templates/%s", templateID), template, &res)
+	err := client.put(ctx, "EditTemplate", fmt.Sprintf("templates/%s", templateID), template, &res)
 	return res, err
 }
 
 // DeleteTemplate removes a template (with templateID) from the server
 func (client *Client) DeleteTemplate(ctx context.Context, templateID string) error {
-	res := APIError{}
-	err := client.delete(ctx, fmt.Sprintf("templates/%s", templateID), &res)
-	if err != nil {
-		return err
-	}
-	if res.ErrorCode != 0 {
-		return res
-	}
-	return nil
+	return client.delete(ctx, "DeleteTemplate", fmt.Sprintf("templates/%s", templateID), &APIError{})
 }
 
 // ValidateTemplateBody contains the template/render model combination to be validated
@@ -162,7 +165,7 @@ type ValidationError struct {
 // ValidateTemplate validates the provided template/render model combination
 func (client *Client) ValidateTemplate(ctx context.Context, validateTemplateBody ValidateTemplateBody) (ValidateTemplateResponse, error) {
 	res := ValidateTemplateResponse{}
-	err := client.post(ctx, "templates/validate", validateTemplateBody, &res)
+	err := client.post(ctx, "ValidateTemplate", "templates/validate", validateTemplateBody, &res)
 	return res, err
 }
 
@@ -208,10 +211,15 @@ func (client *Client) SendTemplatedEmail(ctx context.Context, email TemplatedEma
 	if err := validateTemplateAlias(email.TemplateAlias); err != nil {
 		return EmailResponse{}, err
 	}
+	if client.ValidateEmails {
+		if err := email.Validate(); err != nil {
+			return EmailResponse{}, err
+		}
+	}
 
 	res := EmailResponse{}
-	err := client.post(ctx, "email/withTemplate", email, &res)
-	return res, err
+	err := client.post(ctx, "SendTemplatedEmail", "email/withTemplate", email, &res)
+	return res, sendFailed(err)
 }
 
 // SendTemplatedEmailBatch sends batch email using a template (TemplateID)
@@ -222,13 +230,18 @@ func (client *Client) SendTemplatedEmailBatch(ctx context.Context, emails []Temp
 			return nil, fmt.Errorf("email %d: %w", i, err)
 		}
 	}
+	if client.ValidateEmails {
+		if err := ValidateTemplatedEmailBatch(emails); err != nil {
+			return nil, err
+		}
+	}
 
 	var res []EmailResponse
 	formatEmails := map[string]interface{}{
 		"Messages": emails,
 	}
-	err := client.post(ctx, "email/batchWithTemplates", formatEmails, &res)
-	return res, err
+	err := client.post(ctx, "SendTemplatedEmailBatch", "email/batchWithTemplates", formatEmails, &res)
+	return res, sendFailed(err)
 }
 
 // PushTemplatesRequest contains the request data for pushing templates between servers
@@ -264,6 +277,6 @@ type PushTemplatesResponse struct {
 // PushTemplates pushes templates from one server to another
 func (client *Client) PushTemplates(ctx context.Context, request PushTemplatesRequest) (PushTemplatesResponse, error) {
 	res := PushTemplatesResponse{}
-	err := client.putWithAccountToken(ctx, "templates/push", request, &res)
+	err := client.putWithAccountToken(ctx, "PushTemplates", "templates/push", request, &res)
 	return res, err
 }
@@ -0,0 +1,257 @@
+package postmark
+
+import (
+	"fmt"
+	"net/mail"
+	"net/textproto"
+	"slices"
+	"strings"
+)
+
+// Postmark's documented sending limits
+const (
+	// MaxRecipients is the largest number of To, Cc and Bcc recipients of one message
+	MaxRecipients = 50
+	// MaxMessageSize is the largest total size of one message, including attachments
+	MaxMessageSize = 10 << 20
+	// MaxBatchMessages is the largest number of messages in one batch
+	MaxBatchMessages = 500
+)
+
+// ErrInvalidAddress is the sentinel of address fields that cannot be parsed, it matches ErrInvalidEmailRequest
+var ErrInvalidAddress = fmt.Errorf("%w: invalid address", ErrInvalidEmailRequest)
+
+// FieldError is a problem with one field of an email, found before sending it
+type FieldError struct {
+	// Field is the JSON name of the field, e.g. "Attachments[0].Content" or "[3].To" in a batch
+	Field string
+	// Message describes the problem
+	Message string
+	// Err is the sentinel error Postmark would answer with, e.g. ErrInvalidEmailRequest
+	Err error
+}
+
+// Error returns the field and the problem
+func (e FieldError) Error() string {
+	return e.Field + ": " + e.Message
+}
+
+// Unwrap returns the sentinel error of the problem
+func (e FieldError) Unwrap() error {
+	return e.Err
+}
+
+// EmailValidationError lists every problem found by Validate, it matches the sentinel errors of its problems with errors.Is
+type EmailValidationError struct {
+	Errors []FieldError
+}
+
+// Error returns every problem
+func (e EmailValidationError) Error() string {
+	problems := make([]string, 0, len(e.Errors))
+	for _, fieldErr := range e.Errors {
+		problems = append(problems, fieldErr.Error())
+	}
+	return "invalid email: " + strings.Join(problems, "; ")
+}
+
+// Unwrap returns the problems
+func (e EmailValidationError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Errors))
+	for _, fieldErr := range e.Errors {
+		errs = append(errs, fieldErr)
+	}
+	return errs
+}
+
+// trackLinksOptions returns the accepted TrackLinks values
+func trackLinksOptions() []string {
+	return []string{"None", "HtmlAndText", "HtmlOnly", "TextOnly"}
+}
+
+// reservedHeaders returns the header names that Postmark sets itself, or that have a dedicated field
+func reservedHeaders() []string {
+	return []string{
+		"From", "To", "Cc", "Bcc", "Subject", "Reply-To", "Sender", "Return-Path", "Date",
+		"Mime-Version", "Content-Type", "Content-Transfer-Encoding",
+	}
+}
+
+// validator collects the problems of an email
+type validator struct {
+	prefix string
+	errs   []FieldError
+}
+
+// add records a problem with a field
+func (v *validator) add(field string, err error, format string, args ...interface{}) {
+	v.errs = append(v.errs, FieldError{Field: v.prefix + field, Message: fmt.Sprintf(format, args...), Err: err})
+}
+
+// err returns the problems as an EmailValidationError, nil when there are none
+func (v *validator) err() error {
+	if len(v.errs) == 0 {
+		return nil
+	}
+	return EmailValidationError{Errors: v.errs}
+}
+
+// from checks the sender address
+func (v *validator) from(from string) {
+	if strings.TrimSpace(from) == "" {
+		v.add("From", ErrInvalidEmailRequest, "is required")
+		return
+	}
+	if _, err := mail.ParseAddress(from); err != nil {
+		v.add("From", ErrInvalidAddress, "invalid address %q", from)
+	}
+}
+
+// recipients checks the To, Cc and Bcc addresses and their total count
+func (v *validator) recipients(to, cc, bcc string) {
+	count := 0
+	for _, field := range []struct{ name, value string }{{"To", to}, {"Cc", cc}, {"Bcc", bcc}} {
+		if strings.TrimSpace(field.value) == "" {
+			continue
+		}
+		addresses, err := mail.ParseAddressList(field.value)
+		if err != nil {
+			v.add(field.name, ErrInvalidAddress, "invalid address list %q", field.value)
+			continue
+		}
+		count += len(addresses)
+	}
+
+	switch {
+	case count == 0 && strings.TrimSpace(to+cc+bcc) == "":
+		v.add("To", ErrInvalidEmailRequest, "is required")
+	case count > MaxRecipients:
+		v.add("To", ErrInvalidEmailRequest, "%d recipients in To, Cc and Bcc, the maximum is %d", count, MaxRecipients)
+	}
+}
+
+// replyTo checks the Reply To addresses
+func (v *validator) replyTo(replyTo string) {
+	if strings.TrimSpace(replyTo) == "" {
+		return
+	}
+	if _, err := mail.ParseAddressList(replyTo); err != nil {
+		v.add("ReplyTo", ErrInvalidAddress, "invalid address list %q", replyTo)
+	}
+}
+
+// trackLinks checks the link tracking option
+func (v *validator) trackLinks(trackLinks string) {
+	if trackLinks != "" && !slices.Contains(trackLinksOptions(), trackLinks) {
+		v.add("TrackLinks", ErrInvalidEmailRequest, "%q is not one of %s", trackLinks, strings.Join(trackLinksOptions(), ", "))
+	}
+}
+
+// headers checks that the custom headers are named and not reserved
+func (v *validator) headers(headers []Header) {
+	for i, header := range headers {
+		name := textproto.CanonicalMIMEHeaderKey(strings.TrimSpace(header.Name))
+		switch {
+		case name == "":
+			v.add(fmt.Sprintf("Headers[%d].Name", i), ErrInvalidEmailRequest, "is required")
+		case slices.Contains(reservedHeaders(), name):
+			v.add(fmt.Sprintf("Headers[%d].Name", i), ErrInvalidEmailRequest, "%q is reserved, use the dedicated field instead", header.Name)
+		}
+	}
+}
+
+// attachments checks the attachments and returns their decoded size
+func (v *validator) attachments(attachments []Attachment) int {
+	size := 0
+	for i, attachment := range attachments {
+		if attachment.Name == "" {
+			v.add(fmt.Sprintf("Attachments[%d].Name", i), ErrInvalidEmailRequest, "is required")
+		}
+		if attachment.ContentType == "" {
+			v.add(fmt.Sprintf("Attachments[%d].ContentType", i), ErrInvalidEmailRequest, "is required")
+		}
+		content, err := attachment.Decode()
+		if err != nil {
+			v.add(fmt.Sprintf("Attachments[%d].Content", i), ErrInvalidEmailRequest, "is not valid base64")
+			continue
+		}
+		size += len(content)
+	}
+	return size
+}
+
+// size checks the total size of a message
+func (v *validator) size(size int) {
+	if size > MaxMessageSize {
+		v.add("Attachments", ErrInvalidEmailRequest, "message size is %d bytes, the maximum is %d", size, MaxMessageSize)
+	}
+}
+
+// validate checks every field of the email
+func (e Email) validate(v *validator) {
+	v.from(e.From)
+	v.recipients(e.To, e.Cc, e.Bcc)
+	v.replyTo(e.ReplyTo)
+	if e.HTMLBody == "" && e.TextBody == "" {
+		v.add("TextBody", ErrInvalidEmailRequest, "either HtmlBody or TextBody is required")
+	}
+	v.trackLinks(e.TrackLinks)
+	v.headers(e.Headers)
+	v.size(len(e.Subject) + len(e.HTMLBody) + len(e.TextBody) + v.attachments(e.Attachments))
+}
+
+// Validate checks the email against Postmark's rules and limits without calling the API.
+// It returns an EmailValidationError listing every problem, or nil.
+func (e Email) Validate() error {
+	var v validator
+	e.validate(&v)
+	return v.err()
+}
+
+// validate checks every field of the templated email
+func (e TemplatedEmail) validate(v *validator) {
+	if e.TemplateID == 0 && e.TemplateAlias == "" {
+		v.add("TemplateId", ErrInvalidEmailRequest, "either TemplateId or TemplateAlias is required")
+	}
+	if err := validateTemplateAlias(e.TemplateAlias); err != nil {
+		v.add("TemplateAlias", err, "contains a line break")
+	}
+	v.from(e.From)
+	v.recipients(e.To, e.Cc, e.Bcc)
+	v.replyTo(e.ReplyTo)
+	v.trackLinks(e.TrackLinks)
+	v.headers(e.Headers)
+	v.size(v.attachments(e.Attachments))
+}
+
+// Validate checks the templated email against Postmark's rules and limits without calling the API.
+// The template itself is rendered by Postmark, so the body is not checked.
+// It returns an EmailValidationError listing every problem, or nil.
+func (e TemplatedEmail) Validate() error {
+	var v validator
+	e.validate(&v)
+	return v.err()
+}
+
+// validateBatch checks the number of messages of a batch and each of its messages
+func validateBatch[T any](emails []T, validate func(T, *validator)) error {
+	var v validator
+	if len(emails) > MaxBatchMessages {
+		v.add("Messages", ErrTooManyBatchMessages, "%d messages, the maximum is %d", len(emails), MaxBatchMessages)
+	}
+	for i, email := range emails {
+		v.prefix = fmt.Sprintf("[%d].", i)
+		validate(email, &v)
+	}
+	return v.err()
+}
+
+// ValidateEmailBatch checks a batch of emails, see Email.Validate
+func ValidateEmailBatch(emails []Email) error {
+	return validateBatch(emails, Email.validate)
+}
+
+// ValidateTemplatedEmailBatch checks a batch of templated emails, see TemplatedEmail.Validate
+func ValidateTemplatedEmailBatch(emails []TemplatedEmail) error {
+	return validateBatch(emails, TemplatedEmail.validate)
+}
@@ -0,0 +1,139 @@
+package postmark
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fieldErrors returns the fields of the problems of a validation error
+func fieldErrors(t *testing.T, err error) []string {
+	t.Helper()
+
+	var validationErr EmailValidationError
+	require.ErrorAs(t, err, &validationErr)
+
+	fields := make([]string, 0, len(validationErr.Errors))
+	for _, fieldErr := range validationErr.Errors {
+		fields = append(fields, fieldErr.Field)
+	}
+	return fields
+}
+
+func TestEmailValidate(t *testing.T) {
+	valid := Email{
+		From:     "Sender <sender@example.com>",
+		To:       "one@example.com, Two <two@example.com>",
+		ReplyTo:  "reply@example.com",
+		TextBody: "Hello",
+		Headers:  []Header{{Name: "X-Campaign", Value: "launch"}},
+		Attachments: []Attachment{{
+			Name:        "hello.txt",
+			Content:     base64.StdEncoding.EncodeToString([]byte("hello")),
+			ContentType: "text/plain",
+		}},
+	}
+	require.NoError(t, valid.Validate())
+
+	err := Email{
+		To:          "not an address",
+		Cc:          "cc@example.com",
+		ReplyTo:     "@",
+		TrackLinks:  "Always",
+		Headers:     []Header{{Name: "reply-to", Value: "x@example.com"}, {Value: "unnamed"}},
+		Attachments: []Attachment{{Content: "%%%"}},
+	}.Validate()
+
+	require.ErrorIs(t, err, ErrInvalidEmailRequest)
+	assert.Equal(t, []string{
+		"From", "To", "ReplyTo", "TextBody", "TrackLinks", "Headers[0].Name", "Headers[1].Name",
+		"Attachments[0].Name", "Attachments[0].ContentType", "Attachments[0].Content",
+	}, fieldErrors(t, err))
+	assert.Contains(t, err.Error(), `TrackLinks: "Always" is not one of None, HtmlAndText, HtmlOnly, TextOnly`)
+}
+
+func TestEmailValidateLimits(t *testing.T) {
+	recipients := make([]string, MaxRecipients)
+	for i := range recipients {
+		recipients[i] = fmt.Sprintf("user%d@example.com", i)
+	}
+
+	email := Email{
+		From:     "sender@example.com",
+		To:       strings.Join(recipients[:40], ","),
+		Bcc:      strings.Join(recipients[40:], ","),
+		TextBody: "Hello",
+	}
+	require.NoError(t, email.Validate())
+
+	email.Cc = "one-too-many@example.com"
+	email.Attachments = []Attachment{{
+		Name:        "large.bin",
+		Content:     base64.StdEncoding.EncodeToString(make([]byte, MaxMessageSize)),
+		ContentType: "application/octet-stream",
+	}}
+
+	err := email.Validate()
+	assert.Equal(t, []string{"To", "Attachments"}, fieldErrors(t, err))
+	assert.Contains(t, err.Error(), "51 recipients")
+}
+
+func TestTemplatedEmailValidate(t *testing.T) {
+	require.NoError(t, TemplatedEmail{TemplateAlias: "welcome", From: "sender@example.com", To: "to@example.com"}.Validate())
+
+	err := TemplatedEmail{TemplateAlias: "welcome\r\nBcc: x@example.com", From: "sender@example.com"}.Validate()
+	require.ErrorIs(t, err, ErrHeaderInjection)
+	assert.Equal(t, []string{"TemplateAlias", "To"}, fieldErrors(t, err))
+
+	err = TemplatedEmail{From: "sender@example.com", To: "to@example.com"}.Validate()
+	assert.Equal(t, []string{"TemplateId"}, fieldErrors(t, err))
+}
+
+func TestValidateBatch(t *testing.T) {
+	emails := make([]Email, MaxBatchMessages+1)
+	for i := range emails {
+		emails[i] = Email{From: "sender@example.com", To: "to@example.com", TextBody: "Hello"}
+	}
+	emails[3].From = ""
+
+	err := ValidateEmailBatch(emails)
+	require.ErrorIs(t, err, ErrTooManyBatchMessages)
+	require.ErrorIs(t, err, ErrInvalidEmailRequest)
+	assert.Equal(t, []string{"Messages", "[3].From"}, fieldErrors(t, err))
+
+	require.NoError(t, ValidateTemplatedEmailBatch([]TemplatedEmail{{TemplateID: 1, From: "sender@example.com", To: "to@example.com"}}))
+}
+
+func (s *PostmarkTestSuite) TestValidateEmailsSkipsInvalidSends() {
+	calls := 0
+	s.mux.Post("/email", func(w http.ResponseWriter, _ *http.Request) {
+		calls++
+		_, _ = w.Write([]byte(`{"To": "receiver@example.com", "MessageID": "0a129aee-e1cd-480d-b08d-4f48548ff48d", "ErrorCode": 0, "Message": "OK"}`))
+	})
+	s.mux.Post("/email/withTemplate", func(_ http.ResponseWriter, _ *http.Request) {
+		calls++
+	})
+
+	client := s.newClient()
+	client.ValidateEmails = true
+
+	_, err := client.SendEmail(context.Background(), Email{From: testSenderEmail, To: "receiver@example.com"})
+	s.Require().NotErrorIs(err, ErrEmailFailed, "validation errors are found before sending")
+	s.Require().ErrorIs(err, ErrInvalidEmailRequest)
+
+	_, err = client.SendTemplatedEmail(context.Background(), TemplatedEmail{TemplateID: 1, From: testSenderEmail})
+	var validationErr EmailValidationError
+	s.Require().True(errors.As(err, &validationErr))
+	s.Equal(0, calls)
+
+	_, err = client.SendEmail(context.Background(), Email{From: testSenderEmail, To: "receiver@example.com", TextBody: "Hello"})
+	s.Require().NoError(err)
+	s.Equal(1, calls)
+}
@@ -83,14 +83,14 @@ func (client *Client) ListWebhooks(ctx context.Context, messageStream string) ([
 		options["MessageStream"] = messageStream
 	}
 
-	err := client.get(ctx, buildURL("webhooks", options), &res)
+	err := client.get(ctx, "ListWebhooks", buildURL("webhooks", options), &res)
 	return res.Webhooks, err
 }
 
 // GetWebhook retrieves a specific webhook by the webhook's ID.
 func (client *Client) GetWebhook(ctx context.Context, id int) (Webhook, error) {
 	var res Webhook
-	err := client.get(ctx, fmt.Sprintf("webhooks/%d", id), &res)
+	err := client.get(ctx, "GetWebhook", fmt.Sprintf("webhooks/%d", id), &res)
 	return res, err
 }
 
@@ -98,7 +98,7 @@ func (client *Client) GetWebhook(ctx context.Context, id int) (Webhook, error) {
 // returned webhook if successful will include the ID of the created webhook.
 func (client *Client) CreateWebhook(ctx context.Context, webhook Webhook) (Webhook, error) {
 	var res Webhook
-	err := client.post(ctx, "webhooks", webhook, &res)
+	err := client.post(ctx, "CreateWebhook", "webhooks", webhook, &res)
 	return res, err
 }
 
@@ -106,19 +106,11 @@ func (client *Client) CreateWebhook(ctx context.Context, webhook Webhook) (Webho
 // returned webhook if successful will be the resulting state of after the edit.
 func (client *Client) EditWebhook(ctx context.Context, id int, webhook Webhook) (Webhook, error) {
 	var res Webhook
-	err := client.put(ctx, fmt.Sprintf("webhooks/%d", id), webhook, &res)
+	err := client.put(ctx, "EditWebhook", fmt.Sprintf("webhooks/%d", id), webhook, &res)
 	return res, err
 }
 
 // DeleteWebhook removes a webhook from the server.
 func (client *Client) DeleteWebhook(ctx context.Context, id int) error {
-	res := APIError{}
-	err := client.delete(ctx, fmt.Sprintf("webhooks/%d", id), &res)
-	if err != nil {
-		return err
-	}
-	if res.ErrorCode != 0 {
-		return res
-	}
-	return nil
+	return client.delete(ctx, "DeleteWebhook", fmt.Sprintf("webhooks/%d", id), &APIError{})
 }
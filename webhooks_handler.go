@@ -0,0 +1,193 @@
+package postmark
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// defaultWebhookMaxBodyBytes limits the size of webhook payloads when no limit is configured.
+// Bounce and inbound payloads can include the full message content, so this is generous.
+const defaultWebhookMaxBodyBytes = 50 << 20
+
+var (
+	// ErrInvalidWebhookPayload is returned when a webhook payload cannot be decoded
+	ErrInvalidWebhookPayload = errors.New("invalid webhook payload")
+
+	// ErrWebhookUnauthorized is returned when a webhook request does not carry the configured credentials
+	ErrWebhookUnauthorized = errors.New("webhook request is not authorized")
+)
+
+// WebhookHandler is an http.Handler that receives Postmark webhook POSTs, decodes each event by its
+// RecordType and dispatches it to the matching callback.
+//
+// It responds with 200 when the event was handled (or has no callback), 400 when the payload is
+// invalid, 401 when the credentials are wrong and 500 when a callback returns an error, so that
+// Postmark retries the delivery later.
+type WebhookHandler struct {
+	// HTTPAuth: basic auth credentials every request must carry (optional)
+	HTTPAuth *WebhookHTTPAuth
+	// HTTPHeaders: custom headers every request must carry (optional)
+	HTTPHeaders []Header
+	// MaxBodyBytes: largest payload accepted, defaults to 50 MB
+	MaxBodyBytes int64
+
+	// OnDelivery is called for Delivery events
+	OnDelivery func(ctx context.Context, event DeliveryEvent) error
+	// OnBounce is called for Bounce events
+	OnBounce func(ctx context.Context, event BounceEvent) error
+	// OnOpen is called for Open events
+	OnOpen func(ctx context.Context, event OpenEvent) error
+	// OnClick is called for Click events
+	OnClick func(ctx context.Context, event ClickEvent) error
+	// OnSpamComplaint is called for SpamComplaint events
+	OnSpamComplaint func(ctx context.Context, event SpamComplaintEvent) error
+	// OnSubscriptionChange is called for SubscriptionChange events
+	OnSubscriptionChange func(ctx context.Context, event SubscriptionChangeEvent) error
+
+	// OnError is called whenever a request is rejected or a callback fails (optional)
+	OnError func(r *http.Request, status int, err error)
+}
+
+// NewWebhookHandler creates a WebhookHandler that checks the HTTPAuth and HTTPHeaders of the webhook configuration.
+// HTTPAuth with an empty username and password means no basic auth is configured.
+func NewWebhookHandler(webhook Webhook) *WebhookHandler {
+	auth := webhook.HTTPAuth
+	if auth != nil && auth.Username == "" && auth.Password == "" {
+		auth = nil
+	}
+	return &WebhookHandler{
+		HTTPAuth:    auth,
+		HTTPHeaders: webhook.HTTPHeaders,
+	}
+}
+
+// ServeHTTP implements http.Handler interface.
+func (h *WebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, status, err := readWebhook(w, r, h.HTTPAuth, h.HTTPHeaders, h.MaxBodyBytes)
+	if err != nil {
+		h.fail(w, r, status, err)
+		return
+	}
+
+	var base BaseEvent
+	if err = json.Unmarshal(body, &base); err != nil {
+		h.fail(w, r, http.StatusBadRequest, fmt.Errorf("%w: %w", ErrInvalidWebhookPayload, err))
+		return
+	}
+
+	if err = h.dispatch(r.Context(), base.RecordType, body); err != nil {
+		status = http.StatusInternalServerError
+		if errors.Is(err, ErrInvalidWebhookPayload) {
+			status = http.StatusBadRequest
+		}
+		h.fail(w, r, status, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// dispatch decodes the event and calls the callback registered for its record type
+func (h *WebhookHandler) dispatch(ctx context.Context, recordType string, body []byte) error {
+	switch recordType {
+	case RecordTypeDelivery:
+		return handleWebhookEvent(ctx, body, h.OnDelivery)
+	case RecordTypeBounce:
+		return handleWebhookEvent(ctx, body, h.OnBounce)
+	case RecordTypeOpen:
+		return handleWebhookEvent(ctx, body, h.OnOpen)
+	case RecordTypeClick:
+		return handleWebhookEvent(ctx, body, h.OnClick)
+	case RecordTypeSpamComplaint:
+		return handleWebhookEvent(ctx, body, h.OnSpamComplaint)
+	case RecordTypeSubscriptionChange:
+		return handleWebhookEvent(ctx, body, h.OnSubscriptionChange)
+	default:
+		// Unknown record types are acknowledged, otherwise Postmark would keep retrying them
+		return nil
+	}
+}
+
+// fail reports the error and writes the status code
+func (h *WebhookHandler) fail(w http.ResponseWriter, r *http.Request, status int, err error) {
+	if h.OnError != nil {
+		h.OnError(r, status, err)
+	}
+	writeWebhookError(w, status)
+}
+
+// handleWebhookEvent decodes the payload into T and passes it to the callback, if there is one
+func handleWebhookEvent[T any](ctx context.Context, body []byte, callback func(context.Context, T) error) error {
+	if callback == nil {
+		return nil
+	}
+
+	var event T
+	if err := json.Unmarshal(body, &event); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidWebhookPayload, err)
+	}
+	return callback(ctx, event)
+}
+
+// readWebhook checks the method and credentials of a webhook request and reads its body.
+// On failure it returns the HTTP status code to respond with.
+func readWebhook(w http.ResponseWriter, r *http.Request, auth *WebhookHTTPAuth, headers []Header, maxBytes int64) ([]byte, int, error) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		return nil, http.StatusMethodNotAllowed, fmt.Errorf("%w: method %s", ErrInvalidWebhookPayload, r.Method)
+	}
+
+	if !authorizedWebhook(r, auth, headers) {
+		if auth != nil {
+			w.Header().Set("WWW-Authenticate", `Basic realm="postmark"`)
+		}
+		return nil, http.StatusUnauthorized, ErrWebhookUnauthorized
+	}
+
+	if maxBytes <= 0 {
+		maxBytes = defaultWebhookMaxBodyBytes
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBytes))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return nil, http.StatusRequestEntityTooLarge, fmt.Errorf("%w: %w", ErrInvalidWebhookPayload, err)
+		}
+		return nil, http.StatusBadRequest, fmt.Errorf("%w: %w", ErrInvalidWebhookPayload, err)
+	}
+	return body, 0, nil
+}
+
+// authorizedWebhook reports whether the request carries the expected basic auth credentials and headers.
+// Values are compared in constant time.
+func authorizedWebhook(r *http.Request, auth *WebhookHTTPAuth, headers []Header) bool {
+	if auth != nil {
+		username, password, ok := r.BasicAuth()
+		if !ok || !secureEqual(username, auth.Username) || !secureEqual(password, auth.Password) {
+			return false
+		}
+	}
+
+	for _, header := range headers {
+		if !secureEqual(r.Header.Get(header.Name), header.Value) {
+			return false
+		}
+	}
+	return true
+}
+
+// secureEqual compares two strings in constant time
+func secureEqual(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
+
+// writeWebhookError responds with the status code and its standard text
+func writeWebhookError(w http.ResponseWriter, status int) {
+	http.Error(w, http.StatusText(status), status)
+}
@@ -0,0 +1,210 @@
+package postmark
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errTestCallback = errors.New("callback failed")
+
+func newTestWebhookRequest(method, body string) *http.Request {
+	req := httptest.NewRequest(method, "/webhooks/postmark", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	return req
+}
+
+func TestWebhookHandlerDispatch(t *testing.T) {
+	var got []string
+
+	handler := &WebhookHandler{
+		OnDelivery: func(_ context.Context, event DeliveryEvent) error {
+			got = append(got, "delivery:"+event.Recipient)
+			return nil
+		},
+		OnBounce: func(_ context.Context, event BounceEvent) error {
+			got = append(got, "bounce:"+event.Email)
+			return nil
+		},
+		OnOpen: func(_ context.Context, event OpenEvent) error {
+			got = append(got, "open:"+event.Recipient)
+			return nil
+		},
+		OnClick: func(_ context.Context, event ClickEvent) error {
+			got = append(got, "click:"+event.OriginalLink)
+			return nil
+		},
+		OnSpamComplaint: func(_ context.Context, event SpamComplaintEvent) error {
+			got = append(got, "spam:"+event.Email)
+			return nil
+		},
+		OnSubscriptionChange: func(_ context.Context, event SubscriptionChangeEvent) error {
+			got = append(got, "subscription:"+event.Recipient)
+			return nil
+		},
+	}
+
+	payloads := []string{
+		`{"RecordType": "Delivery", "Recipient": "a@example.com", "DeliveredAt": "2014-04-01T13:42:10Z"}`,
+		`{"RecordType": "Bounce", "Email": "b@example.com", "ID": 42}`,
+		`{"RecordType": "Open", "Recipient": "c@example.com", "FirstOpen": true}`,
+		`{"RecordType": "Click", "OriginalLink": "https://example.com"}`,
+		`{"RecordType": "SpamComplaint", "Email": "d@example.com"}`,
+		`{"RecordType": "SubscriptionChange", "Recipient": "e@example.com", "SuppressSending": true}`,
+		`{"RecordType": "SomethingNew"}`,
+	}
+
+	for _, payload := range payloads {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, newTestWebhookRequest(http.MethodPost, payload))
+		assert.Equal(t, http.StatusOK, rec.Code, payload)
+	}
+
+	assert.Equal(t, []string{
+		"delivery:a@example.com",
+		"bounce:b@example.com",
+		"open:c@example.com",
+		"click:https://example.com",
+		"spam:d@example.com",
+		"subscription:e@example.com",
+	}, got)
+}
+
+func TestWebhookHandlerStatusCodes(t *testing.T) {
+	auth := &WebhookHTTPAuth{Username: "user", Password: "pass"}
+	headers := []Header{{Name: "X-Webhook-Secret", Value: "s3cret"}}
+
+	tests := []struct {
+		name       string
+		method     string
+		body       string
+		username   string
+		password   string
+		secret     string
+		callback   error
+		wantStatus int
+	}{
+		{
+			name:       "authorized delivery",
+			method:     http.MethodPost,
+			body:       `{"RecordType": "Delivery"}`,
+			username:   "user",
+			password:   "pass",
+			secret:     "s3cret",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "wrong password",
+			method:     http.MethodPost,
+			body:       `{"RecordType": "Delivery"}`,
+			username:   "user",
+			password:   "nope",
+			secret:     "s3cret",
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "missing custom header",
+			method:     http.MethodPost,
+			body:       `{"RecordType": "Delivery"}`,
+			username:   "user",
+			password:   "pass",
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "wrong method",
+			method:     http.MethodGet,
+			username:   "user",
+			password:   "pass",
+			secret:     "s3cret",
+			wantStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			name:       "invalid json",
+			method:     http.MethodPost,
+			body:       `{not json`,
+			username:   "user",
+			password:   "pass",
+			secret:     "s3cret",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "invalid event fields",
+			method:     http.MethodPost,
+			body:       `{"RecordType": "Delivery", "ServerID": "not a number"}`,
+			username:   "user",
+			password:   "pass",
+			secret:     "s3cret",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "callback failure asks for a retry",
+			method:     http.MethodPost,
+			body:       `{"RecordType": "Delivery"}`,
+			username:   "user",
+			password:   "pass",
+			secret:     "s3cret",
+			callback:   errTestCallback,
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var reported error
+			handler := NewWebhookHandler(Webhook{HTTPAuth: auth, HTTPHeaders: headers})
+			handler.OnDelivery = func(context.Context, DeliveryEvent) error { return tt.callback }
+			handler.OnError = func(_ *http.Request, status int, err error) {
+				assert.Equal(t, tt.wantStatus, status)
+				reported = err
+			}
+
+			req := newTestWebhookRequest(tt.method, tt.body)
+			req.SetBasicAuth(tt.username, tt.password)
+			if tt.secret != "" {
+				req.Header.Set("X-Webhook-Secret", tt.secret)
+			}
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			if tt.wantStatus == http.StatusOK {
+				require.NoError(t, reported)
+			} else {
+				require.Error(t, reported)
+			}
+			if tt.wantStatus == http.StatusUnauthorized {
+				require.ErrorIs(t, reported, ErrWebhookUnauthorized)
+				assert.NotEmpty(t, rec.Header().Get("WWW-Authenticate"))
+			}
+			if tt.callback != nil {
+				require.ErrorIs(t, reported, tt.callback)
+			}
+		})
+	}
+}
+
+func TestWebhookHandlerEmptyHTTPAuth(t *testing.T) {
+	handler := NewWebhookHandler(Webhook{HTTPAuth: &WebhookHTTPAuth{}})
+	assert.Nil(t, handler.HTTPAuth)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, newTestWebhookRequest(http.MethodPost, `{"RecordType": "Delivery", "Recipient": "a@example.com"}`))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestWebhookHandlerBodyTooLarge(t *testing.T) {
+	handler := &WebhookHandler{MaxBodyBytes: 16}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, newTestWebhookRequest(http.MethodPost, `{"RecordType": "Delivery", "Recipient": "a@example.com"}`))
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+}